@@ -2,30 +2,81 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"flag"
 	"fmt"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/viper"
 	"movie-lib/internal/app"
+	"movie-lib/internal/auth"
 	"movie-lib/internal/ports/httpserver"
 	"movie-lib/internal/repo"
 	"movie-lib/pkg/logger"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 )
 
+// SetConfigs reads the config file, every parameter can be overridden by
+// the environment variable with the prefix MOVIE_LIB_, for example
+// auth.hmac-secret by MOVIE_LIB_AUTH_HMAC_SECRET
 func SetConfigs(configPath string) error {
 	viper.SetConfigFile(configPath)
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
 	if err := viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("cannot read config file %w", err)
 	}
 	return nil
 }
 
-func ConnectToPostgres(ctx context.Context) (*pgx.Conn, error) {
+// ReadSecret returns the secret parameter, it is read from the file named by
+// the parameter with the suffix "-file" if it is set
+func ReadSecret(key string) (string, error) {
+	path := viper.GetString(key + "-file")
+	if path == "" {
+		return viper.GetString(key), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", key+"-file", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// CheckSecret rejects empty secrets and placeholders from examples, the
+// service must not start with credentials known to everyone
+func CheckSecret(key, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is not set, set it by %s or %s", key, envName(key), envName(key+"-file"))
+	}
+	if slices.Contains(placeholderSecrets, strings.ToLower(value)) {
+		return fmt.Errorf("%s is a placeholder, replace it with a random value", key)
+	}
+	return nil
+}
+
+// ReadAdminPassword reads and checks the password of the default admin, it is
+// called only when the admin has no password yet
+func ReadAdminPassword() (string, error) {
+	password, err := ReadSecret("auth.admin-password")
+	if err != nil {
+		return "", err
+	}
+	return password, CheckSecret("auth.admin-password", password)
+}
+
+// envName returns the environment variable which overrides the parameter
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+func ConnectToPostgres(ctx context.Context) (*pgxpool.Pool, error) {
 	adsRepoUrl := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		viper.GetString("postgres-movie-lib.username"),
 		viper.GetString("postgres-movie-lib.password"),
@@ -34,29 +85,112 @@ func ConnectToPostgres(ctx context.Context) (*pgx.Conn, error) {
 		viper.GetString("postgres-movie-lib.dbname"),
 		viper.GetString("postgres-movie-lib.sslmode"))
 
+	poolConfig, err := pgxpool.ParseConfig(adsRepoUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing postgres url: %w", err)
+	}
+	// every connection works in UTC, the time zone is not set by SQL because
+	// the setting would stay on pooled connections
+	poolConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	if viper.IsSet("postgres-movie-lib.pool.min-conns") {
+		poolConfig.MinConns = viper.GetInt32("postgres-movie-lib.pool.min-conns")
+	}
+	if viper.IsSet("postgres-movie-lib.pool.max-conns") {
+		poolConfig.MaxConns = viper.GetInt32("postgres-movie-lib.pool.max-conns")
+	}
+	if viper.IsSet("postgres-movie-lib.pool.max-conn-lifetime") {
+		poolConfig.MaxConnLifetime = viper.GetDuration("postgres-movie-lib.pool.max-conn-lifetime")
+	}
+	if viper.IsSet("postgres-movie-lib.pool.max-conn-idle-time") {
+		poolConfig.MaxConnIdleTime = viper.GetDuration("postgres-movie-lib.pool.max-conn-idle-time")
+	}
+	if viper.IsSet("postgres-movie-lib.pool.health-check-period") {
+		poolConfig.HealthCheckPeriod = viper.GetDuration("postgres-movie-lib.pool.health-check-period")
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating postgres pool: %w", err)
+	}
+
 	// 30 attempts to connect to postgres when starting in docker container
 	for i := 0; i < 30; i++ {
-		conn, err := pgx.Connect(ctx, adsRepoUrl)
-		if err != nil {
+		if err = pool.Ping(ctx); err != nil {
 			time.Sleep(time.Second)
 		} else {
-			return conn, nil
+			return pool, nil
 		}
 	}
 
+	pool.Close()
 	return nil, errors.New("unable to connect to postgres ads repo")
 }
 
+// NewTokenIssuer creates the issuer of access tokens signed by HS256 with
+// the secret or by EdDSA with the base64 encoded seed of the key
+func NewTokenIssuer() (*auth.Issuer, error) {
+	method := viper.GetString("auth.signing-method")
+	var secret, encodedSeed string
+	var err error
+	if method == auth.EdDSA {
+		if encodedSeed, err = ReadSecret("auth.ed25519-seed"); err != nil {
+			return nil, err
+		}
+		if err = CheckSecret("auth.ed25519-seed", encodedSeed); err != nil {
+			return nil, err
+		}
+	} else {
+		if secret, err = ReadSecret("auth.hmac-secret"); err != nil {
+			return nil, err
+		}
+		if err = CheckSecret("auth.hmac-secret", secret); err != nil {
+			return nil, err
+		}
+	}
+	seed, err := base64.StdEncoding.DecodeString(encodedSeed)
+	if err != nil {
+		return nil, fmt.Errorf("decoding ed25519 seed: %w", err)
+	}
+	return auth.NewIssuer(auth.Config{
+		SigningMethod:   method,
+		HMACSecret:      []byte(secret),
+		Ed25519Seed:     seed,
+		Issuer:          viper.GetString("auth.issuer"),
+		AccessTokenTTL:  viper.GetDuration("auth.access-token-ttl"),
+		RefreshTokenTTL: viper.GetDuration("auth.refresh-token-ttl"),
+	})
+}
+
 const (
 	dockerConfigFile = "config/config-docker.yml"
 	localConfigFile  = "config/config-local.yml"
+
+	// envPrefix is the prefix of environment variables overriding configs
+	envPrefix = "MOVIE_LIB"
+
+	postgresBackend = "postgres"
+	memoryBackend   = "memory"
 )
 
+// placeholderSecrets are secrets and passwords from examples and earlier
+// versions of the configs in lower case
+var placeholderSecrets = []string{
+	"change-me",
+	"changeme",
+	"change-me-to-a-random-secret-of-32-bytes",
+	"secret",
+	"password",
+	"admin",
+}
+
 //	@title						movie-lib
 //	@version					1.0
 //	@description				Swagger документация к API фильмотеки
 //	@host						localhost:8080
 //	@BasePath					/api/v1
+//	@SecurityDefinitions.apikey	ServiceKeyAuth
+//	@in							header
+//	@name						X-API-Key
 //	@SecurityDefinitions.apikey	ApiKeyAuth
 //	@in							header
 //	@name						Authorization
@@ -78,14 +212,52 @@ func main() {
 		logs.FatalLog(fmt.Sprintf("reading configs: %s", err.Error()))
 	}
 
-	conn, err := ConnectToPostgres(ctx)
-	if err != nil {
-		logs.FatalLog(fmt.Sprintf("connecting to postgres: %s", err.Error()))
+	if command := flag.Arg(0); command == "migrate" {
+		pool, err := ConnectToPostgres(ctx)
+		if err != nil {
+			logs.FatalLog(fmt.Sprintf("connecting to postgres: %s", err.Error()))
+		}
+		err = RunMigrate(ctx, pool, logs, flag.Args()[1:])
+		pool.Close()
+		if err != nil {
+			logs.FatalLog(fmt.Sprintf("migrating: %s", err.Error()))
+		}
+		return
+	} else if command != "" {
+		logs.FatalLog(fmt.Sprintf("unknown command %q", command))
+	}
+
+	var r repo.Repo
+	switch backend := viper.GetString("repo.backend"); backend {
+	case memoryBackend:
+		r = repo.NewMemory()
+		logs.InfoLog("using in-memory repository, all data will be lost after stop")
+	case postgresBackend, "":
+		pool, err := ConnectToPostgres(ctx)
+		if err != nil {
+			logs.FatalLog(fmt.Sprintf("connecting to postgres: %s", err.Error()))
+		}
+		defer pool.Close()
+		logs.InfoLog("successfully connected to postgres")
+		if viper.GetBool("postgres-movie-lib.migrate-on-start") {
+			if err = MigrateOnStart(ctx, pool, logs); err != nil {
+				logs.FatalLog(fmt.Sprintf("migrating: %s", err.Error()))
+			}
+		}
+		r = repo.New(pool)
+	default:
+		logs.FatalLog(fmt.Sprintf("unknown repo backend %q", backend))
 	}
-	logs.InfoLog("successfully connected to postgres")
 
-	r := repo.New(conn)
-	a := app.New(r, logs)
+	tokens, err := NewTokenIssuer()
+	if err != nil {
+		logs.FatalLog(fmt.Sprintf("creating token issuer: %s", err.Error()))
+	}
+	a := app.New(r, logs, tokens)
+	// the admin password is required only until the admin has one
+	if err = a.InitAdminPassword(ctx, ReadAdminPassword); err != nil {
+		logs.FatalLog(fmt.Sprintf("setting admin password: %s", err.Error()))
+	}
 
 	host := viper.GetString("http-server.host")
 	port := viper.GetInt("http-server.port")
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"movie-lib/migrations"
+	"movie-lib/pkg/logger"
+	"movie-lib/pkg/migrator"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate up | down N | status | force VERSION"
+
+// MigrateOnStart applies all not applied migrations if it is enabled in configs
+func MigrateOnStart(ctx context.Context, pool *pgxpool.Pool, logs logger.Logger) error {
+	m, err := migrator.New(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+	applied, err := m.Up(ctx)
+	for _, migration := range applied {
+		logs.InfoLog(fmt.Sprintf("applied migration %d_%s", migration.Version, migration.Name))
+	}
+	return err
+}
+
+// RunMigrate executes migrate subcommand with given args
+func RunMigrate(ctx context.Context, pool *pgxpool.Pool, logs logger.Logger, args []string) error {
+	m, err := migrator.New(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			logs.InfoLog(fmt.Sprintf("applied migration %d_%s", migration.Version, migration.Name))
+		}
+		if err == nil && len(applied) == 0 {
+			logs.InfoLog("no migrations to apply")
+		}
+		return err
+
+	case "down":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of migrations %q", args[1])
+		}
+		rolledBack, err := m.Down(ctx, n)
+		for _, migration := range rolledBack {
+			logs.InfoLog(fmt.Sprintf("rolled back migration %d_%s", migration.Version, migration.Name))
+		}
+		return err
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				logs.InfoLog(fmt.Sprintf("%d_%s applied at %s", s.Version, s.Name, s.AppliedAt.UTC().Format("2006-01-02 15:04:05")))
+			} else {
+				logs.InfoLog(fmt.Sprintf("%d_%s pending", s.Version, s.Name))
+			}
+		}
+		return nil
+
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err = m.Force(ctx, version); err != nil {
+			return err
+		}
+		logs.InfoLog(fmt.Sprintf("forced version %d", version))
+		return nil
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
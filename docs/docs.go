@@ -20,6 +20,9 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
                     }
                 ],
                 "description": "Возвращает актёра с указанным id",
@@ -82,6 +85,9 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
                     }
                 ],
                 "description": "Обновляет поля актёра по id",
@@ -156,9 +162,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
                     }
                 ],
-                "description": "Добавляет нового актёра",
+                "description": "Добавляет нового актёра или члена съёмочной группы, который не снимался как актёр",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,6 +226,9 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
                     }
                 ],
                 "description": "Удаляет актёра по id",
@@ -281,9 +293,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
                     }
                 ],
-                "description": "Возвращает список актёров",
+                "description": "Возвращает страницу списка актёров, удовлетворяющих фильтрам, с их фильмами и общее количество таких актёров",
                 "produces": [
                     "application/json"
                 ],
@@ -291,6 +306,44 @@ const docTemplate = `{
                     "actors"
                 ],
                 "summary": "Получение списка актёров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поиск по имени и фамилии актёра без учёта регистра, каждое слово должно содержаться в имени или фамилии",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Пол актёра (male/female)",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Актёры, игравшие в фильме с указанным id",
+                        "name": "movie_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Параметры сортировки через запятую, минус перед параметром означает сортировку по убыванию, например -movies_count,name. Поддерживаемые параметры: name (фамилия и имя), movies_count (количество фильмов). По умолчанию актёры упорядочены по id",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество актёров на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Информация об актёрах",
@@ -298,6 +351,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/httpserver.actorListResponse"
                         }
                     },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.actorListResponse"
+                        }
+                    },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
@@ -319,14 +378,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/movies/": {
-            "get": {
+        "/api-keys/": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает фильм с указанным id",
+                "description": "Создаёт долгоживущий API-ключ текущего пользователя для сервисов. Ключ передаётся в заголовке X-API-Key и возвращается только один раз. Ключи создаются только по токену доступа",
                 "consumes": [
                     "application/json"
                 ],
@@ -334,462 +393,4508 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "api-keys"
                 ],
-                "summary": "Получение фильма по id",
+                "summary": "Создание API-ключа",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id фильма",
-                        "name": "movie_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Название, область действия и время истечения ключа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createApiKeyData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пустая структура",
+                        "description": "Информация о ключе вместе с самим ключом",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "400": {
                         "description": "Неверный формат входных данных",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "403": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Фильма не существует",
-                        "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "500": {
                         "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновляет поля фильма по id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Удаляет API-ключ, после этого он перестаёт приниматься. Пользователи с разрешением users:manage могут отозвать ключ любого пользователя, остальные только свой",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "api-keys"
                 ],
-                "summary": "Обновление фильма",
+                "summary": "Отзыв API-ключа",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id фильма",
-                        "name": "movie_id",
+                        "description": "id ключа",
+                        "name": "key_id",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Новые поля",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpserver.updateMovieData"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о фильме",
+                        "description": "Пустая структура",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "400": {
                         "description": "Неверный формат входных данных",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "403": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "404": {
-                        "description": "Фильма либо актёра из списка не существует",
+                        "description": "Ключа не существует",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     },
                     "500": {
                         "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api-keys/list/": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Добавляет новый фильм",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает API-ключи пользователя, упорядоченные по id, включая истёкшие. Сами ключи не возвращаются, только их начало. Пользователи с разрешением users:manage могут получить ключи любого пользователя, остальные только свои",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "api-keys"
                 ],
-                "summary": "Добавление фильма",
+                "summary": "Получение списка API-ключей",
                 "parameters": [
                     {
-                        "description": "Информация о новом фильме",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/httpserver.createMovieData"
-                        }
+                        "type": "string",
+                        "description": "id пользователя, по умолчанию текущий пользователь",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о фильме",
+                        "description": "Информация о ключах",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyListResponse"
                         }
                     },
                     "400": {
                         "description": "Неверный формат входных данных",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyListResponse"
                         }
                     },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyListResponse"
                         }
                     },
                     "403": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyListResponse"
                         }
                     },
-                    "404": {
-                        "description": "Актёра из списка не существует",
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.apiKeyListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login/": {
+            "post": {
+                "description": "Проверяет имя пользователя и пароль и начинает новую сессию. Возвращает короткоживущий токен доступа и одноразовый токен обновления",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Вход",
+                "parameters": [
+                    {
+                        "description": "Имя пользователя и пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.loginData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Токены сессии",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.tokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.tokensResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверное имя пользователя или пароль",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     },
                     "500": {
                         "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/logout/": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Удаление фильма по id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Отзывает текущую сессию или все сессии пользователя, их токены доступа и обновления перестают приниматься",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "auth"
                 ],
-                "summary": "Удаление фильма",
+                "summary": "Выход",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id фильма",
-                        "name": "movie_id",
-                        "in": "query",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Завершить все сессии пользователя",
+                        "name": "all",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Пустая структура",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     },
                     "400": {
                         "description": "Неверный формат входных данных",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     },
                     "403": {
-                        "description": "Ошибка авторизации",
+                        "description": "Запрос авторизован API-ключом",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     },
-                    "404": {
-                        "description": "Фильма не существует",
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh/": {
+            "post": {
+                "description": "Обменивает токен обновления на новую пару токенов той же сессии и продлевает сессию. Каждый токен обновления можно использовать только один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление токенов",
+                "parameters": [
+                    {
+                        "description": "Токен обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.refreshData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новые токены сессии",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.tokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.tokensResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Токен недействителен, истёк или отозван",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     },
                     "500": {
                         "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieResponse"
+                            "$ref": "#/definitions/httpserver.tokensResponse"
                         }
                     }
                 }
             }
         },
-        "/movies/list/": {
+        "/collections/": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
                     }
                 ],
-                "description": "Возвращает список фильмов",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает подборку с фильмами по порядку и заметками к ним. Подборка доступна владельцу, всем пользователям, если она публичная, и пользователям, которым она открыта",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "collections"
                 ],
-                "summary": "Получение списка фильмов",
+                "summary": "Получение подборки",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Поиск по названию фильма/фамилии/имени актёра",
-                        "name": "pattern",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Параметр для сортировки. Поддерживаемые параметры: title, rating, release_date",
-                        "name": "sort_by",
-                        "in": "query"
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о фильмах",
+                        "description": "Информация о подборке",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieListResponse"
+                            "$ref": "#/definitions/httpserver.collectionResponse"
                         }
                     },
                     "400": {
                         "description": "Неверный формат входных данных",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieListResponse"
+                            "$ref": "#/definitions/httpserver.collectionResponse"
                         }
                     },
                     "401": {
                         "description": "Ошибка авторизации",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieListResponse"
+                            "$ref": "#/definitions/httpserver.collectionResponse"
                         }
                     },
                     "403": {
-                        "description": "Ошибка авторизации",
+                        "description": "Подборка недоступна пользователю",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieListResponse"
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
                         }
                     },
                     "500": {
                         "description": "Проблемы на стороне сервера",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.movieListResponse"
+                            "$ref": "#/definitions/httpserver.collectionResponse"
                         }
                     }
                 }
-            }
-        }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет название, описание, видимость подборки и список пользователей, которым она доступна, изменить подборку может только её владелец",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Обновление подборки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateCollectionData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт именованную подборку фильмов, владельцем которой становится пользователь. Подборка по умолчанию приватная, её можно открыть всем пользователям или отдельным пользователям только для чтения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Создание подборки",
+                "parameters": [
+                    {
+                        "description": "Информация о новой подборке",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createCollectionData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет подборку по id, владелец может удалить свою подборку, а пользователь с разрешением collections:moderate любую",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Удаление подборки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/entries/": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет заметку владельца подборки к фильму",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Изменение заметки к фильму подборки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая заметка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateCollectionEntryData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки не существует или фильма нет в подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет фильм с заметкой в конец подборки, изменять фильмы подборки может только её владелец",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Добавление фильма в подборку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Фильм и заметка к нему",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.addCollectionEntryData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки или фильма не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Фильм уже есть в подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет фильм из подборки, порядок остальных фильмов сохраняется",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Удаление фильма из подборки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки не существует или фильма нет в подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу списка доступных пользователю подборок без фильмов: своих, публичных и открытых ему, и общее количество подходящих подборок",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Получение списка подборок",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Подборки пользователя с указанным id",
+                        "name": "owner_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество подборок на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о подборках",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/order/": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Расставляет фильмы подборки в указанном порядке, список должен содержать каждый фильм подборки ровно один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Изменение порядка фильмов подборки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id подборки",
+                        "name": "collection_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый порядок фильмов",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reorderCollectionData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о подборке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подборки не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.collectionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает жанр с указанным id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получение жанра",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id жанра",
+                        "name": "genre_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о жанре",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанра не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Переименовывает жанр по id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Обновление жанра",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id жанра",
+                        "name": "genre_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateGenreData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о жанре",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанра не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет новый жанр",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Добавление жанра",
+                "parameters": [
+                    {
+                        "description": "Информация о новом жанре",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createGenreData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о жанре",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет жанр по id, фильмы жанра остаются без него",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Удаление жанра",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id жанра",
+                        "name": "genre_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанра не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все жанры, упорядоченные по названию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получение списка жанров",
+                "responses": {
+                    "200": {
+                        "description": "Информация о жанрах",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.genreListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает фильм с указанным id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Получение фильма по id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет поля фильма по id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Обновление фильма",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateMovieData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильме",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма либо актёра, жанра или члена съёмочной группы из списка не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт с существующими данными",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет новый фильм",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Добавление фильма",
+                "parameters": [
+                    {
+                        "description": "Информация о новом фильме",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createMovieData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильме",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Актёра, жанра или члена съёмочной группы из списка не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт с существующими данными",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаление фильма по id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Удаление фильма",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу списка фильмов, удовлетворяющих фильтрам, и общее количество таких фильмов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Получение списка фильмов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию, описанию фильма и именам актёров. Поддерживаются or и исключение слов через минус",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Параметры сортировки через запятую, минус перед параметром означает сортировку по убыванию, например -rating,title. Поддерживаемые параметры: title, rating, release_date, а при поиске по pattern также relevance. По умолчанию -rating, при поиске -relevance",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная дата выхода (timestamp)",
+                        "name": "release_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная дата выхода (timestamp)",
+                        "name": "release_date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальный рейтинг",
+                        "name": "rating_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальный рейтинг",
+                        "name": "rating_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id актёров через запятую",
+                        "name": "actors",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильмы с любым (any, по умолчанию) или со всеми (all) актёрами из actors",
+                        "name": "actors_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильмы, в которых играл актёр указанного пола (male/female)",
+                        "name": "actor_gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id жанров через запятую",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильмы с любым (any, по умолчанию) или со всеми (all) жанрами из genres",
+                        "name": "genres_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Фильмы, которые есть (true) или которых нет (false) в списке «Буду смотреть» пользователя",
+                        "name": "watchlist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Фильмы, которые пользователь посмотрел (true) или не посмотрел (false)",
+                        "name": "watched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество фильмов на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильмах",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/persons/filmography/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает актёра или члена съёмочной группы и все его фильмы, сгруппированные по ролям",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "persons"
+                ],
+                "summary": "Фильмография персоны",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id персоны (совпадает с id актёра)",
+                        "name": "person_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Фильмография",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.filmographyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.filmographyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.filmographyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.filmographyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Персоны не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.filmographyResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.filmographyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает отзыв с указанным id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Получение отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id отзыва",
+                        "name": "review_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация об отзыве",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Отзыва не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет оценку и текст отзыва по id, изменить отзыв может только его автор",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Обновление отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id отзыва",
+                        "name": "review_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateReviewData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация об отзыве",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Отзыва не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет оценку фильма от 1 до 10 и текст отзыва от имени пользователя, каждый пользователь может оставить только один отзыв на фильм",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Добавление отзыва",
+                "parameters": [
+                    {
+                        "description": "Информация о новом отзыве",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createReviewData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация об отзыве",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь уже оставил отзыв на фильм",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет отзыв по id, автор может удалить свой отзыв, а пользователь с разрешением reviews:moderate любой",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Удаление отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id отзыва",
+                        "name": "review_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Отзыва не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу списка отзывов, начиная с новых, и общее количество подходящих отзывов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Получение списка отзывов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Отзывы на фильм с указанным id",
+                        "name": "movie_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Отзывы пользователя с указанным id",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество отзывов на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация об отзывах",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.reviewListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает роль с её разрешениями",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Получение роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о роли",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Роли не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет разрешения роли, изменения сразу действуют для всех пользователей с этой ролью. Разрешения администраторов изменить нельзя, выдать роли можно только разрешения собственной роли",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Обновление роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые разрешения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateRoleData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о роли",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Роли не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Встроенную роль изменить нельзя",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет новую роль с разрешениями. Управлять ролями могут пользователи с разрешением roles:manage, выдать роли можно только разрешения собственной роли",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Добавление роли",
+                "parameters": [
+                    {
+                        "description": "Название и разрешения роли",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createRoleData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о роли",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Роль с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет роль, которая не назначена ни одному пользователю. Роли admin и regular удалить нельзя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Удаление роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название роли",
+                        "name": "role",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Роли не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Роль встроенная или назначена пользователям",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все роли с их разрешениями, упорядоченные по названию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Получение списка ролей",
+                "responses": {
+                    "200": {
+                        "description": "Информация о ролях",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.roleListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search/fuzzy/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает фильмы с похожими на запрос названиями и актёров с похожими именами, упорядоченные по убыванию похожести. Находит названия и имена с опечатками. Если полнотекстовый поиск фильмов по запросу ничего не находит, предлагает наиболее похожее название или имя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Нечёткий поиск",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество фильмов и актёров, по умолчанию 10, не больше 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные фильмы и актёры",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.fuzzySearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.fuzzySearchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.fuzzySearchResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.fuzzySearchResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.fuzzySearchResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/pool/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает статистику пула соединений с базой данных, доступно пользователям с разрешением stats:read",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Статистика пула соединений",
+                "responses": {
+                    "200": {
+                        "description": "Статистика пула соединений",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.poolStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.poolStatsResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.poolStatsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.poolStatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает фильмы, названия которых начинаются с введённого текста, и актёров, имя и фамилия или фамилия которых начинаются с него, без учёта регистра. Более короткие названия и имена идут первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Подсказки для строки поиска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало названия или имени, не короче двух символов",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество подсказок, по умолчанию 10, не больше 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Подсказки",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.suggestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.suggestResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.suggestResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.suggestResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.suggestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователя с указанным id, пользователи с разрешением users:manage могут получить любого пользователя, остальные только себя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о пользователе",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователя не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Изменяет имя, адрес почты, роль пользователя и блокирует или разблокирует его. Назначить или изменить администратора может только администратор, остальных пользователей и роли — пользователь, у роли которого есть все их разрешения. Последнего активного администратора нельзя лишить прав или заблокировать",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Обновление пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.updateUserData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о пользователе",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных или роли не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователя не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Имя пользователя или адрес почты уже заняты, либо это последний активный администратор",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт пользователя с паролем, управлять пользователями могут пользователи с разрешением users:manage, назначая только роли, разрешения которых есть у их собственной роли, а администраторами только администраторы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Создание пользователя",
+                "parameters": [
+                    {
+                        "description": "Информация о новом пользователе",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.createUserData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о пользователе",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных или роли не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Имя пользователя или адрес почты уже заняты",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет пользователя вместе с его отзывами, списками и подборками. Администратора может удалить только администратор, остальных пользователей — пользователь, у роли которого есть все разрешения их роли. Последнего активного администратора удалить нельзя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удаление пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователя не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Последний активный администратор",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу списка пользователей, упорядоченных по id, и общее количество пользователей",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение списка пользователей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество пользователей на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о пользователях",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Меняет пароль пользователя и завершает все его сессии. Свой пароль пользователь меняет, подтвердив его текущим паролем, а пользователи с разрешением users:manage могут сменить без него и пароль пользователя, роль которого не даёт разрешений больше, чем их собственная",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Смена пароля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый и текущий пароли",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.setPasswordData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации или неверный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователя не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watched/": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет фильм в историю просмотров пользователя с датой просмотра, для уже просмотренного фильма меняет дату",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Отметка фильма просмотренным",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Дата просмотра (timestamp), по умолчанию сегодня, не может быть в будущем",
+                        "name": "watched_at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильме со статусом",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Снимает с фильма отметку о просмотре",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Удаление фильма из истории просмотров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма нет в истории просмотров",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watched/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу просмотренных пользователем фильмов. Поддерживает те же параметры фильтрации, сортировки и поиска, что и список фильмов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Получение истории просмотров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию, описанию фильма и именам актёров",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Параметры сортировки как у списка фильмов",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id актёров через запятую",
+                        "name": "actors",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id жанров через запятую",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Фильмы, которые есть (true) или которых нет (false) в списке «Буду смотреть»",
+                        "name": "watchlist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество фильмов на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильмах",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет фильм в список «Буду смотреть» пользователя, повторное добавление не считается ошибкой",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Добавление фильма в список «Буду смотреть»",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильме со статусом",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма не существует",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет фильм из списка «Буду смотреть» пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Удаление фильма из списка «Буду смотреть»",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id фильма",
+                        "name": "movie_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пустая структура",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Фильма нет в списке",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/list/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ServiceKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает страницу фильмов из списка «Буду смотреть» пользователя. Поддерживает те же параметры фильтрации, сортировки и поиска, что и список фильмов, например watched=false оставляет только непросмотренные фильмы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlist"
+                ],
+                "summary": "Получение списка «Буду смотреть»",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию, описанию фильма и именам актёров",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Параметры сортировки как у списка фильмов",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id актёров через запятую",
+                        "name": "actors",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id жанров через запятую",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Просмотренные (true) или непросмотренные (false) фильмы",
+                        "name": "watched",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество фильмов на странице, по умолчанию 50, не больше 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из поля next_cursor предыдущего ответа",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о фильмах",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат входных данных",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Ошибка авторизации",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Проблемы на стороне сервера",
+                        "schema": {
+                            "$ref": "#/definitions/httpserver.movieListResponse"
+                        }
+                    }
+                }
+            }
+        }
     },
     "definitions": {
         "httpserver.actorData": {
             "type": "object",
             "properties": {
-                "first_name": {
-                    "type": "string"
+                "billing": {
+                    "description": "Позиция актёра в титрах фильма, только в составе фильма",
+                    "type": "integer"
+                },
+                "characters": {
+                    "description": "Персонажи актёра в фильме, только в составе фильма",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "crew_only": {
+                    "description": "Персона только член съёмочной группы, не актёр",
+                    "type": "boolean"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "$ref": "#/definitions/model.Gender"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.movieData"
+                    }
+                },
+                "relevance": {
+                    "description": "Похожесть имени актёра на запрос, только при нечётком поиске",
+                    "type": "number"
+                },
+                "second_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.actorListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.actorData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.actorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.actorData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.addCollectionEntryData": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.apiKeyData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время создания ключа",
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "description": "Время истечения (timestamp), отсутствует у бессрочных ключей",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Ключ, возвращается только при создании",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Начало ключа, по которому его можно узнать",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Область действия: read или write",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.apiKeyListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.apiKeyData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.apiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.apiKeyData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.castCreditData": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "billing": {
+                    "description": "Позиция актёра в титрах, по умолчанию позиция в списке",
+                    "type": "integer"
+                },
+                "characters": {
+                    "description": "Имена персонажей актёра",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "httpserver.collectionData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "Описание подборки",
+                    "type": "string"
+                },
+                "entries": {
+                    "description": "Фильмы подборки по порядку, возвращаются только при получении одной подборки",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.collectionEntryData"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "shared_with": {
+                    "description": "id пользователей, которым подборка доступна для чтения, возвращаются только владельцу",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "visibility": {
+                    "description": "Видимость: private или public",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.collectionEntryData": {
+            "type": "object",
+            "properties": {
+                "movie": {
+                    "$ref": "#/definitions/httpserver.movieData"
+                },
+                "note": {
+                    "description": "Заметка владельца о фильме",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.collectionListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.collectionData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.collectionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.collectionData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createActorData": {
+            "type": "object",
+            "properties": {
+                "crew_only": {
+                    "description": "Персона только член съёмочной группы: она не попадает в списки, поиск и\nподсказки актёров, пока её не добавят в актёрский состав фильма",
+                    "type": "boolean"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "$ref": "#/definitions/model.Gender"
+                },
+                "second_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createApiKeyData": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Необязательное время истечения (timestamp), ключ без него не истекает",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Название ключа от 1 до 100 символов",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Область действия: read (по умолчанию, только получение данных) или write",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createCollectionData": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "shared_with": {
+                    "description": "id пользователей, которым подборка доступна для чтения",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "visibility": {
+                    "description": "Видимость: private (по умолчанию) или public",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createGenreData": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createMovieData": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "description": "Актёры фильма: объекты с id актёра, персонажами и позицией в титрах либо просто id актёров",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.castCreditData"
+                    }
+                },
+                "crew": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.crewCreditData"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "release_date": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createReviewData": {
+            "type": "object",
+            "properties": {
+                "movie_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "Оценка от 1 до 10",
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.createRoleData": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Название роли от 1 до 20 строчных латинских букв, цифр и символов «_» и «-»",
+                    "type": "string"
+                },
+                "permissions": {
+                    "description": "Разрешения роли, например movies:write или reviews:moderate",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "httpserver.createUserData": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "description": "Необязательный адрес электронной почты",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Пароль от 8 до 72 байт",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Роль: regular (по умолчанию), admin или другая роль из списка ролей",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Имя пользователя от 3 до 50 букв, цифр и символов «_», «.» и «-», уникальное без учёта регистра",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.crewCreditData": {
+            "type": "object",
+            "properties": {
+                "person_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "Роль: director, writer, composer или producer",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.crewMemberData": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "$ref": "#/definitions/model.Gender"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "Роль: director, writer, composer или producer",
+                    "type": "string"
+                },
+                "second_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.filmographyData": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "description": "Фильмы персоны по ролям (actor, director, writer, composer, producer), упорядоченные по дате выхода",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/httpserver.movieData"
+                        }
+                    }
+                },
+                "person": {
+                    "$ref": "#/definitions/httpserver.actorData"
+                }
+            }
+        },
+        "httpserver.filmographyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.filmographyData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.fuzzySearchData": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.actorData"
+                    }
+                },
+                "did_you_mean": {
+                    "description": "Наиболее похожее название фильма или имя актёра, если полнотекстовый поиск ничего не нашёл",
+                    "type": "string"
+                },
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.movieData"
+                    }
+                }
+            }
+        },
+        "httpserver.fuzzySearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.fuzzySearchData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.genreData": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.genreListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.genreData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.genreResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.genreData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.loginData": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.movieData": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.actorData"
+                    }
+                },
+                "community_rating": {
+                    "description": "Средняя оценка пользователей, отсутствует, если оценок нет",
+                    "type": "number"
+                },
+                "crew": {
+                    "description": "Съёмочная группа, упорядоченная по ролям: director, writer, composer, producer",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.crewMemberData"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.genreData"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "release_date": {
+                    "type": "integer"
+                },
+                "relevance": {
+                    "description": "Релевантность фильма запросу, только при поиске по pattern и нечётком поиске",
+                    "type": "number"
+                },
+                "snippet": {
+                    "description": "Фрагмент описания с найденными словами в <b></b>, только при поиске по pattern",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Статус фильма в списках пользователя, выполнившего запрос",
+                    "$ref": "#/definitions/httpserver.movieStatusData"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "votes_count": {
+                    "description": "Количество оценок пользователей",
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.movieListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.movieData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.movieResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.movieData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.movieStatusData": {
+            "type": "object",
+            "properties": {
+                "in_watchlist": {
+                    "description": "Фильм есть в списке «Буду смотреть»",
+                    "type": "boolean"
+                },
+                "watched": {
+                    "type": "boolean"
+                },
+                "watched_at": {
+                    "description": "Дата просмотра (timestamp), только для просмотренных фильмов",
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.poolStatsData": {
+            "type": "object",
+            "properties": {
+                "acquire_count": {
+                    "type": "integer"
                 },
-                "gender": {
-                    "$ref": "#/definitions/model.Gender"
+                "acquire_duration_ms": {
+                    "type": "integer"
                 },
-                "id": {
+                "acquired_conns": {
+                    "type": "integer"
+                },
+                "canceled_acquire_count": {
+                    "type": "integer"
+                },
+                "constructing_conns": {
+                    "type": "integer"
+                },
+                "empty_acquire_count": {
+                    "type": "integer"
+                },
+                "idle_conns": {
                     "type": "integer"
                 },
+                "max_conns": {
+                    "type": "integer"
+                },
+                "max_idle_destroy_count": {
+                    "type": "integer"
+                },
+                "max_lifetime_destroy_count": {
+                    "type": "integer"
+                },
+                "new_conns_count": {
+                    "type": "integer"
+                },
+                "total_conns": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.poolStatsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.poolStatsData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.refreshData": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.reorderCollectionData": {
+            "type": "object",
+            "properties": {
                 "movies": {
+                    "description": "id всех фильмов подборки в новом порядке",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/httpserver.movieData"
+                        "type": "integer"
                     }
+                }
+            }
+        },
+        "httpserver.reviewData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время создания и последнего изменения отзыва",
+                    "type": "integer"
                 },
-                "second_name": {
+                "id": {
+                    "type": "integer"
+                },
+                "movie_id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "Оценка от 1 до 10",
+                    "type": "integer"
+                },
+                "text": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "httpserver.actorListResponse": {
+        "httpserver.reviewListResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/httpserver.actorData"
+                        "$ref": "#/definitions/httpserver.reviewData"
                     }
                 },
                 "error": {
                     "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "httpserver.actorResponse": {
+        "httpserver.reviewResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/httpserver.actorData"
+                    "$ref": "#/definitions/httpserver.reviewData"
                 },
                 "error": {
                     "type": "string"
                 }
             }
         },
-        "httpserver.createActorData": {
+        "httpserver.roleData": {
             "type": "object",
             "properties": {
-                "first_name": {
+                "name": {
                     "type": "string"
                 },
-                "gender": {
-                    "$ref": "#/definitions/model.Gender"
-                },
-                "second_name": {
-                    "type": "string"
+                "permissions": {
+                    "description": "Разрешения роли, упорядоченные по названию",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "httpserver.createMovieData": {
+        "httpserver.roleListResponse": {
             "type": "object",
             "properties": {
-                "actors": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/httpserver.roleData"
                     }
                 },
-                "description": {
+                "error": {
                     "type": "string"
+                }
+            }
+        },
+        "httpserver.roleResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.roleData"
                 },
-                "rating": {
-                    "type": "number"
-                },
-                "release_date": {
-                    "type": "integer"
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.setPasswordData": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "description": "Текущий пароль, обязателен при смене своего пароля",
+                    "type": "string"
                 },
-                "title": {
+                "password": {
+                    "description": "Новый пароль от 8 до 72 байт",
                     "type": "string"
                 }
             }
         },
-        "httpserver.movieData": {
+        "httpserver.suggestResponse": {
             "type": "object",
             "properties": {
-                "actors": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/httpserver.actorData"
+                        "$ref": "#/definitions/httpserver.suggestionData"
                     }
                 },
-                "description": {
+                "error": {
                     "type": "string"
-                },
+                }
+            }
+        },
+        "httpserver.suggestionData": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "integer"
                 },
-                "rating": {
-                    "type": "number"
-                },
-                "release_date": {
-                    "type": "integer"
+                "text": {
+                    "description": "Название фильма или имя и фамилия актёра",
+                    "type": "string"
                 },
-                "title": {
+                "type": {
+                    "description": "Тип: movie или actor",
                     "type": "string"
                 }
             }
         },
-        "httpserver.movieListResponse": {
+        "httpserver.tokensData": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/httpserver.movieData"
-                    }
+                "access_token": {
+                    "description": "Токен доступа, передаётся в заголовке Authorization: Bearer <токен>",
+                    "type": "string"
                 },
-                "error": {
+                "expires_in": {
+                    "description": "Время жизни токена доступа в секундах",
+                    "type": "integer"
+                },
+                "refresh_expires_in": {
+                    "description": "Время жизни сессии в секундах, если её не продлевать",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "Одноразовый токен для получения новой пары токенов",
+                    "type": "string"
+                },
+                "token_type": {
                     "type": "string"
                 }
             }
         },
-        "httpserver.movieResponse": {
+        "httpserver.tokensResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/httpserver.movieData"
+                    "$ref": "#/definitions/httpserver.tokensData"
                 },
                 "error": {
                     "type": "string"
@@ -799,6 +4904,10 @@ const docTemplate = `{
         "httpserver.updateActorData": {
             "type": "object",
             "properties": {
+                "crew_only": {
+                    "description": "Персона только член съёмочной группы",
+                    "type": "boolean"
+                },
                 "first_name": {
                     "type": "string"
                 },
@@ -810,18 +4919,69 @@ const docTemplate = `{
                 }
             }
         },
+        "httpserver.updateCollectionData": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "shared_with": {
+                    "description": "id пользователей, которым подборка доступна для чтения, заменяют прежний список",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "visibility": {
+                    "description": "Видимость: private (по умолчанию) или public",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.updateCollectionEntryData": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.updateGenreData": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "httpserver.updateMovieData": {
             "type": "object",
             "properties": {
                 "actors": {
+                    "description": "Актёры фильма: объекты с id актёра, персонажами и позицией в титрах либо просто id актёров",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/httpserver.castCreditData"
+                    }
+                },
+                "crew": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.crewCreditData"
                     }
                 },
                 "description": {
                     "type": "string"
                 },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "rating": {
                     "type": "number"
                 },
@@ -833,6 +4993,107 @@ const docTemplate = `{
                 }
             }
         },
+        "httpserver.updateReviewData": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "description": "Оценка от 1 до 10",
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.updateRoleData": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "description": "Новые разрешения роли, заменяют прежние",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "httpserver.updateUserData": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "description": "Заблокированный пользователь не может выполнять никакие операции",
+                    "type": "boolean"
+                },
+                "email": {
+                    "description": "Необязательный адрес электронной почты",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Роль: regular, admin или другая роль из списка ролей",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Имя пользователя от 3 до 50 букв, цифр и символов «_», «.» и «-», уникальное без учёта регистра",
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.userData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Время создания пользователя",
+                    "type": "integer"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "Роль пользователя",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "httpserver.userListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httpserver.userData"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "httpserver.userResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/httpserver.userData"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
         "model.Gender": {
             "type": "string",
             "enum": [
@@ -852,6 +5113,11 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "ServiceKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
@@ -2,17 +2,30 @@ package app
 
 import (
 	"context"
+	"errors"
+	"movie-lib/internal/auth"
 	"movie-lib/internal/model"
 	"movie-lib/internal/repo"
 	"movie-lib/pkg/logger"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
+// defaultAdminUsername is the username of the admin created by the migrations
+const defaultAdminUsername = "user1"
+
 type appImpl struct {
-	r    repo.Repo
-	logs logger.Logger
+	r      repo.Repo
+	logs   logger.Logger
+	tokens *auth.Issuer
+	roles  *roleCache
 }
 
-func (a *appImpl) CreateMovie(ctx context.Context, userId uint64, movie model.Movie) (model.Movie, error) {
+func (a *appImpl) CreateMovie(ctx context.Context, movie model.Movie) (model.Movie, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -20,24 +33,26 @@ func (a *appImpl) CreateMovie(ctx context.Context, userId uint64, movie model.Mo
 		}
 	}()
 
-	var role model.Role
-	if role, err = a.r.GetUserRole(ctx, userId); err != nil {
+	var userId uint64
+	if userId, _, err = a.authorizeWrite(ctx, model.MoviesWrite); err != nil {
 		return model.Movie{}, err
-	} else if role != model.Admin {
-		return model.Movie{}, model.ErrPermissionDenied
 	}
 
 	if !(len([]rune(movie.Title)) <= 150 && len([]rune(movie.Title)) >= 1) ||
 		len([]rune(movie.Description)) > 1000 ||
-		!(movie.Rating >= 0. && movie.Rating <= 10.) {
+		!(movie.Rating >= 0. && movie.Rating <= 10.) ||
+		!checkCast(movie.Cast) || !checkCrew(movie.CrewCredits) {
 		return model.Movie{}, model.ErrValidationError
 	}
 
-	movie, err = a.r.CreateMovie(ctx, movie)
+	if movie, err = a.r.CreateMovie(ctx, movie); err != nil {
+		return model.Movie{}, err
+	}
+	movie, err = a.withStatus(ctx, userId, movie)
 	return movie, err
 }
 
-func (a *appImpl) UpdateMovie(ctx context.Context, userId uint64, id uint64, upd model.UpdateMovie) (model.Movie, error) {
+func (a *appImpl) UpdateMovie(ctx context.Context, id uint64, upd model.UpdateMovie) (model.Movie, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -45,25 +60,27 @@ func (a *appImpl) UpdateMovie(ctx context.Context, userId uint64, id uint64, upd
 		}
 	}()
 
-	var role model.Role
-	if role, err = a.r.GetUserRole(ctx, userId); err != nil {
+	var userId uint64
+	if userId, _, err = a.authorizeWrite(ctx, model.MoviesWrite); err != nil {
 		return model.Movie{}, err
-	} else if role != model.Admin {
-		return model.Movie{}, model.ErrPermissionDenied
 	}
 
 	if !(len([]rune(upd.Title)) <= 150 && len([]rune(upd.Title)) >= 1) ||
 		len([]rune(upd.Description)) > 1000 ||
-		!(upd.Rating >= 0. && upd.Rating <= 10.) {
+		!(upd.Rating >= 0. && upd.Rating <= 10.) ||
+		!checkCast(upd.Cast) || !checkCrew(upd.Crew) {
 		return model.Movie{}, model.ErrValidationError
 	}
 
 	var movie model.Movie
-	movie, err = a.r.UpdateMovie(ctx, id, upd)
+	if movie, err = a.r.UpdateMovie(ctx, id, upd); err != nil {
+		return model.Movie{}, err
+	}
+	movie, err = a.withStatus(ctx, userId, movie)
 	return movie, err
 }
 
-func (a *appImpl) DeleteMovie(ctx context.Context, userId uint64, id uint64) error {
+func (a *appImpl) DeleteMovie(ctx context.Context, id uint64) error {
 	var err error
 	defer func() {
 		if err != nil {
@@ -71,18 +88,15 @@ func (a *appImpl) DeleteMovie(ctx context.Context, userId uint64, id uint64) err
 		}
 	}()
 
-	var role model.Role
-	if role, err = a.r.GetUserRole(ctx, userId); err != nil {
+	if _, _, err = a.authorizeWrite(ctx, model.MoviesDelete); err != nil {
 		return err
-	} else if role != model.Admin {
-		return model.ErrPermissionDenied
 	}
 
 	err = a.r.DeleteMovie(ctx, id)
 	return err
 }
 
-func (a *appImpl) GetMovie(ctx context.Context, userId uint64, id uint64) (model.Movie, error) {
+func (a *appImpl) GetMovie(ctx context.Context, id uint64) (model.Movie, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -90,16 +104,21 @@ func (a *appImpl) GetMovie(ctx context.Context, userId uint64, id uint64) (model
 		}
 	}()
 
-	if _, err = a.r.GetUserRole(ctx, userId); err != nil {
+	var userId uint64
+	if userId, _, err = a.caller(ctx); err != nil {
 		return model.Movie{}, err
 	}
 
 	var movie model.Movie
-	movie, err = a.r.GetMovie(ctx, id)
+	if movie, err = a.r.GetMovie(ctx, id); err != nil {
+		return model.Movie{}, err
+	}
+	movie, err = a.withStatus(ctx, userId, movie)
 	return movie, err
 }
 
-func (a *appImpl) GetMovies(ctx context.Context, userId uint64, sortBy model.SortParam) ([]model.Movie, error) {
+func (a *appImpl) GetMovies(ctx context.Context, filter model.MovieFilter,
+	sortBy model.Sort, page model.Page) (model.MovieList, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -107,16 +126,32 @@ func (a *appImpl) GetMovies(ctx context.Context, userId uint64, sortBy model.Sor
 		}
 	}()
 
-	if _, err = a.r.GetUserRole(ctx, userId); err != nil {
-		return []model.Movie{}, err
+	var userId uint64
+	if userId, _, err = a.caller(ctx); err != nil {
+		return model.MovieList{}, err
 	}
 
-	var movies []model.Movie
-	movies, err = a.r.GetMovies(ctx, sortBy)
+	if page, err = checkPage(page); err != nil {
+		return model.MovieList{}, err
+	}
+	if err = checkMovieFilter(filter); err != nil {
+		return model.MovieList{}, err
+	}
+	if err = checkSort(sortBy, model.MovieSortParams); err != nil {
+		return model.MovieList{}, err
+	}
+
+	filter.UserId = userId
+	var movies model.MovieList
+	if movies, err = a.r.GetMovies(ctx, filter, sortBy, page); err != nil {
+		return model.MovieList{}, err
+	}
+	err = a.setMoviesStatus(ctx, userId, movies.Movies)
 	return movies, err
 }
 
-func (a *appImpl) SearchMovies(ctx context.Context, userId uint64, pattern string) ([]model.Movie, error) {
+func (a *appImpl) SearchMovies(ctx context.Context, query string, filter model.MovieFilter,
+	sortBy model.Sort, page model.Page) (model.MovieList, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -124,16 +159,31 @@ func (a *appImpl) SearchMovies(ctx context.Context, userId uint64, pattern strin
 		}
 	}()
 
-	if _, err = a.r.GetUserRole(ctx, userId); err != nil {
-		return []model.Movie{}, err
+	var userId uint64
+	if userId, _, err = a.caller(ctx); err != nil {
+		return model.MovieList{}, err
+	}
+
+	if page, err = checkPage(page); err != nil {
+		return model.MovieList{}, err
+	}
+	if err = checkMovieFilter(filter); err != nil {
+		return model.MovieList{}, err
+	}
+	if err = checkSort(sortBy, model.SearchMovieSortParams); err != nil {
+		return model.MovieList{}, err
 	}
 
-	var movies []model.Movie
-	movies, err = a.r.SearchMovies(ctx, pattern)
+	filter.UserId = userId
+	var movies model.MovieList
+	if movies, err = a.r.SearchMovies(ctx, query, filter, sortBy, page); err != nil {
+		return model.MovieList{}, err
+	}
+	err = a.setMoviesStatus(ctx, userId, movies.Movies)
 	return movies, err
 }
 
-func (a *appImpl) CreateActor(ctx context.Context, userId uint64, actor model.Actor) (model.Actor, error) {
+func (a *appImpl) CreateActor(ctx context.Context, actor model.Actor) (model.Actor, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -141,18 +191,15 @@ func (a *appImpl) CreateActor(ctx context.Context, userId uint64, actor model.Ac
 		}
 	}()
 
-	var role model.Role
-	if role, err = a.r.GetUserRole(ctx, userId); err != nil {
+	if _, _, err = a.authorizeWrite(ctx, model.ActorsWrite); err != nil {
 		return model.Actor{}, err
-	} else if role != model.Admin {
-		return model.Actor{}, model.ErrPermissionDenied
 	}
 
 	actor, err = a.r.CreateActor(ctx, actor)
 	return actor, err
 }
 
-func (a *appImpl) UpdateActor(ctx context.Context, userId uint64, id uint64, upd model.UpdateActor) (model.Actor, error) {
+func (a *appImpl) UpdateActor(ctx context.Context, id uint64, upd model.UpdateActor) (model.Actor, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -160,11 +207,8 @@ func (a *appImpl) UpdateActor(ctx context.Context, userId uint64, id uint64, upd
 		}
 	}()
 
-	var role model.Role
-	if role, err = a.r.GetUserRole(ctx, userId); err != nil {
+	if _, _, err = a.authorizeWrite(ctx, model.ActorsWrite); err != nil {
 		return model.Actor{}, err
-	} else if role != model.Admin {
-		return model.Actor{}, model.ErrPermissionDenied
 	}
 
 	var actor model.Actor
@@ -172,7 +216,7 @@ func (a *appImpl) UpdateActor(ctx context.Context, userId uint64, id uint64, upd
 	return actor, err
 }
 
-func (a *appImpl) DeleteActor(ctx context.Context, userId uint64, id uint64) error {
+func (a *appImpl) DeleteActor(ctx context.Context, id uint64) error {
 	var err error
 	defer func() {
 		if err != nil {
@@ -180,18 +224,15 @@ func (a *appImpl) DeleteActor(ctx context.Context, userId uint64, id uint64) err
 		}
 	}()
 
-	var role model.Role
-	if role, err = a.r.GetUserRole(ctx, userId); err != nil {
+	if _, _, err = a.authorizeWrite(ctx, model.ActorsDelete); err != nil {
 		return err
-	} else if role != model.Admin {
-		return model.ErrPermissionDenied
 	}
 
 	err = a.r.DeleteActor(ctx, id)
 	return err
 }
 
-func (a *appImpl) GetActor(ctx context.Context, userId uint64, id uint64) (model.Actor, error) {
+func (a *appImpl) GetActor(ctx context.Context, id uint64) (model.Actor, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -199,7 +240,7 @@ func (a *appImpl) GetActor(ctx context.Context, userId uint64, id uint64) (model
 		}
 	}()
 
-	if _, err = a.r.GetUserRole(ctx, userId); err != nil {
+	if _, _, err = a.caller(ctx); err != nil {
 		return model.Actor{}, err
 	}
 
@@ -208,7 +249,36 @@ func (a *appImpl) GetActor(ctx context.Context, userId uint64, id uint64) (model
 	return actor, err
 }
 
-func (a *appImpl) GetActors(ctx context.Context, userId uint64) ([]model.Actor, error) {
+func (a *appImpl) GetActors(ctx context.Context, filter model.ActorFilter,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.ActorList{}, err
+	}
+
+	if page, err = checkPage(page); err != nil {
+		return model.ActorList{}, err
+	}
+	if err = checkActorFilter(filter); err != nil {
+		return model.ActorList{}, err
+	}
+	if err = checkSort(sortBy, model.ActorSortParams); err != nil {
+		return model.ActorList{}, err
+	}
+
+	var actors model.ActorList
+	actors, err = a.r.GetActors(ctx, filter, sortBy, page)
+	return actors, err
+}
+
+func (a *appImpl) SearchActors(ctx context.Context, pattern string, filter model.ActorFilter,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -216,11 +286,1750 @@ func (a *appImpl) GetActors(ctx context.Context, userId uint64) ([]model.Actor,
 		}
 	}()
 
-	if _, err = a.r.GetUserRole(ctx, userId); err != nil {
-		return []model.Actor{}, err
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.ActorList{}, err
+	}
+
+	if page, err = checkPage(page); err != nil {
+		return model.ActorList{}, err
+	}
+	if err = checkActorFilter(filter); err != nil {
+		return model.ActorList{}, err
+	}
+	if err = checkSort(sortBy, model.ActorSortParams); err != nil {
+		return model.ActorList{}, err
 	}
 
-	var actors []model.Actor
-	actors, err = a.r.GetActors(ctx)
+	var actors model.ActorList
+	actors, err = a.r.SearchActors(ctx, pattern, filter, sortBy, page)
 	return actors, err
 }
+
+// GetFilmography returns movies of the person grouped by roles, the person
+// may be an actor or a crew member
+func (a *appImpl) GetFilmography(ctx context.Context, personId uint64) (model.Filmography, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.Filmography{}, err
+	}
+
+	var filmography model.Filmography
+	filmography, err = a.r.GetFilmography(ctx, personId)
+	return filmography, err
+}
+
+func (a *appImpl) CreateGenre(ctx context.Context, genre model.Genre) (model.Genre, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.authorizeWrite(ctx, model.GenresWrite); err != nil {
+		return model.Genre{}, err
+	}
+
+	genre.Name = strings.TrimSpace(genre.Name)
+	if !checkGenreName(genre.Name) {
+		return model.Genre{}, model.ErrValidationError
+	}
+
+	genre, err = a.r.CreateGenre(ctx, genre)
+	return genre, err
+}
+
+func (a *appImpl) UpdateGenre(ctx context.Context, id uint64, upd model.UpdateGenre) (model.Genre, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.authorizeWrite(ctx, model.GenresWrite); err != nil {
+		return model.Genre{}, err
+	}
+
+	upd.Name = strings.TrimSpace(upd.Name)
+	if !checkGenreName(upd.Name) {
+		return model.Genre{}, model.ErrValidationError
+	}
+
+	var genre model.Genre
+	genre, err = a.r.UpdateGenre(ctx, id, upd)
+	return genre, err
+}
+
+func (a *appImpl) DeleteGenre(ctx context.Context, id uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.authorizeWrite(ctx, model.GenresDelete); err != nil {
+		return err
+	}
+
+	err = a.r.DeleteGenre(ctx, id)
+	return err
+}
+
+func (a *appImpl) GetGenre(ctx context.Context, id uint64) (model.Genre, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.Genre{}, err
+	}
+
+	var genre model.Genre
+	genre, err = a.r.GetGenre(ctx, id)
+	return genre, err
+}
+
+func (a *appImpl) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return nil, err
+	}
+
+	var genres []model.Genre
+	genres, err = a.r.GetGenres(ctx)
+	return genres, err
+}
+
+// CreateReview adds the review of the movie written by the user, every user
+// can review the movie only once
+func (a *appImpl) CreateReview(ctx context.Context, review model.Review) (model.Review, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Review{}, err
+	}
+
+	review.Text = strings.TrimSpace(review.Text)
+	if !checkReview(review.Rating, review.Text) {
+		return model.Review{}, model.ErrValidationError
+	}
+
+	review.UserId = userId
+	review, err = a.r.CreateReview(ctx, review)
+	return review, err
+}
+
+// UpdateReview changes the review, only the author can change it
+func (a *appImpl) UpdateReview(ctx context.Context, id uint64, upd model.UpdateReview) (model.Review, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Review{}, err
+	}
+
+	upd.Text = strings.TrimSpace(upd.Text)
+	if !checkReview(upd.Rating, upd.Text) {
+		return model.Review{}, model.ErrValidationError
+	}
+
+	var review model.Review
+	if review, err = a.r.GetReview(ctx, id); err != nil {
+		return model.Review{}, err
+	} else if review.UserId != userId {
+		return model.Review{}, model.ErrPermissionDenied
+	}
+
+	review, err = a.r.UpdateReview(ctx, id, upd)
+	return review, err
+}
+
+// DeleteReview deletes the review, the author can delete own review and
+// users with the reviews:moderate permission can delete any review
+func (a *appImpl) DeleteReview(ctx context.Context, id uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var (
+		userId uint64
+		role   model.RolePermissions
+	)
+	if userId, role, err = a.writer(ctx); err != nil {
+		return err
+	}
+
+	var review model.Review
+	if review, err = a.r.GetReview(ctx, id); err != nil {
+		return err
+	} else if review.UserId != userId && !role.Has(model.ReviewsModerate) {
+		return model.ErrPermissionDenied
+	}
+
+	err = a.r.DeleteReview(ctx, id)
+	return err
+}
+
+func (a *appImpl) GetReview(ctx context.Context, id uint64) (model.Review, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.Review{}, err
+	}
+
+	var review model.Review
+	review, err = a.r.GetReview(ctx, id)
+	return review, err
+}
+
+func (a *appImpl) GetReviews(ctx context.Context, filter model.ReviewFilter,
+	page model.Page) (model.ReviewList, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.ReviewList{}, err
+	}
+
+	if page, err = checkPage(page); err != nil {
+		return model.ReviewList{}, err
+	}
+
+	var reviews model.ReviewList
+	reviews, err = a.r.GetReviews(ctx, filter, page)
+	return reviews, err
+}
+
+// AddToWatchlist adds the movie to the watchlist of the user and returns the
+// movie with its new status
+func (a *appImpl) AddToWatchlist(ctx context.Context, movieId uint64) (model.Movie, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Movie{}, err
+	}
+
+	if err = a.r.AddToWatchlist(ctx, userId, movieId); err != nil {
+		return model.Movie{}, err
+	}
+	var movie model.Movie
+	if movie, err = a.r.GetMovie(ctx, movieId); err != nil {
+		return model.Movie{}, err
+	}
+	movie, err = a.withStatus(ctx, userId, movie)
+	return movie, err
+}
+
+func (a *appImpl) RemoveFromWatchlist(ctx context.Context, movieId uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return err
+	}
+
+	err = a.r.RemoveFromWatchlist(ctx, userId, movieId)
+	return err
+}
+
+// MarkWatched adds the movie to the watched history of the user with the date
+// of watching, zero date means today. The movie is returned with its new
+// status.
+func (a *appImpl) MarkWatched(ctx context.Context, movieId uint64, watchedAt time.Time) (model.Movie, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Movie{}, err
+	}
+
+	// dates are compared in UTC the same way as they are stored
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if watchedAt.IsZero() {
+		watchedAt = today
+	}
+	if watchedAt.UTC().Truncate(24 * time.Hour).After(today) {
+		return model.Movie{}, model.ErrValidationError
+	}
+
+	if err = a.r.MarkWatched(ctx, userId, movieId, watchedAt); err != nil {
+		return model.Movie{}, err
+	}
+	var movie model.Movie
+	if movie, err = a.r.GetMovie(ctx, movieId); err != nil {
+		return model.Movie{}, err
+	}
+	movie, err = a.withStatus(ctx, userId, movie)
+	return movie, err
+}
+
+func (a *appImpl) UnmarkWatched(ctx context.Context, movieId uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return err
+	}
+
+	err = a.r.UnmarkWatched(ctx, userId, movieId)
+	return err
+}
+
+// withStatus returns the movie with its status in the lists of the user
+func (a *appImpl) withStatus(ctx context.Context, userId uint64, movie model.Movie) (model.Movie, error) {
+	movies := []model.Movie{movie}
+	if err := a.setMoviesStatus(ctx, userId, movies); err != nil {
+		return model.Movie{}, err
+	}
+	return movies[0], nil
+}
+
+// setMoviesStatus sets statuses of the movies in the lists of the user
+func (a *appImpl) setMoviesStatus(ctx context.Context, userId uint64, movies []model.Movie) error {
+	ids := make([]uint64, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+	statuses, err := a.r.GetMoviesStatus(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+	for i := range movies {
+		status := statuses[movies[i].Id]
+		movies[i].Status = &status
+	}
+	return nil
+}
+
+// CreateCollection creates the collection owned by the user, collections are
+// private by default
+func (a *appImpl) CreateCollection(ctx context.Context, collection model.Collection) (model.Collection, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Collection{}, err
+	}
+
+	collection.Name = strings.TrimSpace(collection.Name)
+	collection.Description = strings.TrimSpace(collection.Description)
+	if collection.Visibility == "" {
+		collection.Visibility = model.Private
+	}
+	if !checkCollection(collection.Name, collection.Description, collection.Visibility) {
+		return model.Collection{}, model.ErrValidationError
+	}
+
+	collection.OwnerId = userId
+	if collection, err = a.r.CreateCollection(ctx, collection); err != nil {
+		return model.Collection{}, err
+	}
+	collection, err = a.withEntriesStatus(ctx, userId, collection)
+	return collection, err
+}
+
+// UpdateCollection changes the collection and replaces users it is shared
+// with, only the owner can change it
+func (a *appImpl) UpdateCollection(ctx context.Context, id uint64,
+	upd model.UpdateCollection) (model.Collection, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Collection{}, err
+	}
+
+	upd.Name = strings.TrimSpace(upd.Name)
+	upd.Description = strings.TrimSpace(upd.Description)
+	if upd.Visibility == "" {
+		upd.Visibility = model.Private
+	}
+	if !checkCollection(upd.Name, upd.Description, upd.Visibility) {
+		return model.Collection{}, model.ErrValidationError
+	}
+
+	if err = a.checkCollectionOwner(ctx, userId, id); err != nil {
+		return model.Collection{}, err
+	}
+	var collection model.Collection
+	if collection, err = a.r.UpdateCollection(ctx, id, upd); err != nil {
+		return model.Collection{}, err
+	}
+	collection, err = a.withEntriesStatus(ctx, userId, collection)
+	return collection, err
+}
+
+// DeleteCollection deletes the collection, the owner can delete own
+// collection and users with the collections:moderate permission can delete
+// any collection
+func (a *appImpl) DeleteCollection(ctx context.Context, id uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var (
+		userId uint64
+		role   model.RolePermissions
+	)
+	if userId, role, err = a.writer(ctx); err != nil {
+		return err
+	}
+
+	var collection model.Collection
+	if collection, err = a.r.GetCollection(ctx, id); err != nil {
+		return err
+	} else if collection.OwnerId != userId && !role.Has(model.CollectionsModerate) {
+		return model.ErrPermissionDenied
+	}
+
+	err = a.r.DeleteCollection(ctx, id)
+	return err
+}
+
+// GetCollection returns the collection with its entries if the user can read
+// it, users the collection is shared with are returned only to the owner
+func (a *appImpl) GetCollection(ctx context.Context, id uint64) (model.Collection, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.caller(ctx); err != nil {
+		return model.Collection{}, err
+	}
+
+	var collection model.Collection
+	if collection, err = a.r.GetCollection(ctx, id); err != nil {
+		return model.Collection{}, err
+	}
+	if !canReadCollection(collection, userId) {
+		return model.Collection{}, model.ErrPermissionDenied
+	}
+	if collection.OwnerId != userId {
+		collection.SharedWith = nil
+	}
+	collection, err = a.withEntriesStatus(ctx, userId, collection)
+	return collection, err
+}
+
+// GetCollections returns collections which the user can read: own, public and
+// shared with the user
+func (a *appImpl) GetCollections(ctx context.Context, filter model.CollectionFilter,
+	page model.Page) (model.CollectionList, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.caller(ctx); err != nil {
+		return model.CollectionList{}, err
+	}
+
+	if page, err = checkPage(page); err != nil {
+		return model.CollectionList{}, err
+	}
+
+	filter.ReaderId = userId
+	var collections model.CollectionList
+	collections, err = a.r.GetCollections(ctx, filter, page)
+	return collections, err
+}
+
+// AddCollectionEntry appends the movie with the note to the end of the
+// collection, only the owner can change entries
+func (a *appImpl) AddCollectionEntry(ctx context.Context, collectionId uint64,
+	entry model.CollectionEntry) (model.Collection, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Collection{}, err
+	}
+
+	entry.Note = strings.TrimSpace(entry.Note)
+	if !checkCollectionNote(entry.Note) {
+		return model.Collection{}, model.ErrValidationError
+	}
+
+	if err = a.checkCollectionOwner(ctx, userId, collectionId); err != nil {
+		return model.Collection{}, err
+	}
+	if err = a.r.AddCollectionEntry(ctx, collectionId, entry); err != nil {
+		return model.Collection{}, err
+	}
+	var collection model.Collection
+	if collection, err = a.r.GetCollection(ctx, collectionId); err != nil {
+		return model.Collection{}, err
+	}
+	collection, err = a.withEntriesStatus(ctx, userId, collection)
+	return collection, err
+}
+
+// UpdateCollectionEntry changes the note of the movie in the collection
+func (a *appImpl) UpdateCollectionEntry(ctx context.Context, collectionId uint64,
+	entry model.CollectionEntry) (model.Collection, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Collection{}, err
+	}
+
+	entry.Note = strings.TrimSpace(entry.Note)
+	if !checkCollectionNote(entry.Note) {
+		return model.Collection{}, model.ErrValidationError
+	}
+
+	if err = a.checkCollectionOwner(ctx, userId, collectionId); err != nil {
+		return model.Collection{}, err
+	}
+	if err = a.r.UpdateCollectionEntry(ctx, collectionId, entry); err != nil {
+		return model.Collection{}, err
+	}
+	var collection model.Collection
+	if collection, err = a.r.GetCollection(ctx, collectionId); err != nil {
+		return model.Collection{}, err
+	}
+	collection, err = a.withEntriesStatus(ctx, userId, collection)
+	return collection, err
+}
+
+func (a *appImpl) RemoveCollectionEntry(ctx context.Context, collectionId uint64, movieId uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return err
+	}
+
+	if err = a.checkCollectionOwner(ctx, userId, collectionId); err != nil {
+		return err
+	}
+	err = a.r.RemoveCollectionEntry(ctx, collectionId, movieId)
+	return err
+}
+
+// ReorderCollection orders entries of the collection as the movies, moviesId
+// should contain every movie of the collection exactly once
+func (a *appImpl) ReorderCollection(ctx context.Context, collectionId uint64,
+	moviesId []uint64) (model.Collection, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.Collection{}, err
+	}
+
+	if err = a.checkCollectionOwner(ctx, userId, collectionId); err != nil {
+		return model.Collection{}, err
+	}
+	if err = a.r.ReorderCollection(ctx, collectionId, moviesId); err != nil {
+		return model.Collection{}, err
+	}
+	var collection model.Collection
+	if collection, err = a.r.GetCollection(ctx, collectionId); err != nil {
+		return model.Collection{}, err
+	}
+	collection, err = a.withEntriesStatus(ctx, userId, collection)
+	return collection, err
+}
+
+// checkCollectionOwner returns model.ErrPermissionDenied if the user does not
+// own the collection
+func (a *appImpl) checkCollectionOwner(ctx context.Context, userId uint64, collectionId uint64) error {
+	collection, err := a.r.GetCollection(ctx, collectionId)
+	if err != nil {
+		return err
+	}
+	if collection.OwnerId != userId {
+		return model.ErrPermissionDenied
+	}
+	return nil
+}
+
+// withEntriesStatus returns the collection with statuses of its movies in the
+// lists of the user
+func (a *appImpl) withEntriesStatus(ctx context.Context, userId uint64, collection model.Collection) (model.Collection, error) {
+	movies := make([]model.Movie, 0, len(collection.Entries))
+	for _, entry := range collection.Entries {
+		movies = append(movies, entry.Movie)
+	}
+	if err := a.setMoviesStatus(ctx, userId, movies); err != nil {
+		return model.Collection{}, err
+	}
+	for i := range collection.Entries {
+		collection.Entries[i].Movie = movies[i]
+	}
+	return collection, nil
+}
+
+// canReadCollection checks whether the collection is own, public or shared
+// with the user
+func canReadCollection(collection model.Collection, userId uint64) bool {
+	if collection.OwnerId == userId || collection.Visibility == model.Public {
+		return true
+	}
+	for _, id := range collection.SharedWith {
+		if id == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzySearch finds movies and actors with titles and names similar to the
+// query and suggests the most similar one when the full-text search of movies
+// finds nothing
+func (a *appImpl) FuzzySearch(ctx context.Context, query string, limit int) (model.FuzzySearchResult, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.FuzzySearchResult{}, err
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return model.FuzzySearchResult{}, model.ErrValidationError
+	}
+	if limit == 0 {
+		limit = model.DefaultFuzzySearchLimit
+	}
+	if limit < 0 || limit > model.MaxFuzzySearchLimit {
+		return model.FuzzySearchResult{}, model.ErrValidationError
+	}
+
+	var res model.FuzzySearchResult
+	if res, err = a.r.FuzzySearch(ctx, query, limit); err != nil {
+		return model.FuzzySearchResult{}, err
+	}
+
+	var exact model.MovieList
+	if exact, err = a.r.SearchMovies(ctx, query, model.MovieFilter{}, nil, model.Page{Limit: 1}); err != nil {
+		return model.FuzzySearchResult{}, err
+	}
+	if exact.Total == 0 {
+		res.DidYouMean = didYouMean(res)
+	}
+	return res, nil
+}
+
+// Suggest returns movies and actors whose titles or names start with the
+// prefix, it is used to complete the text typed into the search box. Nothing
+// is suggested for prefixes shorter than model.MinSuggestPrefixLength.
+func (a *appImpl) Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit == 0 {
+		limit = model.DefaultSuggestLimit
+	}
+	if limit < 0 || limit > model.MaxSuggestLimit {
+		return nil, model.ErrValidationError
+	}
+	prefix = strings.TrimSpace(prefix)
+	if utf8.RuneCountInString(prefix) < model.MinSuggestPrefixLength {
+		return make([]model.Suggestion, 0), nil
+	}
+
+	var suggestions []model.Suggestion
+	suggestions, err = a.r.Suggest(ctx, prefix, limit)
+	return suggestions, err
+}
+
+// CreateUser creates the account of the user with the password, users are
+// managed by users with the users:manage permission. Users are regular by
+// default, emails are stored in lower case.
+func (a *appImpl) CreateUser(ctx context.Context, user model.User, password string) (model.User, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var role model.RolePermissions
+	if _, role, err = a.authorizeWrite(ctx, model.UsersManage); err != nil {
+		return model.User{}, err
+	}
+
+	user.Username = strings.TrimSpace(user.Username)
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	if user.Role == model.Default {
+		user.Role = model.Regular
+	}
+	if err = a.checkManagedRole(ctx, role, user.Role); err != nil {
+		return model.User{}, err
+	}
+	if !checkUser(user.Username, user.Email) || !checkPassword(password) {
+		return model.User{}, model.ErrValidationError
+	}
+
+	var hash string
+	if hash, err = auth.HashPassword(password); err != nil {
+		return model.User{}, err
+	}
+	err = a.r.WithTx(ctx, func(r repo.Repo) error {
+		var err error
+		if user, err = r.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return r.SetPasswordHash(ctx, user.Id, hash)
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// UpdateUser changes the account of the user, the last active admin can not
+// be demoted or disabled
+func (a *appImpl) UpdateUser(ctx context.Context, id uint64, upd model.UpdateUser) (model.User, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var role model.RolePermissions
+	if _, role, err = a.authorizeWrite(ctx, model.UsersManage); err != nil {
+		return model.User{}, err
+	}
+
+	upd.Username = strings.TrimSpace(upd.Username)
+	upd.Email = strings.ToLower(strings.TrimSpace(upd.Email))
+	if !checkUser(upd.Username, upd.Email) {
+		return model.User{}, model.ErrValidationError
+	}
+
+	var user model.User
+	if user, err = a.r.GetUser(ctx, id); err != nil {
+		return model.User{}, err
+	}
+	if err = a.checkManagedRole(ctx, role, user.Role); err != nil {
+		return model.User{}, err
+	}
+	if err = a.checkManagedRole(ctx, role, upd.Role); err != nil {
+		return model.User{}, err
+	}
+
+	// sessions of the disabled user are revoked, so the user can not refresh
+	// tokens after being enabled again without a new login
+	err = a.r.WithTx(ctx, func(r repo.Repo) error {
+		var err error
+		if user, err = r.UpdateUser(ctx, id, upd); err != nil {
+			return err
+		}
+		if upd.Disabled {
+			return r.DeleteUserSessions(ctx, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	a.roles.invalidate()
+	return user, nil
+}
+
+// DeleteUser deletes the user with their reviews, lists and collections, the
+// last active admin can not be deleted
+func (a *appImpl) DeleteUser(ctx context.Context, id uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var role model.RolePermissions
+	if _, role, err = a.authorizeWrite(ctx, model.UsersManage); err != nil {
+		return err
+	}
+
+	var user model.User
+	if user, err = a.r.GetUser(ctx, id); err != nil {
+		return err
+	}
+	if err = a.checkManagedRole(ctx, role, user.Role); err != nil {
+		return err
+	}
+
+	if err = a.r.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	a.roles.invalidate()
+	return nil
+}
+
+// GetUser returns the account of the user, users with the users:manage
+// permission can get any account and other users only their own one
+func (a *appImpl) GetUser(ctx context.Context, id uint64) (model.User, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var (
+		userId uint64
+		role   model.RolePermissions
+	)
+	if userId, role, err = a.caller(ctx); err != nil {
+		return model.User{}, err
+	} else if id != userId && !role.Has(model.UsersManage) {
+		return model.User{}, model.ErrPermissionDenied
+	}
+
+	var user model.User
+	user, err = a.r.GetUser(ctx, id)
+	return user, err
+}
+
+func (a *appImpl) GetUsers(ctx context.Context, page model.Page) (model.UserList, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.authorize(ctx, model.UsersManage); err != nil {
+		return model.UserList{}, err
+	}
+
+	if page, err = checkPage(page); err != nil {
+		return model.UserList{}, err
+	}
+
+	var users model.UserList
+	users, err = a.r.GetUsers(ctx, page)
+	return users, err
+}
+
+// SetPassword changes the password of the user and revokes all sessions of
+// the user. Users change their own password confirming it with the current
+// one, so a stolen access token is not enough to take over the account. Users
+// with the users:manage permission can also change passwords of users they
+// manage.
+func (a *appImpl) SetPassword(ctx context.Context, id uint64, password string, currentPassword string) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var (
+		userId uint64
+		role   model.RolePermissions
+	)
+	if userId, role, err = a.writer(ctx); err != nil {
+		return err
+	} else if id != userId && !role.Has(model.UsersManage) {
+		return model.ErrPermissionDenied
+	}
+
+	if !checkPassword(password) {
+		return model.ErrValidationError
+	}
+	var user model.User
+	if user, err = a.r.GetUser(ctx, id); err != nil {
+		return err
+	}
+	if id != userId {
+		if err = a.checkManagedRole(ctx, role, user.Role); err != nil {
+			return err
+		}
+	} else {
+		var creds model.Credentials
+		if creds, err = a.r.GetUserCredentials(ctx, user.Username); err != nil {
+			return err
+		}
+		if !auth.CheckPassword(creds.PasswordHash, currentPassword) {
+			err = model.ErrInvalidCredentials
+			return err
+		}
+	}
+
+	var hash string
+	if hash, err = auth.HashPassword(password); err != nil {
+		return err
+	}
+	err = a.r.WithTx(ctx, func(r repo.Repo) error {
+		if err := r.SetPasswordHash(ctx, id, hash); err != nil {
+			return err
+		}
+		return r.DeleteUserSessions(ctx, id)
+	})
+	return err
+}
+
+// GetRoles returns all roles with their permissions ordered by name, roles
+// are visible to all users
+func (a *appImpl) GetRoles(ctx context.Context) ([]model.RolePermissions, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return nil, err
+	}
+
+	var roles []model.RolePermissions
+	roles, err = a.r.GetRoles(ctx)
+	return roles, err
+}
+
+func (a *appImpl) GetRole(ctx context.Context, name model.Role) (model.RolePermissions, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.caller(ctx); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	var role model.RolePermissions
+	role, err = a.r.GetRole(ctx, name)
+	return role, err
+}
+
+// CreateRole creates the role with the permissions, roles are managed by
+// users with the roles:manage permission
+func (a *appImpl) CreateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var manager model.RolePermissions
+	if _, manager, err = a.authorizeWrite(ctx, model.RolesManage); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	role.Role = model.Role(strings.TrimSpace(string(role.Role)))
+	if !checkRole(role.Role, role.Permissions) {
+		return model.RolePermissions{}, model.ErrValidationError
+	}
+	if err = checkGrantedPermissions(manager, role.Permissions); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	role, err = a.r.CreateRole(ctx, role)
+	return role, err
+}
+
+// UpdateRole replaces permissions of the role, permissions of admins can not
+// be changed, so admins can always manage users and roles
+func (a *appImpl) UpdateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var manager model.RolePermissions
+	if _, manager, err = a.authorizeWrite(ctx, model.RolesManage); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	if role.Role == model.Admin {
+		err = model.ErrBuiltInRole
+		return model.RolePermissions{}, err
+	}
+	if !checkRole(role.Role, role.Permissions) {
+		return model.RolePermissions{}, model.ErrValidationError
+	}
+	if err = checkGrantedPermissions(manager, role.Permissions); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	if role, err = a.r.UpdateRole(ctx, role); err != nil {
+		return model.RolePermissions{}, err
+	}
+	a.roles.invalidate()
+	return role, nil
+}
+
+// DeleteRole deletes the role which is not assigned to any user, the admin
+// and the regular roles can not be deleted
+func (a *appImpl) DeleteRole(ctx context.Context, name model.Role) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.authorizeWrite(ctx, model.RolesManage); err != nil {
+		return err
+	}
+
+	if name == model.Admin || name == model.Regular {
+		err = model.ErrBuiltInRole
+		return err
+	}
+
+	err = a.r.DeleteRole(ctx, name)
+	return err
+}
+
+// Login checks the password of the active user and starts a new session.
+// The same error is returned for unknown users, users without a password
+// and wrong passwords.
+func (a *appImpl) Login(ctx context.Context, username string, password string) (model.Tokens, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var creds model.Credentials
+	creds, err = a.r.GetUserCredentials(ctx, strings.TrimSpace(username))
+	if errors.Is(err, model.ErrUserNotExists) {
+		// the password is still checked, so the response time does not tell
+		// whether the user exists
+		auth.CheckPassword("", password)
+		err = model.ErrInvalidCredentials
+		return model.Tokens{}, err
+	} else if err != nil {
+		return model.Tokens{}, err
+	}
+	if !auth.CheckPassword(creds.PasswordHash, password) || creds.Disabled {
+		err = model.ErrInvalidCredentials
+		return model.Tokens{}, err
+	}
+
+	var refreshToken, refreshHash string
+	if refreshToken, refreshHash, err = auth.NewRefreshToken(); err != nil {
+		return model.Tokens{}, err
+	}
+	var session model.Session
+	session, err = a.r.CreateSession(ctx, model.Session{
+		UserId:           creds.UserId,
+		RefreshTokenHash: refreshHash,
+		ExpiresAt:        time.Now().Add(a.tokens.RefreshTokenTTL()),
+	})
+	if err != nil {
+		return model.Tokens{}, err
+	}
+
+	var tokens model.Tokens
+	tokens, err = a.issueTokens(session, refreshToken)
+	return tokens, err
+}
+
+// Refresh exchanges the refresh token for new tokens of the same session and
+// prolongs the session, every refresh token can be used only once
+func (a *appImpl) Refresh(ctx context.Context, refreshToken string) (model.Tokens, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var newToken, newHash string
+	if newToken, newHash, err = auth.NewRefreshToken(); err != nil {
+		return model.Tokens{}, err
+	}
+	var session model.Session
+	session, err = a.r.RefreshSession(ctx, auth.HashToken(refreshToken), newHash,
+		time.Now().Add(a.tokens.RefreshTokenTTL()))
+	if errors.Is(err, model.ErrSessionNotExists) {
+		err = errors.Join(model.ErrInvalidToken, err)
+		return model.Tokens{}, err
+	} else if err != nil {
+		return model.Tokens{}, err
+	}
+	if _, err = a.r.GetUserRole(ctx, session.UserId); errors.Is(err, model.ErrUserNotExists) {
+		err = errors.Join(model.ErrInvalidToken, err)
+		return model.Tokens{}, err
+	} else if err != nil {
+		return model.Tokens{}, err
+	}
+
+	var tokens model.Tokens
+	tokens, err = a.issueTokens(session, newToken)
+	return tokens, err
+}
+
+// Logout revokes the session of the request or all sessions of its user,
+// access tokens of revoked sessions are rejected by Authenticate
+func (a *appImpl) Logout(ctx context.Context, all bool) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		err = model.ErrUnauthorized
+		return err
+	} else if principal.SessionId == 0 {
+		// the request is authorized by the API key
+		err = model.ErrPermissionDenied
+		return err
+	}
+
+	if all {
+		err = a.r.DeleteUserSessions(ctx, principal.UserId)
+		return err
+	}
+
+	var session model.Session
+	if session, err = a.r.GetSession(ctx, principal.SessionId); err != nil {
+		return err
+	} else if session.UserId != principal.UserId {
+		err = model.ErrPermissionDenied
+		return err
+	}
+	err = a.r.DeleteSession(ctx, principal.SessionId)
+	return err
+}
+
+// Authenticate verifies the access token and checks that its session is not
+// revoked and its user is active, it returns the principal of the session
+func (a *appImpl) Authenticate(ctx context.Context, accessToken string) (model.Principal, error) {
+	claims, err := a.tokens.ParseAccessToken(accessToken)
+	if err != nil {
+		return model.Principal{}, err
+	}
+
+	session, err := a.r.GetSession(ctx, claims.SessionId)
+	if errors.Is(err, model.ErrSessionNotExists) {
+		return model.Principal{}, errors.Join(model.ErrInvalidToken, err)
+	} else if err != nil {
+		a.logs.ErrorLog(err.Error())
+		return model.Principal{}, err
+	}
+	if session.UserId != claims.UserId {
+		return model.Principal{}, model.ErrInvalidToken
+	}
+	if err = a.checkActive(ctx, session.UserId); err != nil {
+		return model.Principal{}, err
+	}
+	return model.Principal{
+		UserId:    session.UserId,
+		SessionId: session.Id,
+		Scope:     model.WriteScope,
+	}, nil
+}
+
+// AuthenticateApiKey checks that the API key exists and is not expired and
+// its user is active, it returns the principal of the key
+func (a *appImpl) AuthenticateApiKey(ctx context.Context, key string) (model.Principal, error) {
+	apiKey, err := a.r.GetApiKeyByHash(ctx, auth.HashToken(key))
+	if errors.Is(err, model.ErrApiKeyNotExists) {
+		return model.Principal{}, errors.Join(model.ErrInvalidToken, err)
+	} else if err != nil {
+		a.logs.ErrorLog(err.Error())
+		return model.Principal{}, err
+	}
+	if err = a.checkActive(ctx, apiKey.UserId); err != nil {
+		return model.Principal{}, err
+	}
+	return model.Principal{
+		UserId:   apiKey.UserId,
+		ApiKeyId: apiKey.Id,
+		Scope:    apiKey.Scope,
+	}, nil
+}
+
+// CreateApiKey creates the API key of the user, read keys are created by
+// default. The key is returned only once and can not be got later.
+func (a *appImpl) CreateApiKey(ctx context.Context, key model.ApiKey) (model.ApiKey, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var userId uint64
+	if userId, _, err = a.writer(ctx); err != nil {
+		return model.ApiKey{}, err
+	}
+
+	key.UserId = userId
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Scope == "" {
+		key.Scope = model.ReadScope
+	}
+	if !checkApiKey(key.Name, key.Scope) || key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return model.ApiKey{}, model.ErrValidationError
+	}
+
+	var secret string
+	if secret, key.Prefix, key.KeyHash, err = auth.NewApiKey(); err != nil {
+		return model.ApiKey{}, err
+	}
+	if key, err = a.r.CreateApiKey(ctx, key); err != nil {
+		return model.ApiKey{}, err
+	}
+	key.Key = secret
+	return key, nil
+}
+
+// GetApiKeys returns API keys of the owner without the keys themselves,
+// users with the users:manage permission can get keys of any user and other
+// users only their own ones
+func (a *appImpl) GetApiKeys(ctx context.Context, ownerId uint64) ([]model.ApiKey, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var (
+		userId uint64
+		role   model.RolePermissions
+	)
+	if userId, role, err = a.caller(ctx); err != nil {
+		return nil, err
+	} else if ownerId != userId && !role.Has(model.UsersManage) {
+		return nil, model.ErrPermissionDenied
+	}
+
+	var keys []model.ApiKey
+	keys, err = a.r.GetApiKeys(ctx, ownerId)
+	return keys, err
+}
+
+// RevokeApiKey deletes the API key, users with the users:manage permission
+// can revoke keys of any user and other users only their own ones
+func (a *appImpl) RevokeApiKey(ctx context.Context, id uint64) error {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	var (
+		userId uint64
+		role   model.RolePermissions
+	)
+	if userId, role, err = a.writer(ctx); err != nil {
+		return err
+	}
+	var key model.ApiKey
+	if key, err = a.r.GetApiKey(ctx, id); err != nil {
+		return err
+	} else if key.UserId != userId && !role.Has(model.UsersManage) {
+		err = model.ErrPermissionDenied
+		return err
+	}
+
+	err = a.r.DeleteApiKey(ctx, id)
+	return err
+}
+
+// InitAdminPassword sets the password of the default admin created by the
+// migrations if the admin has no password yet, so the first login is
+// possible. Passwords set before are not changed and the password is not
+// even read then, so it is not needed after the first start.
+func (a *appImpl) InitAdminPassword(ctx context.Context, password func() (string, error)) error {
+	creds, err := a.r.GetUserCredentials(ctx, defaultAdminUsername)
+	if errors.Is(err, model.ErrUserNotExists) || err == nil && creds.PasswordHash != "" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	secret, err := password()
+	if err != nil {
+		return err
+	}
+	if !checkPassword(secret) {
+		return model.ErrValidationError
+	}
+	hash, err := auth.HashPassword(secret)
+	if err != nil {
+		return err
+	}
+	return a.r.SetPasswordHash(ctx, creds.UserId, hash)
+}
+
+// userRole returns the role of the active user with its permissions, roles
+// are cached for a short time
+func (a *appImpl) userRole(ctx context.Context, userId uint64) (model.RolePermissions, error) {
+	cached, generation, ok := a.roles.get(userId)
+	if ok {
+		return cached, nil
+	}
+
+	name, err := a.r.GetUserRole(ctx, userId)
+	if err != nil {
+		return model.RolePermissions{}, err
+	}
+	role, err := a.r.GetRole(ctx, name)
+	if err != nil {
+		return model.RolePermissions{}, err
+	}
+	a.roles.put(userId, role, generation)
+	return role, nil
+}
+
+// checkActive returns model.ErrInvalidToken if the user of the credentials is
+// deleted or disabled. The role is cached, so the methods called for the
+// same request do not query it again.
+func (a *appImpl) checkActive(ctx context.Context, userId uint64) error {
+	_, err := a.userRole(ctx, userId)
+	if errors.Is(err, model.ErrUserNotExists) {
+		return errors.Join(model.ErrInvalidToken, err)
+	} else if err != nil {
+		a.logs.ErrorLog(err.Error())
+		return err
+	}
+	return nil
+}
+
+// caller returns the id and the role of the user who makes the request, it
+// returns model.ErrUnauthorized if the context has no principal or its user
+// is deleted or disabled
+func (a *appImpl) caller(ctx context.Context) (uint64, model.RolePermissions, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return 0, model.RolePermissions{}, model.ErrUnauthorized
+	}
+	role, err := a.userRole(ctx, principal.UserId)
+	if errors.Is(err, model.ErrUserNotExists) {
+		return 0, model.RolePermissions{}, errors.Join(model.ErrUnauthorized, err)
+	} else if err != nil {
+		return 0, model.RolePermissions{}, err
+	}
+	return principal.UserId, role, nil
+}
+
+// writer is like caller but for operations which change data, it returns
+// model.ErrPermissionDenied if the request is made with the API key which is
+// not allowed to change data
+func (a *appImpl) writer(ctx context.Context) (uint64, model.RolePermissions, error) {
+	userId, role, err := a.caller(ctx)
+	if err != nil {
+		return 0, model.RolePermissions{}, err
+	}
+	if err = checkWriteScope(ctx); err != nil {
+		return 0, model.RolePermissions{}, err
+	}
+	return userId, role, nil
+}
+
+// authorize returns the id and the role of the user who makes the request if
+// the role grants the permission and model.ErrPermissionDenied otherwise
+func (a *appImpl) authorize(ctx context.Context, permission model.Permission) (uint64, model.RolePermissions, error) {
+	userId, role, err := a.caller(ctx)
+	if err != nil {
+		return 0, model.RolePermissions{}, err
+	}
+	if !role.Has(permission) {
+		return 0, model.RolePermissions{}, model.ErrPermissionDenied
+	}
+	return userId, role, nil
+}
+
+// authorizeWrite is like authorize but for operations which change data, as
+// writer it denies requests made with read-scoped API keys
+func (a *appImpl) authorizeWrite(ctx context.Context, permission model.Permission) (uint64, model.RolePermissions, error) {
+	userId, role, err := a.authorize(ctx, permission)
+	if err != nil {
+		return 0, model.RolePermissions{}, err
+	}
+	if err = checkWriteScope(ctx); err != nil {
+		return 0, model.RolePermissions{}, err
+	}
+	return userId, role, nil
+}
+
+// checkWriteScope returns model.ErrPermissionDenied unless the principal of
+// the request is allowed to change data, access tokens always are and API
+// keys only with the write scope
+func checkWriteScope(ctx context.Context) error {
+	if principal, _ := PrincipalFromContext(ctx); principal.Scope != model.WriteScope {
+		return model.ErrPermissionDenied
+	}
+	return nil
+}
+
+// checkManagedRole checks that the manager can manage accounts with the role.
+// Only admins can manage admins and other managers only accounts whose roles
+// grant no permissions the manager does not hold, so managers can not take
+// over more privileged accounts or grant more than they have.
+func (a *appImpl) checkManagedRole(ctx context.Context, manager model.RolePermissions, name model.Role) error {
+	if name == model.Admin && manager.Role != model.Admin {
+		return model.ErrPermissionDenied
+	}
+	role, err := a.r.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+	return checkGrantedPermissions(manager, role.Permissions)
+}
+
+// issueTokens signs the access token of the session
+func (a *appImpl) issueTokens(session model.Session, refreshToken string) (model.Tokens, error) {
+	accessToken, expiresAt, err := a.tokens.AccessToken(session.UserId, session.Id)
+	if err != nil {
+		return model.Tokens{}, err
+	}
+	return model.Tokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (a *appImpl) GetPoolStats(ctx context.Context) (model.PoolStats, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			a.logs.ErrorLog(err.Error())
+		}
+	}()
+
+	if _, _, err = a.authorize(ctx, model.StatsRead); err != nil {
+		return model.PoolStats{}, err
+	}
+
+	return a.r.PoolStats(ctx), nil
+}
+
+// checkPage validates the page limit and sets the default one if it is not set
+func checkPage(page model.Page) (model.Page, error) {
+	if page.Limit == 0 {
+		page.Limit = model.DefaultPageLimit
+	}
+	if page.Limit < 0 || page.Limit > model.MaxPageLimit {
+		return model.Page{}, model.ErrValidationError
+	}
+	return page, nil
+}
+
+// checkMovieFilter validates ranges and enumerations of the filter
+func checkMovieFilter(filter model.MovieFilter) error {
+	if filter.ReleaseDateFrom != nil && filter.ReleaseDateTo != nil &&
+		filter.ReleaseDateFrom.After(*filter.ReleaseDateTo) ||
+		filter.RatingFrom != nil && filter.RatingTo != nil && *filter.RatingFrom > *filter.RatingTo {
+		return model.ErrValidationError
+	}
+
+	switch filter.ActorsMatch {
+	case "", model.MatchAny, model.MatchAll:
+	default:
+		return model.ErrValidationError
+	}
+
+	switch filter.ActorGender {
+	case model.Unknown, model.Male, model.Female:
+	default:
+		return model.ErrValidationError
+	}
+
+	switch filter.GenresMatch {
+	case "", model.MatchAny, model.MatchAll:
+	default:
+		return model.ErrValidationError
+	}
+	return nil
+}
+
+// checkCast checks that billing positions are not negative and names of
+// characters are from 1 to 100 characters long
+func checkCast(cast []model.CastCredit) bool {
+	for _, credit := range cast {
+		if credit.Billing < 0 {
+			return false
+		}
+		for _, character := range credit.Characters {
+			if strings.TrimSpace(character) == "" || len([]rune(character)) > 100 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkCrew checks that all credits have crew roles
+func checkCrew(crew []model.CrewCredit) bool {
+	for _, credit := range crew {
+		found := false
+		for _, role := range model.CrewRoles {
+			if credit.Role == role {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// checkGenreName checks that the trimmed name of the genre is from 1 to 50
+// characters long
+func checkGenreName(name string) bool {
+	return len([]rune(name)) >= 1 && len([]rune(name)) <= 50
+}
+
+// checkReview validates the rating and the trimmed text of the review
+func checkReview(rating int, text string) bool {
+	return rating >= model.MinReviewRating && rating <= model.MaxReviewRating &&
+		len([]rune(text)) <= 5000
+}
+
+// checkUser validates the trimmed username and the email of the user.
+// Usernames are from 3 to 50 letters, digits and symbols "_", "." and "-",
+// the email is optional.
+func checkUser(username, email string) bool {
+	if len([]rune(username)) < 3 || len([]rune(username)) > 50 {
+		return false
+	}
+	for _, c := range username {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune("_.-", c) {
+			return false
+		}
+	}
+	if email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil || address.Address != email || len([]rune(email)) > 254 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkGrantedPermissions checks that the manager of roles holds all
+// permissions granted to the role, so managers can not grant themselves or
+// other users more than they have
+func checkGrantedPermissions(manager model.RolePermissions, permissions []model.Permission) error {
+	for _, p := range permissions {
+		if !manager.Has(p) {
+			return model.ErrPermissionDenied
+		}
+	}
+	return nil
+}
+
+// checkRole validates the trimmed name of the role and its permissions.
+// Names are from 1 to 20 lowercase latin letters, digits and symbols "_" and
+// "-".
+func checkRole(name model.Role, permissions []model.Permission) bool {
+	if len(name) < 1 || len(name) > 20 {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' && c != '-' {
+			return false
+		}
+	}
+	for _, p := range permissions {
+		if !slices.Contains(model.Permissions, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPassword checks the length of the password in bytes
+func checkPassword(password string) bool {
+	return len(password) >= auth.MinPasswordLength && len(password) <= auth.MaxPasswordLength
+}
+
+// checkApiKey validates the trimmed name of the API key and its scope
+func checkApiKey(name string, scope model.ApiKeyScope) bool {
+	return len([]rune(name)) >= 1 && len([]rune(name)) <= 100 &&
+		(scope == model.ReadScope || scope == model.WriteScope)
+}
+
+// checkCollection validates the trimmed name and the description of the
+// collection and its visibility
+func checkCollection(name, description string, visibility model.Visibility) bool {
+	return len([]rune(name)) >= 1 && len([]rune(name)) <= 100 &&
+		len([]rune(description)) <= 1000 &&
+		(visibility == model.Private || visibility == model.Public)
+}
+
+// checkCollectionNote checks that the trimmed note is at most 500 characters
+// long
+func checkCollectionNote(note string) bool {
+	return len([]rune(note)) <= 500
+}
+
+// checkActorFilter validates enumerations of the filter
+func checkActorFilter(filter model.ActorFilter) error {
+	switch filter.Gender {
+	case model.Unknown, model.Male, model.Female:
+		return nil
+	default:
+		return model.ErrValidationError
+	}
+}
+
+// didYouMean returns the most similar title or name of the fuzzy search
+// result, titles are preferred when similarity is equal
+func didYouMean(res model.FuzzySearchResult) string {
+	var (
+		suggestion string
+		best       float64
+	)
+	if len(res.Movies) != 0 {
+		suggestion, best = res.Movies[0].Title, res.Movies[0].Relevance
+	}
+	if len(res.Actors) != 0 && res.Actors[0].Relevance > best {
+		suggestion = res.Actors[0].FirstName + " " + res.Actors[0].SecondName
+	}
+	return suggestion
+}
+
+// checkSort checks that all params of the sort are allowed and not repeated
+func checkSort(sortBy model.Sort, allowed []model.SortParam) error {
+	used := make(map[model.SortParam]struct{}, len(sortBy))
+	for _, key := range sortBy {
+		if _, ok := used[key.Param]; ok {
+			return model.ErrValidationError
+		}
+		used[key.Param] = struct{}{}
+
+		found := false
+		for _, param := range allowed {
+			if key.Param == param {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return model.ErrValidationError
+		}
+	}
+	return nil
+}
@@ -2,29 +2,103 @@ package app
 
 import (
 	"context"
+	"movie-lib/internal/auth"
 	"movie-lib/internal/model"
 	"movie-lib/internal/repo"
 	"movie-lib/pkg/logger"
+	"time"
 )
 
+// App acts on behalf of the principal stored in the context by WithPrincipal,
+// methods other than the authentication ones return model.ErrUnauthorized if
+// the context has no principal or its user is deleted or disabled
 type App interface {
-	CreateMovie(ctx context.Context, userId uint64, movie model.Movie) (model.Movie, error)
-	UpdateMovie(ctx context.Context, userId uint64, id uint64, upd model.UpdateMovie) (model.Movie, error)
-	DeleteMovie(ctx context.Context, userId uint64, id uint64) error
-	GetMovie(ctx context.Context, userId uint64, id uint64) (model.Movie, error)
-	GetMovies(ctx context.Context, userId uint64, sortBy model.SortParam) ([]model.Movie, error)
-	SearchMovies(ctx context.Context, userId uint64, pattern string) ([]model.Movie, error)
-
-	CreateActor(ctx context.Context, userId uint64, actor model.Actor) (model.Actor, error)
-	UpdateActor(ctx context.Context, userId uint64, id uint64, upd model.UpdateActor) (model.Actor, error)
-	DeleteActor(ctx context.Context, userId uint64, id uint64) error
-	GetActor(ctx context.Context, userId uint64, id uint64) (model.Actor, error)
-	GetActors(ctx context.Context, userId uint64) ([]model.Actor, error)
+	CreateMovie(ctx context.Context, movie model.Movie) (model.Movie, error)
+	UpdateMovie(ctx context.Context, id uint64, upd model.UpdateMovie) (model.Movie, error)
+	DeleteMovie(ctx context.Context, id uint64) error
+	GetMovie(ctx context.Context, id uint64) (model.Movie, error)
+	GetMovies(ctx context.Context, filter model.MovieFilter, sortBy model.Sort, page model.Page) (model.MovieList, error)
+	SearchMovies(ctx context.Context, query string, filter model.MovieFilter, sortBy model.Sort, page model.Page) (model.MovieList, error)
+
+	CreateActor(ctx context.Context, actor model.Actor) (model.Actor, error)
+	UpdateActor(ctx context.Context, id uint64, upd model.UpdateActor) (model.Actor, error)
+	DeleteActor(ctx context.Context, id uint64) error
+	GetActor(ctx context.Context, id uint64) (model.Actor, error)
+	GetActors(ctx context.Context, filter model.ActorFilter, sortBy model.Sort, page model.Page) (model.ActorList, error)
+	SearchActors(ctx context.Context, pattern string, filter model.ActorFilter, sortBy model.Sort, page model.Page) (model.ActorList, error)
+	GetFilmography(ctx context.Context, personId uint64) (model.Filmography, error)
+
+	CreateGenre(ctx context.Context, genre model.Genre) (model.Genre, error)
+	UpdateGenre(ctx context.Context, id uint64, upd model.UpdateGenre) (model.Genre, error)
+	DeleteGenre(ctx context.Context, id uint64) error
+	GetGenre(ctx context.Context, id uint64) (model.Genre, error)
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+
+	CreateReview(ctx context.Context, review model.Review) (model.Review, error)
+	UpdateReview(ctx context.Context, id uint64, upd model.UpdateReview) (model.Review, error)
+	DeleteReview(ctx context.Context, id uint64) error
+	GetReview(ctx context.Context, id uint64) (model.Review, error)
+	GetReviews(ctx context.Context, filter model.ReviewFilter, page model.Page) (model.ReviewList, error)
+
+	AddToWatchlist(ctx context.Context, movieId uint64) (model.Movie, error)
+	RemoveFromWatchlist(ctx context.Context, movieId uint64) error
+	MarkWatched(ctx context.Context, movieId uint64, watchedAt time.Time) (model.Movie, error)
+	UnmarkWatched(ctx context.Context, movieId uint64) error
+
+	CreateCollection(ctx context.Context, collection model.Collection) (model.Collection, error)
+	UpdateCollection(ctx context.Context, id uint64, upd model.UpdateCollection) (model.Collection, error)
+	DeleteCollection(ctx context.Context, id uint64) error
+	GetCollection(ctx context.Context, id uint64) (model.Collection, error)
+	GetCollections(ctx context.Context, filter model.CollectionFilter, page model.Page) (model.CollectionList, error)
+	AddCollectionEntry(ctx context.Context, collectionId uint64, entry model.CollectionEntry) (model.Collection, error)
+	UpdateCollectionEntry(ctx context.Context, collectionId uint64, entry model.CollectionEntry) (model.Collection, error)
+	RemoveCollectionEntry(ctx context.Context, collectionId uint64, movieId uint64) error
+	ReorderCollection(ctx context.Context, collectionId uint64, moviesId []uint64) (model.Collection, error)
+
+	FuzzySearch(ctx context.Context, query string, limit int) (model.FuzzySearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error)
+
+	CreateUser(ctx context.Context, user model.User, password string) (model.User, error)
+	UpdateUser(ctx context.Context, id uint64, upd model.UpdateUser) (model.User, error)
+	DeleteUser(ctx context.Context, id uint64) error
+	GetUser(ctx context.Context, id uint64) (model.User, error)
+	GetUsers(ctx context.Context, page model.Page) (model.UserList, error)
+	SetPassword(ctx context.Context, id uint64, password string, currentPassword string) error
+
+	GetRoles(ctx context.Context) ([]model.RolePermissions, error)
+	GetRole(ctx context.Context, name model.Role) (model.RolePermissions, error)
+	CreateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error)
+	// UpdateRole replaces permissions of the role
+	UpdateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error)
+	DeleteRole(ctx context.Context, name model.Role) error
+
+	Login(ctx context.Context, username string, password string) (model.Tokens, error)
+	Refresh(ctx context.Context, refreshToken string) (model.Tokens, error)
+	Logout(ctx context.Context, all bool) error
+	// Authenticate returns the principal of the valid access token, it returns
+	// model.ErrInvalidToken for invalid, expired and revoked tokens and tokens
+	// of disabled users
+	Authenticate(ctx context.Context, accessToken string) (model.Principal, error)
+	// AuthenticateApiKey returns the principal of the API key, it returns
+	// model.ErrInvalidToken for unknown, expired and revoked keys and keys of
+	// disabled users
+	AuthenticateApiKey(ctx context.Context, key string) (model.Principal, error)
+	// InitAdminPassword sets the password of the default admin if it has no
+	// password yet, password is called only in this case
+	InitAdminPassword(ctx context.Context, password func() (string, error)) error
+
+	CreateApiKey(ctx context.Context, key model.ApiKey) (model.ApiKey, error)
+	GetApiKeys(ctx context.Context, ownerId uint64) ([]model.ApiKey, error)
+	RevokeApiKey(ctx context.Context, id uint64) error
+
+	GetPoolStats(ctx context.Context) (model.PoolStats, error)
 }
 
-func New(r repo.Repo, logs logger.Logger) App {
+func New(r repo.Repo, logs logger.Logger, tokens *auth.Issuer) App {
 	return &appImpl{
-		r:    r,
-		logs: logs,
+		r:      r,
+		logs:   logs,
+		tokens: tokens,
+		roles:  newRoleCache(roleCacheTTL),
 	}
 }
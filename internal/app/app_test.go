@@ -2,15 +2,15 @@ package app
 
 import (
 	"context"
-	"fmt"
-	"github.com/jackc/pgx/v5"
-	"github.com/spf13/viper"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"movie-lib/internal/auth"
 	"movie-lib/internal/model"
 	"movie-lib/internal/repo"
 	"movie-lib/pkg/logger"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -18,6 +18,8 @@ import (
 const (
 	adminUserId   = 1
 	regularUserId = 2
+
+	testPassword = "password1"
 )
 
 var (
@@ -88,47 +90,35 @@ var (
 	}
 )
 
+// as returns the context of the request made by the user
+func as(userId uint64) context.Context {
+	return WithPrincipal(ctx, model.Principal{UserId: userId, Scope: model.WriteScope})
+}
+
 type appTestSuite struct {
 	suite.Suite
 
 	moviesIdsToDelete []uint64
 	actorsIdsToDelete []uint64
+	genresIdsToDelete []uint64
 
-	conn    *pgx.Conn
 	service App
 }
 
 func (s *appTestSuite) SetupSuite() {
-	var err error
-	// setting configs
-	viper.SetConfigFile("../../config/config-local.yml")
-	err = viper.ReadInConfig()
-	if err != nil {
-		s.Fail("unable to read configs")
-	}
-	for i := 0; i < 30; i++ { // 30 attempts to connect to postgres
-		s.conn, err = pgx.Connect(context.Background(), fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-			viper.GetString("postgres-movie-lib.username"),
-			viper.GetString("postgres-movie-lib.password"),
-			viper.GetString("postgres-movie-lib.host"),
-			viper.GetInt("postgres-movie-lib.port"),
-			viper.GetString("postgres-movie-lib.dbname"),
-			viper.GetString("postgres-movie-lib.sslmode"),
-		))
-		if err == nil {
-			break
-		}
-		time.Sleep(time.Second)
-	}
-	if err != nil {
-		s.Fail("unable to connect to postgres")
-	}
-
-	s.service = New(repo.New(s.conn), logger.DefaultLogger(os.Stdout))
+	tokens, err := auth.NewIssuer(auth.Config{
+		SigningMethod:   auth.HS256,
+		HMACSecret:      []byte("test-secret-which-is-32-bytes-long"),
+		Issuer:          "movie-lib-test",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	s.Require().NoError(err)
+	s.service = New(repo.NewMemory(), logger.DefaultLogger(os.Stdout), tokens)
 
 	// add 3 actors to database
 	for i := 0; i < 3; i++ {
-		addedActor, _ := s.service.CreateActor(ctx, adminUserId, actors[i])
+		addedActor, _ := s.service.CreateActor(as(adminUserId), actors[i])
 		actors[i].Id = addedActor.Id
 		s.actorsIdsToDelete = append(s.actorsIdsToDelete, addedActor.Id)
 	}
@@ -139,13 +129,14 @@ func (s *appTestSuite) SetupSuite() {
 
 func (s *appTestSuite) TearDownSuite() {
 	for _, id := range s.moviesIdsToDelete {
-		_ = s.service.DeleteMovie(ctx, adminUserId, id)
+		_ = s.service.DeleteMovie(as(adminUserId), id)
 	}
 	for _, id := range s.actorsIdsToDelete {
-		_ = s.service.DeleteActor(ctx, adminUserId, id)
+		_ = s.service.DeleteActor(as(adminUserId), id)
+	}
+	for _, id := range s.genresIdsToDelete {
+		_ = s.service.DeleteGenre(as(adminUserId), id)
 	}
-
-	_ = s.conn.Close(context.Background())
 }
 
 type createMovieTest struct {
@@ -203,7 +194,7 @@ func (s *appTestSuite) TestCreateMovie() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			addedMovie, err := s.service.CreateMovie(ctx, test.user, *test.movie)
+			addedMovie, err := s.service.CreateMovie(as(test.user), *test.movie)
 			if err == nil {
 				test.movie.Id = addedMovie.Id
 				s.moviesIdsToDelete = append(s.moviesIdsToDelete, addedMovie.Id)
@@ -316,7 +307,7 @@ func (s *appTestSuite) TestUpdateMovie() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			updatedMovie, err := s.service.UpdateMovie(ctx, test.user, test.id, test.upd)
+			updatedMovie, err := s.service.UpdateMovie(as(test.user), test.id, test.upd)
 			assert.Equal(t, test.res.Id, updatedMovie.Id)
 			assert.Equal(t, test.res.Description, updatedMovie.Description)
 			assert.Equal(t, test.res.Rating, updatedMovie.Rating)
@@ -363,7 +354,7 @@ func (s *appTestSuite) TestDeleteMovie() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			err := s.service.DeleteMovie(ctx, test.user, test.id)
+			err := s.service.DeleteMovie(as(test.user), test.id)
 			assert.ErrorIs(t, err, test.err)
 		})
 	}
@@ -411,7 +402,7 @@ func (s *appTestSuite) TestGetMovie() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			gotMovie, err := s.service.GetMovie(ctx, test.user, test.id)
+			gotMovie, err := s.service.GetMovie(as(test.user), test.id)
 			assert.Equal(t, test.res.Id, gotMovie.Id)
 			assert.Equal(t, test.res.Title, gotMovie.Title)
 			assert.ErrorIs(t, err, test.err)
@@ -422,7 +413,9 @@ func (s *appTestSuite) TestGetMovie() {
 type getMoviesTest struct {
 	description string
 	user        uint64
-	sortBy      model.SortParam
+	filter      model.MovieFilter
+	sortBy      model.Sort
+	page        model.Page
 
 	// moviesIdList содержит в себе правильный порядок следования тестовых
 	// фильмов в списке всех фильмов
@@ -440,7 +433,7 @@ func (s *appTestSuite) TestGetMovies() {
 		{
 			description:  "getting of movies list with default sort params",
 			user:         adminUserId,
-			sortBy:       "",
+			sortBy:       nil,
 			moviesIdList: []uint64{movies[0].Id, movies[3].Id},
 			moviesIdSet: map[uint64]struct{}{
 				movies[0].Id: {},
@@ -451,7 +444,7 @@ func (s *appTestSuite) TestGetMovies() {
 		{
 			description:  "getting of movies list sorted by title",
 			user:         adminUserId,
-			sortBy:       model.Title,
+			sortBy:       model.ParseSort("title"),
 			moviesIdList: []uint64{movies[0].Id, movies[3].Id},
 			moviesIdSet: map[uint64]struct{}{
 				movies[0].Id: {},
@@ -462,7 +455,7 @@ func (s *appTestSuite) TestGetMovies() {
 		{
 			description:  "getting of movies list sorted by release date",
 			user:         adminUserId,
-			sortBy:       model.ReleaseDate,
+			sortBy:       model.ParseSort("release_date"),
 			moviesIdList: []uint64{movies[0].Id, movies[3].Id},
 			moviesIdSet: map[uint64]struct{}{
 				movies[0].Id: {},
@@ -473,7 +466,29 @@ func (s *appTestSuite) TestGetMovies() {
 		{
 			description:  "getting of movies list sorted by rating",
 			user:         adminUserId,
-			sortBy:       model.Rating,
+			sortBy:       model.ParseSort("rating"),
+			moviesIdList: []uint64{movies[3].Id, movies[0].Id},
+			moviesIdSet: map[uint64]struct{}{
+				movies[0].Id: {},
+				movies[3].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description:  "getting of movies list sorted by title descending",
+			user:         adminUserId,
+			sortBy:       model.ParseSort("-title"),
+			moviesIdList: []uint64{movies[3].Id, movies[0].Id},
+			moviesIdSet: map[uint64]struct{}{
+				movies[0].Id: {},
+				movies[3].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description:  "getting of movies list sorted by several params",
+			user:         adminUserId,
+			sortBy:       model.ParseSort("-release_date,title"),
 			moviesIdList: []uint64{movies[3].Id, movies[0].Id},
 			moviesIdSet: map[uint64]struct{}{
 				movies[0].Id: {},
@@ -481,10 +496,34 @@ func (s *appTestSuite) TestGetMovies() {
 			},
 			err: nil,
 		},
+		{
+			description:  "getting of movies list with unknown sort param",
+			user:         adminUserId,
+			sortBy:       model.ParseSort("title,unknown"),
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list sorted by relevance",
+			user:         adminUserId,
+			sortBy:       model.ParseSort("-relevance"),
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list with repeated sort param",
+			user:         adminUserId,
+			sortBy:       model.ParseSort("title,-title"),
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
 		{
 			description:  "getting of movies list with no admin rights",
 			user:         regularUserId,
-			sortBy:       "",
+			sortBy:       nil,
 			moviesIdList: []uint64{movies[0].Id, movies[3].Id},
 			moviesIdSet: map[uint64]struct{}{
 				movies[0].Id: {},
@@ -492,17 +531,76 @@ func (s *appTestSuite) TestGetMovies() {
 			},
 			err: nil,
 		},
+		{
+			description:  "getting of movies list filtered by rating",
+			user:         adminUserId,
+			filter:       model.MovieFilter{RatingFrom: floatPtr(4)},
+			moviesIdList: []uint64{movies[0].Id},
+			moviesIdSet: map[uint64]struct{}{
+				movies[0].Id: {},
+				movies[3].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description:  "getting of movies list with invalid rating range",
+			user:         adminUserId,
+			filter:       model.MovieFilter{RatingFrom: floatPtr(5), RatingTo: floatPtr(4)},
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list with invalid actors match",
+			user:         adminUserId,
+			filter:       model.MovieFilter{ActorsId: []uint64{1}, ActorsMatch: "some"},
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list with invalid genres match",
+			user:         adminUserId,
+			filter:       model.MovieFilter{GenresId: []uint64{1}, GenresMatch: "some"},
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list with negative limit",
+			user:         adminUserId,
+			page:         model.Page{Limit: -1},
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list with too big limit",
+			user:         adminUserId,
+			page:         model.Page{Limit: model.MaxPageLimit + 1},
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrValidationError,
+		},
+		{
+			description:  "getting of movies list with invalid cursor",
+			user:         adminUserId,
+			page:         model.Page{Cursor: "invalid"},
+			moviesIdList: []uint64{},
+			moviesIdSet:  map[uint64]struct{}{},
+			err:          model.ErrInvalidCursor,
+		},
 	}
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			gotMoviesList, err := s.service.GetMovies(ctx, test.user, test.sortBy)
+			gotMoviesList, err := s.service.GetMovies(as(test.user), test.filter, test.sortBy, test.page)
 			assert.ErrorIs(s.T(), err, test.err)
 
 			// Здесь происходит проверка на то, что тестовые фильмы в списке всех
 			// фильмов располагаются в правильном порядке, т.е. правильно отсортированы
 			moviesSequence := make([]uint64, 0, len(test.moviesIdList))
-			for _, m := range gotMoviesList {
+			for _, m := range gotMoviesList.Movies {
 				if _, ok := test.moviesIdSet[m.Id]; ok {
 					moviesSequence = append(moviesSequence, m.Id)
 				}
@@ -513,10 +611,15 @@ func (s *appTestSuite) TestGetMovies() {
 	}
 }
 
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
 type searchMoviesTest struct {
 	description string
 	user        uint64
 	pattern     string
+	sortBy      model.Sort
 
 	// moviesIdSet содержит в себе id тестовых фильмов, которые должны быть в
 	// списке найденных фильмов
@@ -545,6 +648,33 @@ func (s *appTestSuite) TestSearchMovies() {
 			},
 			err: nil,
 		},
+		{
+			description: "getting film by pattern in lower case",
+			user:        adminUserId,
+			pattern:     "testmovie01",
+			moviesIdSet: map[uint64]struct{}{
+				movies[0].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description: "getting film by pattern sorted by relevance",
+			user:        adminUserId,
+			pattern:     "TestMovie01",
+			sortBy:      model.ParseSort("-relevance,title"),
+			moviesIdSet: map[uint64]struct{}{
+				movies[0].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description: "getting film by pattern with unknown sort param",
+			user:        adminUserId,
+			pattern:     "TestMovie01",
+			sortBy:      model.ParseSort("movies_count"),
+			moviesIdSet: map[uint64]struct{}{},
+			err:         model.ErrValidationError,
+		},
 		{
 			description: "getting film by pattern with no admin rights",
 			user:        regularUserId,
@@ -558,13 +688,13 @@ func (s *appTestSuite) TestSearchMovies() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			gotMoviesList, err := s.service.SearchMovies(ctx, test.user, test.pattern)
+			gotMoviesList, err := s.service.SearchMovies(as(test.user), test.pattern, model.MovieFilter{}, test.sortBy, model.Page{})
 			assert.ErrorIs(s.T(), err, test.err)
 
 			// Здесь происходит проверка на то, что все фильмы, которые нужно
 			// найти, содержатся в полученном списке
 			gotMoviesSet := make(map[uint64]struct{})
-			for _, m := range gotMoviesList {
+			for _, m := range gotMoviesList.Movies {
 				if _, ok := test.moviesIdSet[m.Id]; ok {
 					gotMoviesSet[m.Id] = struct{}{}
 				}
@@ -606,7 +736,7 @@ func (s *appTestSuite) TestCreateActor() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			addedActor, err := s.service.CreateActor(ctx, test.user, *test.actor)
+			addedActor, err := s.service.CreateActor(as(test.user), *test.actor)
 			if addedActor.Id != 0 {
 				s.actorsIdsToDelete = append(s.actorsIdsToDelete, addedActor.Id)
 				test.actor.Id = addedActor.Id
@@ -684,7 +814,7 @@ func (s *appTestSuite) TestUpdateActor() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			updatedActor, err := s.service.UpdateActor(ctx, test.user, test.id, test.upd)
+			updatedActor, err := s.service.UpdateActor(as(test.user), test.id, test.upd)
 			assert.Equal(t, test.res.Id, updatedActor.Id)
 			assert.Equal(t, test.res.FirstName, updatedActor.FirstName)
 			assert.Equal(t, test.res.SecondName, updatedActor.SecondName)
@@ -731,7 +861,7 @@ func (s *appTestSuite) TestDeleteActor() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			err := s.service.DeleteActor(ctx, test.user, test.id)
+			err := s.service.DeleteActor(as(test.user), test.id)
 			assert.ErrorIs(t, err, test.err)
 		})
 	}
@@ -779,7 +909,7 @@ func (s *appTestSuite) TestGetActor() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			gotActor, err := s.service.GetActor(ctx, test.user, test.id)
+			gotActor, err := s.service.GetActor(as(test.user), test.id)
 			assert.Equal(t, test.res.Id, gotActor.Id)
 			assert.Equal(t, test.res.FirstName, gotActor.FirstName)
 			assert.ErrorIs(t, err, test.err)
@@ -790,6 +920,8 @@ func (s *appTestSuite) TestGetActor() {
 type getActorsTest struct {
 	description string
 	user        uint64
+	filter      model.ActorFilter
+	sortBy      model.Sort
 	actorsIds   map[uint64]struct{}
 	err         error
 }
@@ -816,6 +948,31 @@ func (s *appTestSuite) TestGetActors() {
 			},
 			err: nil,
 		},
+		{
+			description: "getting of list of actors sorted by name and number of movies",
+			user:        adminUserId,
+			sortBy:      model.ParseSort("name,-movies_count"),
+			actorsIds: map[uint64]struct{}{
+				actors[0].Id: {},
+				actors[1].Id: {},
+				actors[3].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description: "getting of list of actors with movie sort param",
+			user:        adminUserId,
+			sortBy:      model.ParseSort("rating"),
+			actorsIds:   map[uint64]struct{}{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "getting of list of actors with invalid gender",
+			user:        adminUserId,
+			filter:      model.ActorFilter{Gender: "unknown"},
+			actorsIds:   map[uint64]struct{}{},
+			err:         model.ErrValidationError,
+		},
 		{
 			description: "getting of list of actors with non existing user",
 			user:        0,
@@ -826,9 +983,9 @@ func (s *appTestSuite) TestGetActors() {
 
 	for _, test := range tests {
 		s.T().Run(test.description, func(t *testing.T) {
-			actorsList, err := s.service.GetActors(ctx, test.user)
+			actorsList, err := s.service.GetActors(as(test.user), test.filter, test.sortBy, model.Page{})
 			actorsIdSet := make(map[uint64]struct{})
-			for _, actor := range actorsList {
+			for _, actor := range actorsList.Actors {
 				if _, ok := test.actorsIds[actor.Id]; ok {
 					actorsIdSet[actor.Id] = struct{}{}
 				}
@@ -839,6 +996,1522 @@ func (s *appTestSuite) TestGetActors() {
 	}
 }
 
+type searchActorsTest struct {
+	description string
+	user        uint64
+	pattern     string
+	filter      model.ActorFilter
+
+	// actorsIds содержит в себе id тестовых актёров, которые должны быть в
+	// списке найденных актёров
+	actorsIds map[uint64]struct{}
+
+	err error
+}
+
+func (s *appTestSuite) TestSearchActors() {
+	tests := []searchActorsTest{
+		{
+			description: "searching of actors by name ignoring case",
+			user:        regularUserId,
+			pattern:     "testactor01",
+			actorsIds: map[uint64]struct{}{
+				actors[0].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description: "searching of actors by part of name",
+			user:        regularUserId,
+			pattern:     "Actor0",
+			actorsIds: map[uint64]struct{}{
+				actors[0].Id: {},
+				actors[1].Id: {},
+				actors[3].Id: {},
+			},
+			err: nil,
+		},
+		{
+			description: "searching of actors by name and gender",
+			user:        regularUserId,
+			pattern:     "TestActor01",
+			filter:      model.ActorFilter{Gender: model.Female},
+			actorsIds:   map[uint64]struct{}{},
+			err:         nil,
+		},
+		{
+			description: "searching of actors with invalid gender",
+			user:        regularUserId,
+			pattern:     "TestActor01",
+			filter:      model.ActorFilter{Gender: "unknown"},
+			actorsIds:   map[uint64]struct{}{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "searching of actors with non existing user",
+			user:        0,
+			pattern:     "TestActor01",
+			actorsIds:   map[uint64]struct{}{},
+			err:         model.ErrUserNotExists,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			actorsList, err := s.service.SearchActors(as(test.user), test.pattern, test.filter, nil, model.Page{})
+			assert.ErrorIs(t, err, test.err)
+
+			actorsIdSet := make(map[uint64]struct{})
+			for _, actor := range actorsList.Actors {
+				if _, ok := test.actorsIds[actor.Id]; ok {
+					actorsIdSet[actor.Id] = struct{}{}
+				}
+				assert.NotNil(t, actor.Movies)
+			}
+			assert.Equal(t, test.actorsIds, actorsIdSet)
+		})
+	}
+}
+
+func (s *appTestSuite) TestGetFilmography() {
+	person, err := s.service.CreateActor(as(adminUserId), model.Actor{FirstName: "TestDirector"})
+	s.Require().NoError(err)
+	s.actorsIdsToDelete = append(s.actorsIdsToDelete, person.Id)
+
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Director",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+		CrewCredits: []model.CrewCredit{{PersonId: person.Id, Role: model.DirectorRole}},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	s.Equal([]model.CrewMember{{Person: person, Role: model.DirectorRole}}, movie.Crew)
+
+	_, err = s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Invalid Role",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		CrewCredits: []model.CrewCredit{{PersonId: person.Id, Role: "stuntman"}},
+	})
+	s.ErrorIs(err, model.ErrValidationError)
+
+	filmography, err := s.service.GetFilmography(as(regularUserId), person.Id)
+	s.Require().NoError(err)
+	s.Equal(person.Id, filmography.Person.Id)
+	s.Require().Len(filmography.Credits[model.DirectorRole], 1)
+	s.Equal(movie.Id, filmography.Credits[model.DirectorRole][0].Id)
+	s.Empty(filmography.Credits[model.ActorRole])
+
+	_, err = s.service.GetFilmography(as(regularUserId), 0)
+	s.ErrorIs(err, model.ErrPersonNotExists)
+
+	_, err = s.service.GetFilmography(as(0), person.Id)
+	s.ErrorIs(err, model.ErrUserNotExists)
+}
+
+func (s *appTestSuite) TestMovieCast() {
+	actor, err := s.service.CreateActor(as(adminUserId), model.Actor{FirstName: "TestCastActor"})
+	s.Require().NoError(err)
+	s.actorsIdsToDelete = append(s.actorsIdsToDelete, actor.Id)
+
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Cast",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+		Cast:        []model.CastCredit{{ActorId: actor.Id, Characters: []string{"Neo"}, Billing: 1}},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	s.Require().Len(movie.Actors, 1)
+	s.Equal([]string{"Neo"}, movie.Actors[0].Characters)
+	s.Equal(1, movie.Actors[0].Billing)
+
+	for _, cast := range [][]model.CastCredit{
+		{{ActorId: actor.Id, Characters: []string{" "}}},
+		{{ActorId: actor.Id, Characters: []string{strings.Repeat("c", 101)}}},
+		{{ActorId: actor.Id, Billing: -1}},
+	} {
+		_, err = s.service.UpdateMovie(as(adminUserId), movie.Id, model.UpdateMovie{
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Rating:      movie.Rating,
+			Cast:        cast,
+		})
+		s.ErrorIs(err, model.ErrValidationError)
+	}
+}
+
+type createGenreTest struct {
+	description string
+	user        uint64
+	genre       model.Genre
+	res         model.Genre
+	err         error
+}
+
+func (s *appTestSuite) TestCreateGenre() {
+	tests := []createGenreTest{
+		{
+			description: "successful creation of the genre with trimmed name",
+			user:        adminUserId,
+			genre:       model.Genre{Name: " TestGenre01 "},
+			res:         model.Genre{Name: "TestGenre01"},
+			err:         nil,
+		},
+		{
+			description: "creation of the genre with existing name",
+			user:        adminUserId,
+			genre:       model.Genre{Name: "testgenre01"},
+			res:         model.Genre{},
+			err:         model.ErrConflict,
+		},
+		{
+			description: "creation of the genre with blank name",
+			user:        adminUserId,
+			genre:       model.Genre{Name: "  "},
+			res:         model.Genre{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creation of the genre with too long name",
+			user:        adminUserId,
+			genre:       model.Genre{Name: strings.Repeat("g", 51)},
+			res:         model.Genre{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creation of the genre with no admin rights",
+			user:        regularUserId,
+			genre:       model.Genre{Name: "TestGenre02"},
+			res:         model.Genre{},
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "creation of the genre with non existing user",
+			user:        0,
+			genre:       model.Genre{Name: "TestGenre02"},
+			res:         model.Genre{},
+			err:         model.ErrUserNotExists,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			genre, err := s.service.CreateGenre(as(test.user), test.genre)
+			if genre.Id != 0 {
+				s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+			}
+			assert.Equal(t, test.res.Name, genre.Name)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+type updateGenreTest struct {
+	description string
+	user        uint64
+	id          uint64
+	upd         model.UpdateGenre
+	res         model.Genre
+	err         error
+}
+
+func (s *appTestSuite) TestUpdateGenre() {
+	genre, err := s.service.CreateGenre(as(adminUserId), model.Genre{Name: "TestGenre03"})
+	s.Require().NoError(err)
+	s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+
+	tests := []updateGenreTest{
+		{
+			description: "successful update of the genre",
+			user:        adminUserId,
+			id:          genre.Id,
+			upd:         model.UpdateGenre{Name: "TestGenre04"},
+			res:         model.Genre{Id: genre.Id, Name: "TestGenre04"},
+			err:         nil,
+		},
+		{
+			description: "update of the genre with blank name",
+			user:        adminUserId,
+			id:          genre.Id,
+			upd:         model.UpdateGenre{Name: ""},
+			res:         model.Genre{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "update of non existing genre",
+			user:        adminUserId,
+			id:          0,
+			upd:         model.UpdateGenre{Name: "TestGenre05"},
+			res:         model.Genre{},
+			err:         model.ErrGenreNotExists,
+		},
+		{
+			description: "update of the genre with no admin rights",
+			user:        regularUserId,
+			id:          genre.Id,
+			upd:         model.UpdateGenre{Name: "TestGenre05"},
+			res:         model.Genre{},
+			err:         model.ErrPermissionDenied,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			updated, err := s.service.UpdateGenre(as(test.user), test.id, test.upd)
+			assert.Equal(t, test.res, updated)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+type deleteGenreTest struct {
+	description string
+	user        uint64
+	id          uint64
+	err         error
+}
+
+func (s *appTestSuite) TestDeleteGenre() {
+	genre, err := s.service.CreateGenre(as(adminUserId), model.Genre{Name: "TestGenre06"})
+	s.Require().NoError(err)
+
+	tests := []deleteGenreTest{
+		{
+			description: "deleting of the genre with no admin rights",
+			user:        regularUserId,
+			id:          genre.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "successful deleting of the genre",
+			user:        adminUserId,
+			id:          genre.Id,
+			err:         nil,
+		},
+		{
+			description: "deleting of non existing genre",
+			user:        adminUserId,
+			id:          genre.Id,
+			err:         model.ErrGenreNotExists,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			err := s.service.DeleteGenre(as(test.user), test.id)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func (s *appTestSuite) TestMovieGenres() {
+	genre, err := s.service.CreateGenre(as(adminUserId), model.Genre{Name: "TestGenre07"})
+	s.Require().NoError(err)
+	s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Genre",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+		GenresId:    []uint64{genre.Id},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	s.Equal([]model.Genre{genre}, movie.Genres)
+
+	list, err := s.service.GetMovies(as(regularUserId), model.MovieFilter{GenresId: []uint64{genre.Id}}, nil, model.Page{})
+	s.Require().NoError(err)
+	s.Equal(uint64(1), list.Total)
+	s.Equal(movie.Id, list.Movies[0].Id)
+
+	genres, err := s.service.GetGenres(as(regularUserId))
+	s.Require().NoError(err)
+	s.Contains(genres, genre)
+
+	_, err = s.service.GetGenres(as(0))
+	s.ErrorIs(err, model.ErrUserNotExists)
+
+	got, err := s.service.GetGenre(as(regularUserId), genre.Id)
+	s.Require().NoError(err)
+	s.Equal(genre, got)
+
+	_, err = s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Unknown Genre",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		GenresId:    []uint64{0},
+	})
+	s.ErrorIs(err, model.ErrGenreNotExists)
+}
+
+type fuzzySearchTest struct {
+	description string
+	user        uint64
+	query       string
+	limit       int
+
+	moviesIds  []uint64
+	didYouMean string
+
+	err error
+}
+
+type createReviewTest struct {
+	description string
+	user        uint64
+	review      model.Review
+	err         error
+}
+
+func (s *appTestSuite) TestCreateReview() {
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Reviews",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	tests := []createReviewTest{
+		{
+			description: "successful creation of the review by regular user",
+			user:        regularUserId,
+			review:      model.Review{MovieId: movie.Id, Rating: 6, Text: " Good "},
+			err:         nil,
+		},
+		{
+			description: "creation of the second review of the same movie",
+			user:        regularUserId,
+			review:      model.Review{MovieId: movie.Id, Rating: 7},
+			err:         model.ErrConflict,
+		},
+		{
+			description: "creation of the review with too low rating",
+			user:        adminUserId,
+			review:      model.Review{MovieId: movie.Id, Rating: 0},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creation of the review with too high rating",
+			user:        adminUserId,
+			review:      model.Review{MovieId: movie.Id, Rating: 11},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creation of the review with too long text",
+			user:        adminUserId,
+			review:      model.Review{MovieId: movie.Id, Rating: 5, Text: strings.Repeat("t", 5001)},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creation of the review of non existing movie",
+			user:        adminUserId,
+			review:      model.Review{MovieId: 0, Rating: 5},
+			err:         model.ErrMovieNotExists,
+		},
+		{
+			description: "creation of the review by non existing user",
+			user:        0,
+			review:      model.Review{MovieId: movie.Id, Rating: 5},
+			err:         model.ErrUserNotExists,
+		},
+		{
+			description: "successful creation of the review by admin",
+			user:        adminUserId,
+			review:      model.Review{MovieId: movie.Id, Rating: 9},
+			err:         nil,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			review, err := s.service.CreateReview(as(test.user), test.review)
+			assert.ErrorIs(t, err, test.err)
+			if err == nil {
+				assert.Equal(t, test.user, review.UserId)
+				assert.Equal(t, strings.TrimSpace(test.review.Text), review.Text)
+			}
+		})
+	}
+
+	got, err := s.service.GetMovie(as(regularUserId), movie.Id)
+	s.Require().NoError(err)
+	s.Equal(7.5, got.CommunityRating)
+	s.Equal(uint64(2), got.VotesCount)
+	s.Equal(5.0, got.Rating)
+}
+
+type reviewModerationTest struct {
+	description string
+	user        uint64
+	id          uint64
+	err         error
+}
+
+func (s *appTestSuite) TestReviewModeration() {
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie With Moderated Reviews",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	own, err := s.service.CreateReview(as(regularUserId), model.Review{MovieId: movie.Id, Rating: 3})
+	s.Require().NoError(err)
+	other, err := s.service.CreateReview(as(adminUserId), model.Review{MovieId: movie.Id, Rating: 8})
+	s.Require().NoError(err)
+
+	updateTests := []reviewModerationTest{
+		{
+			description: "successful updating of own review",
+			user:        regularUserId,
+			id:          own.Id,
+			err:         nil,
+		},
+		{
+			description: "updating of the review of another user",
+			user:        regularUserId,
+			id:          other.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "updating of the review of another user by admin",
+			user:        adminUserId,
+			id:          own.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "updating of non existing review",
+			user:        regularUserId,
+			id:          0,
+			err:         model.ErrReviewNotExists,
+		},
+	}
+	for _, test := range updateTests {
+		s.T().Run(test.description, func(t *testing.T) {
+			_, err := s.service.UpdateReview(as(test.user), test.id, model.UpdateReview{Rating: 4, Text: "Updated"})
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+
+	deleteTests := []reviewModerationTest{
+		{
+			description: "deleting of the review of another user",
+			user:        regularUserId,
+			id:          other.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "successful deleting of the review of another user by admin",
+			user:        adminUserId,
+			id:          own.Id,
+			err:         nil,
+		},
+		{
+			description: "successful deleting of own review",
+			user:        adminUserId,
+			id:          other.Id,
+			err:         nil,
+		},
+		{
+			description: "deleting of non existing review",
+			user:        adminUserId,
+			id:          other.Id,
+			err:         model.ErrReviewNotExists,
+		},
+	}
+	for _, test := range deleteTests {
+		s.T().Run(test.description, func(t *testing.T) {
+			err := s.service.DeleteReview(as(test.user), test.id)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+type markWatchedTest struct {
+	description string
+	user        uint64
+	movie       uint64
+	watchedAt   time.Time
+	err         error
+}
+
+func (s *appTestSuite) TestWatchlist() {
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie In Watchlist",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	s.Equal(&model.MovieStatus{}, movie.Status)
+
+	added, err := s.service.AddToWatchlist(as(regularUserId), movie.Id)
+	s.Require().NoError(err)
+	s.Equal(&model.MovieStatus{InWatchlist: true}, added.Status)
+
+	tests := []markWatchedTest{
+		{
+			description: "marking of the movie watched in the future",
+			user:        regularUserId,
+			movie:       movie.Id,
+			watchedAt:   time.Now().Add(48 * time.Hour),
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "marking of non existing movie",
+			user:        regularUserId,
+			movie:       0,
+			err:         model.ErrMovieNotExists,
+		},
+		{
+			description: "marking by non existing user",
+			user:        0,
+			movie:       movie.Id,
+			err:         model.ErrUserNotExists,
+		},
+		{
+			description: "successful marking of the movie watched today",
+			user:        regularUserId,
+			movie:       movie.Id,
+			err:         nil,
+		},
+	}
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			watched, err := s.service.MarkWatched(as(test.user), test.movie, test.watchedAt)
+			assert.ErrorIs(t, err, test.err)
+			if err == nil {
+				assert.True(t, watched.Status.Watched)
+				assert.True(t, watched.Status.InWatchlist)
+			}
+		})
+	}
+
+	// statuses are personal and embedded into movies of lists
+	yes := true
+	list, err := s.service.GetMovies(as(regularUserId), model.MovieFilter{Watched: &yes}, nil, model.Page{})
+	s.Require().NoError(err)
+	s.Require().Len(list.Movies, 1)
+	s.Equal(movie.Id, list.Movies[0].Id)
+	s.True(list.Movies[0].Status.Watched)
+
+	list, err = s.service.GetMovies(as(adminUserId), model.MovieFilter{Watched: &yes}, nil, model.Page{})
+	s.Require().NoError(err)
+	s.Empty(list.Movies)
+
+	got, err := s.service.GetMovie(as(adminUserId), movie.Id)
+	s.Require().NoError(err)
+	s.Equal(&model.MovieStatus{}, got.Status)
+
+	s.Require().NoError(s.service.RemoveFromWatchlist(as(regularUserId), movie.Id))
+	s.ErrorIs(s.service.RemoveFromWatchlist(as(regularUserId), movie.Id), model.ErrEntryNotExists)
+	s.Require().NoError(s.service.UnmarkWatched(as(regularUserId), movie.Id))
+	s.ErrorIs(s.service.UnmarkWatched(as(regularUserId), movie.Id), model.ErrEntryNotExists)
+}
+
+type collectionAccessTest struct {
+	description string
+	user        uint64
+	id          uint64
+	err         error
+}
+
+func (s *appTestSuite) TestCollections() {
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Movie In Collection",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	_, err = s.service.CreateCollection(as(regularUserId), model.Collection{Name: "   "})
+	s.ErrorIs(err, model.ErrValidationError)
+	_, err = s.service.CreateCollection(as(regularUserId), model.Collection{Name: "Name", Visibility: "hidden"})
+	s.ErrorIs(err, model.ErrValidationError)
+
+	private, err := s.service.CreateCollection(as(regularUserId), model.Collection{Name: "  Private  "})
+	s.Require().NoError(err)
+	s.Equal("Private", private.Name)
+	s.Equal(model.Private, private.Visibility)
+	s.Equal(uint64(regularUserId), private.OwnerId)
+
+	_, err = s.service.AddCollectionEntry(as(regularUserId), private.Id, model.CollectionEntry{
+		MovieId: movie.Id,
+		Note:    strings.Repeat("a", 501),
+	})
+	s.ErrorIs(err, model.ErrValidationError)
+	withEntry, err := s.service.AddCollectionEntry(as(regularUserId), private.Id, model.CollectionEntry{
+		MovieId: movie.Id,
+		Note:    " Must see ",
+	})
+	s.Require().NoError(err)
+	s.Require().Len(withEntry.Entries, 1)
+	s.Equal("Must see", withEntry.Entries[0].Note)
+	s.Equal(movie.Id, withEntry.Entries[0].Movie.Id)
+	s.NotNil(withEntry.Entries[0].Movie.Status)
+
+	readTests := []collectionAccessTest{
+		{
+			description: "reading of own private collection",
+			user:        regularUserId,
+			id:          private.Id,
+			err:         nil,
+		},
+		{
+			description: "reading of private collection of another user",
+			user:        adminUserId,
+			id:          private.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "reading of non existing collection",
+			user:        regularUserId,
+			id:          0,
+			err:         model.ErrCollectionNotExists,
+		},
+	}
+	for _, test := range readTests {
+		s.T().Run(test.description, func(t *testing.T) {
+			_, err := s.service.GetCollection(as(test.user), test.id)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+
+	// sharing gives read-only access
+	shared, err := s.service.UpdateCollection(as(regularUserId), private.Id, model.UpdateCollection{
+		Name:       private.Name,
+		SharedWith: []uint64{adminUserId},
+	})
+	s.Require().NoError(err)
+	s.Equal([]uint64{adminUserId}, shared.SharedWith)
+
+	got, err := s.service.GetCollection(as(adminUserId), private.Id)
+	s.Require().NoError(err)
+	s.Len(got.Entries, 1)
+	s.Empty(got.SharedWith)
+
+	list, err := s.service.GetCollections(as(adminUserId), model.CollectionFilter{OwnerId: regularUserId}, model.Page{})
+	s.Require().NoError(err)
+	s.Require().Len(list.Collections, 1)
+	s.Equal(private.Id, list.Collections[0].Id)
+
+	changeTests := []collectionAccessTest{
+		{
+			description: "changing of the shared collection",
+			user:        adminUserId,
+			id:          private.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "changing of non existing collection",
+			user:        regularUserId,
+			id:          0,
+			err:         model.ErrCollectionNotExists,
+		},
+		{
+			description: "successful changing of own collection",
+			user:        regularUserId,
+			id:          private.Id,
+			err:         nil,
+		},
+	}
+	for _, test := range changeTests {
+		s.T().Run(test.description, func(t *testing.T) {
+			_, err := s.service.UpdateCollection(as(test.user), test.id, model.UpdateCollection{Name: "Updated"})
+			assert.ErrorIs(t, err, test.err)
+			_, err = s.service.UpdateCollectionEntry(as(test.user), test.id, model.CollectionEntry{MovieId: movie.Id})
+			assert.ErrorIs(t, err, test.err)
+			_, err = s.service.ReorderCollection(as(test.user), test.id, []uint64{movie.Id})
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+
+	// the shared list was replaced by the last update
+	_, err = s.service.GetCollection(as(adminUserId), private.Id)
+	s.ErrorIs(err, model.ErrPermissionDenied)
+
+	s.ErrorIs(s.service.RemoveCollectionEntry(as(adminUserId), private.Id, movie.Id), model.ErrPermissionDenied)
+	s.Require().NoError(s.service.RemoveCollectionEntry(as(regularUserId), private.Id, movie.Id))
+	s.ErrorIs(s.service.RemoveCollectionEntry(as(regularUserId), private.Id, movie.Id), model.ErrEntryNotExists)
+
+	public, err := s.service.CreateCollection(as(adminUserId), model.Collection{Name: "Public", Visibility: model.Public})
+	s.Require().NoError(err)
+	_, err = s.service.GetCollection(as(regularUserId), public.Id)
+	s.NoError(err)
+
+	deleteTests := []collectionAccessTest{
+		{
+			description: "deleting of the collection of another user",
+			user:        regularUserId,
+			id:          public.Id,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "successful deleting of own collection",
+			user:        adminUserId,
+			id:          public.Id,
+			err:         nil,
+		},
+		{
+			description: "successful deleting of the collection of another user by admin",
+			user:        adminUserId,
+			id:          private.Id,
+			err:         nil,
+		},
+		{
+			description: "deleting of non existing collection",
+			user:        adminUserId,
+			id:          private.Id,
+			err:         model.ErrCollectionNotExists,
+		},
+	}
+	for _, test := range deleteTests {
+		s.T().Run(test.description, func(t *testing.T) {
+			err := s.service.DeleteCollection(as(test.user), test.id)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+type createUserTest struct {
+	description string
+	user        uint64
+	input       model.User
+	password    string
+	err         error
+}
+
+func (s *appTestSuite) TestCreateUser() {
+	tests := []createUserTest{
+		{
+			description: "successful creating of the user",
+			user:        adminUserId,
+			input:       model.User{Username: " new.user ", Email: " New.User@Example.com "},
+			password:    testPassword,
+			err:         nil,
+		},
+		{
+			description: "creating of the user with taken username",
+			user:        adminUserId,
+			input:       model.User{Username: "NEW.USER"},
+			password:    testPassword,
+			err:         model.ErrConflict,
+		},
+		{
+			description: "creating of the user with short username",
+			user:        adminUserId,
+			input:       model.User{Username: "ab"},
+			password:    testPassword,
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creating of the user with spaces in username",
+			user:        adminUserId,
+			input:       model.User{Username: "new user"},
+			password:    testPassword,
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creating of the user with invalid email",
+			user:        adminUserId,
+			input:       model.User{Username: "emailuser", Email: "Name <name@example.com>"},
+			password:    testPassword,
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creating of the user with unknown role",
+			user:        adminUserId,
+			input:       model.User{Username: "roleuser", Role: "owner"},
+			password:    testPassword,
+			err:         model.ErrRoleNotExists,
+		},
+		{
+			description: "creating of the user with short password",
+			user:        adminUserId,
+			input:       model.User{Username: "passworduser"},
+			password:    "short",
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "creating of the user with no admin rights",
+			user:        regularUserId,
+			input:       model.User{Username: "regularuser"},
+			password:    testPassword,
+			err:         model.ErrPermissionDenied,
+		},
+	}
+
+	var created model.User
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			user, err := s.service.CreateUser(as(test.user), test.input, test.password)
+			assert.ErrorIs(t, err, test.err)
+			if err == nil {
+				assert.Equal(t, "new.user", user.Username)
+				assert.Equal(t, "new.user@example.com", user.Email)
+				assert.Equal(t, model.Regular, user.Role)
+				created = user
+			}
+		})
+	}
+	s.Require().NoError(s.service.DeleteUser(as(adminUserId), created.Id))
+}
+
+func (s *appTestSuite) TestUserManagement() {
+	admin, err := s.service.CreateUser(as(adminUserId), model.User{Username: "second.admin", Role: model.Admin}, testPassword)
+	s.Require().NoError(err)
+	user, err := s.service.CreateUser(as(admin.Id), model.User{Username: "managed.user"}, testPassword)
+	s.Require().NoError(err)
+
+	// users can get only their own accounts
+	_, err = s.service.GetUser(as(user.Id), user.Id)
+	s.NoError(err)
+	_, err = s.service.GetUser(as(user.Id), admin.Id)
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.GetUsers(as(user.Id), model.Page{})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+
+	_, err = s.service.UpdateUser(as(admin.Id), user.Id, model.UpdateUser{Username: user.Username})
+	s.ErrorIs(err, model.ErrRoleNotExists)
+	disabled, err := s.service.UpdateUser(as(admin.Id), user.Id, model.UpdateUser{
+		Username: user.Username,
+		Role:     model.Regular,
+		Disabled: true,
+	})
+	s.Require().NoError(err)
+	s.True(disabled.Disabled)
+
+	// disabled users can not perform any operation
+	_, err = s.service.GetUser(as(user.Id), user.Id)
+	s.ErrorIs(err, model.ErrUserNotExists)
+	_, err = s.service.GetGenres(as(user.Id))
+	s.ErrorIs(err, model.ErrUserNotExists)
+
+	// the default admin is demoted, so the second admin is the last one
+	_, err = s.service.UpdateUser(as(admin.Id), adminUserId, model.UpdateUser{Username: "user1", Role: model.Regular})
+	s.Require().NoError(err)
+	_, err = s.service.UpdateUser(as(admin.Id), admin.Id, model.UpdateUser{Username: admin.Username, Role: model.Regular})
+	s.ErrorIs(err, model.ErrLastAdmin)
+	_, err = s.service.UpdateUser(as(admin.Id), admin.Id, model.UpdateUser{
+		Username: admin.Username,
+		Role:     model.Admin,
+		Disabled: true,
+	})
+	s.ErrorIs(err, model.ErrLastAdmin)
+	s.ErrorIs(s.service.DeleteUser(as(admin.Id), admin.Id), model.ErrLastAdmin)
+
+	_, err = s.service.UpdateUser(as(admin.Id), adminUserId, model.UpdateUser{Username: "user1", Role: model.Admin})
+	s.Require().NoError(err)
+	list, err := s.service.GetUsers(as(adminUserId), model.Page{})
+	s.Require().NoError(err)
+	s.Equal(uint64(4), list.Total)
+
+	s.ErrorIs(s.service.DeleteUser(as(user.Id), admin.Id), model.ErrUserNotExists)
+	s.Require().NoError(s.service.DeleteUser(as(adminUserId), user.Id))
+	s.Require().NoError(s.service.DeleteUser(as(adminUserId), admin.Id))
+	s.ErrorIs(s.service.DeleteUser(as(adminUserId), admin.Id), model.ErrUserNotExists)
+}
+
+func (s *appTestSuite) TestRoles() {
+	roles, err := s.service.GetRoles(as(regularUserId))
+	s.Require().NoError(err)
+	s.Len(roles, 4)
+	editorRole, err := s.service.GetRole(as(regularUserId), "editor")
+	s.Require().NoError(err)
+	s.Equal([]model.Permission{model.ActorsWrite, model.GenresWrite, model.MoviesWrite}, editorRole.Permissions)
+	_, err = s.service.GetRole(as(regularUserId), "owner")
+	s.ErrorIs(err, model.ErrRoleNotExists)
+
+	// editors can change movies but can not delete them
+	editor, err := s.service.CreateUser(as(adminUserId), model.User{Username: "editor", Role: "editor"}, testPassword)
+	s.Require().NoError(err)
+	movie, err := s.service.CreateMovie(as(editor.Id), model.Movie{
+		Title:       "Movie Of The Editor",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	s.ErrorIs(s.service.DeleteMovie(as(editor.Id), movie.Id), model.ErrPermissionDenied)
+	_, err = s.service.CreateRole(as(editor.Id), model.RolePermissions{Role: "curator"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+
+	// permissions of the role are applied to its users at once
+	updated, err := s.service.UpdateRole(as(adminUserId), model.RolePermissions{
+		Role:        "editor",
+		Permissions: []model.Permission{model.MoviesWrite, model.MoviesDelete},
+	})
+	s.Require().NoError(err)
+	s.Equal([]model.Permission{model.MoviesDelete, model.MoviesWrite}, updated.Permissions)
+	s.NoError(s.service.DeleteMovie(as(editor.Id), movie.Id))
+	_, err = s.service.CreateActor(as(editor.Id), model.Actor{FirstName: "Actor Of The Editor"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.UpdateRole(as(adminUserId), editorRole)
+	s.Require().NoError(err)
+
+	// managers of users can not manage admins
+	manager, err := s.service.CreateRole(as(adminUserId), model.RolePermissions{
+		Role:        " manager ",
+		Permissions: []model.Permission{model.UsersManage, model.UsersManage},
+	})
+	s.Require().NoError(err)
+	s.Equal(model.RolePermissions{Role: "manager", Permissions: []model.Permission{model.UsersManage}}, manager)
+	managerUser, err := s.service.CreateUser(as(adminUserId), model.User{Username: "manager", Role: "manager"}, testPassword)
+	s.Require().NoError(err)
+	_, err = s.service.CreateUser(as(managerUser.Id), model.User{Username: "new.admin", Role: model.Admin}, testPassword)
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.UpdateUser(as(managerUser.Id), editor.Id, model.UpdateUser{Username: editor.Username, Role: model.Admin})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.UpdateUser(as(managerUser.Id), adminUserId, model.UpdateUser{Username: "user1", Role: model.Admin, Disabled: true})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	s.ErrorIs(s.service.SetPassword(as(managerUser.Id), adminUserId, "new password", ""), model.ErrPermissionDenied)
+	s.ErrorIs(s.service.DeleteUser(as(managerUser.Id), adminUserId), model.ErrPermissionDenied)
+
+	// managers of users can not assign roles or manage accounts with permissions
+	// they do not hold
+	_, err = s.service.CreateUser(as(managerUser.Id), model.User{Username: "new.editor", Role: "editor"}, testPassword)
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.UpdateUser(as(managerUser.Id), managerUser.Id, model.UpdateUser{Username: managerUser.Username, Role: "moderator"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.UpdateUser(as(managerUser.Id), editor.Id, model.UpdateUser{Username: editor.Username, Role: model.Regular})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	s.ErrorIs(s.service.SetPassword(as(managerUser.Id), editor.Id, "new password", ""), model.ErrPermissionDenied)
+	s.ErrorIs(s.service.DeleteUser(as(managerUser.Id), editor.Id), model.ErrPermissionDenied)
+	managerRole, err := s.service.GetUser(as(adminUserId), managerUser.Id)
+	s.Require().NoError(err)
+	s.Equal(model.Role("manager"), managerRole.Role)
+	regular, err := s.service.CreateUser(as(managerUser.Id), model.User{Username: "managed.regular"}, testPassword)
+	s.Require().NoError(err)
+	s.NoError(s.service.SetPassword(as(managerUser.Id), regular.Id, "new password", ""))
+	// managers confirm their own password like other users
+	s.ErrorIs(s.service.SetPassword(as(managerUser.Id), managerUser.Id, "new password", ""), model.ErrInvalidCredentials)
+	s.ErrorIs(s.service.SetPassword(as(adminUserId), adminUserId, "new password", ""), model.ErrInvalidCredentials)
+	s.NoError(s.service.SetPassword(as(managerUser.Id), managerUser.Id, "new password", testPassword))
+	_, err = s.service.UpdateUser(as(managerUser.Id), regular.Id, model.UpdateUser{Username: regular.Username, Role: "manager"})
+	s.NoError(err)
+	s.NoError(s.service.DeleteUser(as(managerUser.Id), regular.Id))
+	_, err = s.service.UpdateUser(as(adminUserId), editor.Id, model.UpdateUser{Username: editor.Username, Role: "moderator"})
+	s.NoError(err)
+
+	// managers of roles can grant only permissions they hold
+	_, err = s.service.CreateRole(as(adminUserId), model.RolePermissions{
+		Role:        "curator",
+		Permissions: []model.Permission{model.RolesManage, model.MoviesWrite},
+	})
+	s.Require().NoError(err)
+	curator, err := s.service.CreateUser(as(adminUserId), model.User{Username: "curator", Role: "curator"}, testPassword)
+	s.Require().NoError(err)
+	_, err = s.service.CreateRole(as(curator.Id), model.RolePermissions{
+		Role:        "escalated",
+		Permissions: []model.Permission{model.MoviesWrite, model.UsersManage},
+	})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.GetRole(as(adminUserId), "escalated")
+	s.ErrorIs(err, model.ErrRoleNotExists)
+	_, err = s.service.UpdateRole(as(curator.Id), model.RolePermissions{
+		Role:        "curator",
+		Permissions: []model.Permission{model.RolesManage, model.MoviesWrite, model.MoviesDelete},
+	})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.UpdateRole(as(curator.Id), model.RolePermissions{
+		Role:        "manager",
+		Permissions: []model.Permission{model.UsersManage},
+	})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	curatorRole, err := s.service.GetRole(as(adminUserId), "curator")
+	s.Require().NoError(err)
+	s.Equal([]model.Permission{model.MoviesWrite, model.RolesManage}, curatorRole.Permissions)
+	created, err := s.service.CreateRole(as(curator.Id), model.RolePermissions{
+		Role:        "writer",
+		Permissions: []model.Permission{model.MoviesWrite},
+	})
+	s.Require().NoError(err)
+	s.Equal([]model.Permission{model.MoviesWrite}, created.Permissions)
+	s.Require().NoError(s.service.DeleteRole(as(curator.Id), "writer"))
+	s.Require().NoError(s.service.DeleteUser(as(adminUserId), curator.Id))
+	s.Require().NoError(s.service.DeleteRole(as(adminUserId), "curator"))
+
+	// built-in roles and roles of users can not be deleted
+	s.ErrorIs(s.service.DeleteRole(as(adminUserId), model.Admin), model.ErrBuiltInRole)
+	s.ErrorIs(s.service.DeleteRole(as(adminUserId), model.Regular), model.ErrBuiltInRole)
+	_, err = s.service.UpdateRole(as(adminUserId), model.RolePermissions{Role: model.Admin})
+	s.ErrorIs(err, model.ErrBuiltInRole)
+	s.ErrorIs(s.service.DeleteRole(as(adminUserId), "manager"), model.ErrRoleInUse)
+
+	for name, role := range map[string]model.RolePermissions{
+		"empty name":         {Role: ""},
+		"long name":          {Role: model.Role(strings.Repeat("a", 21))},
+		"upper case name":    {Role: "Curator"},
+		"unknown permission": {Role: "curator", Permissions: []model.Permission{"movies:read"}},
+	} {
+		_, err = s.service.CreateRole(as(adminUserId), role)
+		s.ErrorIs(err, model.ErrValidationError, name)
+	}
+	_, err = s.service.CreateRole(as(adminUserId), model.RolePermissions{Role: "moderator"})
+	s.ErrorIs(err, model.ErrConflict)
+	_, err = s.service.UpdateRole(as(adminUserId), model.RolePermissions{Role: "curator"})
+	s.ErrorIs(err, model.ErrRoleNotExists)
+
+	s.Require().NoError(s.service.DeleteUser(as(adminUserId), managerUser.Id))
+	s.Require().NoError(s.service.DeleteUser(as(adminUserId), editor.Id))
+	s.Require().NoError(s.service.DeleteRole(as(adminUserId), "manager"))
+	s.ErrorIs(s.service.DeleteRole(as(adminUserId), "manager"), model.ErrRoleNotExists)
+}
+
+func (s *appTestSuite) TestAuth() {
+	user, err := s.service.CreateUser(as(adminUserId), model.User{Username: "auth.user"}, testPassword)
+	s.Require().NoError(err)
+	defer func() {
+		s.Require().NoError(s.service.DeleteUser(as(adminUserId), user.Id))
+	}()
+
+	// the same error is returned for wrong passwords and unknown users
+	_, err = s.service.Login(ctx, "auth.user", "wrong password")
+	s.ErrorIs(err, model.ErrInvalidCredentials)
+	_, err = s.service.Login(ctx, "unknown.user", testPassword)
+	s.ErrorIs(err, model.ErrInvalidCredentials)
+
+	tokens, err := s.service.Login(ctx, " AUTH.USER ", testPassword)
+	s.Require().NoError(err)
+	s.NotEmpty(tokens.AccessToken)
+	s.NotEmpty(tokens.RefreshToken)
+	s.True(tokens.AccessTokenExpiresAt.Before(tokens.RefreshTokenExpiresAt))
+	principal, err := s.service.Authenticate(ctx, tokens.AccessToken)
+	s.Require().NoError(err)
+	s.Equal(user.Id, principal.UserId)
+
+	_, err = s.service.Authenticate(ctx, "not a token")
+	s.ErrorIs(err, model.ErrInvalidToken)
+	_, err = s.service.Authenticate(ctx, tokens.AccessToken+"x")
+	s.ErrorIs(err, model.ErrInvalidToken)
+
+	// refresh tokens are rotated and can be used only once
+	refreshed, err := s.service.Refresh(ctx, tokens.RefreshToken)
+	s.Require().NoError(err)
+	s.NotEqual(tokens.RefreshToken, refreshed.RefreshToken)
+	_, err = s.service.Refresh(ctx, tokens.RefreshToken)
+	s.ErrorIs(err, model.ErrInvalidToken)
+	refreshedPrincipal, err := s.service.Authenticate(ctx, refreshed.AccessToken)
+	s.Require().NoError(err)
+	s.Equal(principal.SessionId, refreshedPrincipal.SessionId)
+
+	// access and refresh tokens of the revoked session are rejected
+	other, err := s.service.Login(ctx, "auth.user", testPassword)
+	s.Require().NoError(err)
+	s.ErrorIs(s.service.Logout(ctx, false), model.ErrUnauthorized)
+	s.ErrorIs(s.service.Logout(as(user.Id), false), model.ErrPermissionDenied)
+	s.Require().NoError(s.service.Logout(WithPrincipal(ctx, principal), false))
+	_, err = s.service.Authenticate(ctx, refreshed.AccessToken)
+	s.ErrorIs(err, model.ErrInvalidToken)
+	_, err = s.service.Refresh(ctx, refreshed.RefreshToken)
+	s.ErrorIs(err, model.ErrInvalidToken)
+	otherPrincipal, err := s.service.Authenticate(ctx, other.AccessToken)
+	s.Require().NoError(err)
+	s.Require().NoError(s.service.Logout(WithPrincipal(ctx, otherPrincipal), true))
+	_, err = s.service.Authenticate(ctx, other.AccessToken)
+	s.ErrorIs(err, model.ErrInvalidToken)
+
+	// users confirm changes of their own passwords, all sessions are revoked
+	tokens, err = s.service.Login(ctx, "auth.user", testPassword)
+	s.Require().NoError(err)
+	s.ErrorIs(s.service.SetPassword(as(user.Id), user.Id, "new password", "wrong password"),
+		model.ErrInvalidCredentials)
+	s.ErrorIs(s.service.SetPassword(as(user.Id), user.Id, "short", testPassword), model.ErrValidationError)
+	s.ErrorIs(s.service.SetPassword(as(user.Id), adminUserId, "new password", testPassword),
+		model.ErrPermissionDenied)
+	s.Require().NoError(s.service.SetPassword(as(user.Id), user.Id, "new password", testPassword))
+	_, err = s.service.Authenticate(ctx, tokens.AccessToken)
+	s.ErrorIs(err, model.ErrInvalidToken)
+	_, err = s.service.Login(ctx, "auth.user", testPassword)
+	s.ErrorIs(err, model.ErrInvalidCredentials)
+	s.Require().NoError(s.service.SetPassword(as(adminUserId), user.Id, testPassword, ""))
+
+	// disabled users can not log in and their sessions are revoked
+	tokens, err = s.service.Login(ctx, "auth.user", testPassword)
+	s.Require().NoError(err)
+	_, err = s.service.UpdateUser(as(adminUserId), user.Id, model.UpdateUser{
+		Username: user.Username,
+		Role:     model.Regular,
+		Disabled: true,
+	})
+	s.Require().NoError(err)
+	_, err = s.service.Authenticate(ctx, tokens.AccessToken)
+	s.ErrorIs(err, model.ErrInvalidToken)
+	_, err = s.service.Login(ctx, "auth.user", testPassword)
+	s.ErrorIs(err, model.ErrInvalidCredentials)
+}
+
+func (s *appTestSuite) TestPrincipal() {
+	// requests without the principal or made by deleted users are rejected
+	_, err := s.service.GetGenres(ctx)
+	s.ErrorIs(err, model.ErrUnauthorized)
+	_, err = s.service.GetGenres(as(0))
+	s.ErrorIs(err, model.ErrUnauthorized)
+
+	user, err := s.service.CreateUser(as(adminUserId), model.User{Username: "principal.user"}, testPassword)
+	s.Require().NoError(err)
+	defer func() {
+		s.Require().NoError(s.service.DeleteUser(as(adminUserId), user.Id))
+	}()
+	key, err := s.service.CreateApiKey(as(user.Id), model.ApiKey{Name: "principal"})
+	s.Require().NoError(err)
+	_, err = s.service.CreateGenre(as(user.Id), model.Genre{Name: "Principal Genre"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+
+	// the cached role is dropped when the role of the user is changed
+	_, err = s.service.UpdateUser(as(adminUserId), user.Id, model.UpdateUser{Username: user.Username, Role: "editor"})
+	s.Require().NoError(err)
+	genre, err := s.service.CreateGenre(as(user.Id), model.Genre{Name: "Principal Genre"})
+	s.Require().NoError(err)
+	s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+
+	// disabled users are rejected at once, their API keys too
+	_, err = s.service.UpdateUser(as(adminUserId), user.Id, model.UpdateUser{
+		Username: user.Username,
+		Role:     "editor",
+		Disabled: true,
+	})
+	s.Require().NoError(err)
+	_, err = s.service.GetGenres(as(user.Id))
+	s.ErrorIs(err, model.ErrUnauthorized)
+	_, err = s.service.AuthenticateApiKey(ctx, key.Key)
+	s.ErrorIs(err, model.ErrInvalidToken)
+}
+
+func TestRoleCache(t *testing.T) {
+	cache := newRoleCache(time.Minute)
+	editor := model.RolePermissions{Role: "editor", Permissions: []model.Permission{model.MoviesWrite}}
+
+	_, generation, ok := cache.get(1)
+	assert.False(t, ok)
+	cache.put(1, editor, generation)
+	role, _, ok := cache.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, editor, role)
+
+	// the role read before the invalidation is not cached after it
+	_, generation, _ = cache.get(2)
+	cache.invalidate()
+	cache.put(2, editor, generation)
+	_, _, ok = cache.get(2)
+	assert.False(t, ok)
+	_, _, ok = cache.get(1)
+	assert.False(t, ok)
+
+	// expired roles are read again
+	cache = newRoleCache(0)
+	_, generation, _ = cache.get(1)
+	cache.put(1, editor, generation)
+	_, _, ok = cache.get(1)
+	assert.False(t, ok)
+}
+
+func (s *appTestSuite) TestInitAdminPassword() {
+	_, err := s.service.Login(ctx, "user1", "admin password")
+	s.ErrorIs(err, model.ErrInvalidCredentials)
+
+	errNotSet := errors.New("the password is not set")
+	s.ErrorIs(s.service.InitAdminPassword(ctx, func() (string, error) {
+		return "", errNotSet
+	}), errNotSet)
+
+	// the password is set only once
+	s.Require().NoError(s.service.InitAdminPassword(ctx, func() (string, error) {
+		return "admin password", nil
+	}))
+	// the password is not read when the admin has one
+	s.Require().NoError(s.service.InitAdminPassword(ctx, func() (string, error) {
+		return "", errNotSet
+	}))
+	_, err = s.service.Login(ctx, "user1", "admin password")
+	s.NoError(err)
+}
+
+func (s *appTestSuite) TestApiKeys() {
+	other, err := s.service.CreateUser(as(adminUserId), model.User{Username: "other.key.user"}, testPassword)
+	s.Require().NoError(err)
+	defer func() {
+		s.Require().NoError(s.service.DeleteUser(as(adminUserId), other.Id))
+	}()
+
+	// keys are read by default and returned only on creation
+	readKey, err := s.service.CreateApiKey(as(regularUserId), model.ApiKey{Name: " ingestion "})
+	s.Require().NoError(err)
+	s.Equal("ingestion", readKey.Name)
+	s.Equal(model.ReadScope, readKey.Scope)
+	s.Nil(readKey.ExpiresAt)
+	s.True(strings.HasPrefix(readKey.Key, readKey.Prefix))
+	principal, err := s.service.AuthenticateApiKey(ctx, readKey.Key)
+	s.Require().NoError(err)
+	s.Equal(model.Principal{UserId: regularUserId, ApiKeyId: readKey.Id, Scope: model.ReadScope}, principal)
+
+	expiresAt := time.Now().Add(time.Hour)
+	writeKey, err := s.service.CreateApiKey(as(regularUserId), model.ApiKey{
+		Name:      "import",
+		Scope:     model.WriteScope,
+		ExpiresAt: &expiresAt,
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(writeKey.ExpiresAt)
+	s.WithinDuration(expiresAt, *writeKey.ExpiresAt, time.Millisecond)
+
+	past := time.Now().Add(-time.Hour)
+	for name, key := range map[string]model.ApiKey{
+		"empty name":    {Name: " "},
+		"long name":     {Name: strings.Repeat("a", 101)},
+		"unknown scope": {Name: "admin", Scope: "admin"},
+		"expired key":   {Name: "expired", ExpiresAt: &past},
+	} {
+		_, err = s.service.CreateApiKey(as(regularUserId), key)
+		s.ErrorIs(err, model.ErrValidationError, name)
+	}
+
+	keys, err := s.service.GetApiKeys(as(regularUserId), regularUserId)
+	s.Require().NoError(err)
+	s.Require().Len(keys, 2)
+	s.Equal(readKey.Id, keys[0].Id)
+	s.Equal(readKey.Prefix, keys[0].Prefix)
+	s.Empty(keys[0].Key)
+	_, err = s.service.GetApiKeys(as(other.Id), regularUserId)
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	keys, err = s.service.GetApiKeys(as(adminUserId), regularUserId)
+	s.Require().NoError(err)
+	s.Len(keys, 2)
+
+	// revoked keys are rejected
+	s.ErrorIs(s.service.RevokeApiKey(as(other.Id), readKey.Id), model.ErrPermissionDenied)
+	s.Require().NoError(s.service.RevokeApiKey(as(regularUserId), readKey.Id))
+	_, err = s.service.AuthenticateApiKey(ctx, readKey.Key)
+	s.ErrorIs(err, model.ErrInvalidToken)
+	s.ErrorIs(s.service.RevokeApiKey(as(regularUserId), readKey.Id), model.ErrApiKeyNotExists)
+	s.Require().NoError(s.service.RevokeApiKey(as(adminUserId), writeKey.Id))
+
+	_, err = s.service.AuthenticateApiKey(ctx, "mlk_unknown")
+	s.ErrorIs(err, model.ErrInvalidToken)
+}
+
+func (s *appTestSuite) TestApiKeyScope() {
+	readOnly := WithPrincipal(ctx, model.Principal{UserId: adminUserId, ApiKeyId: 1, Scope: model.ReadScope})
+
+	// read-scoped keys read data but do not change it even if the role allows it
+	_, err := s.service.GetGenres(readOnly)
+	s.NoError(err)
+	_, err = s.service.GetPoolStats(readOnly)
+	s.NoError(err)
+	users, err := s.service.GetUsers(readOnly, model.Page{})
+	s.Require().NoError(err)
+	s.NotEmpty(users.Users)
+	user, err := s.service.GetUser(readOnly, regularUserId)
+	s.Require().NoError(err)
+	_, err = s.service.GetRoles(readOnly)
+	s.NoError(err)
+	_, err = s.service.UpdateUser(readOnly, regularUserId, model.UpdateUser{Username: user.Username, Role: model.Regular, Disabled: true})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	s.ErrorIs(s.service.DeleteUser(readOnly, regularUserId), model.ErrPermissionDenied)
+	_, err = s.service.UpdateRole(readOnly, model.RolePermissions{Role: "editor"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	user, err = s.service.GetUser(readOnly, regularUserId)
+	s.Require().NoError(err)
+	s.False(user.Disabled)
+	_, err = s.service.CreateGenre(readOnly, model.Genre{Name: "Read Scope"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.CreateUser(readOnly, model.User{Username: "read.scope.user"}, testPassword)
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.CreateCollection(readOnly, model.Collection{Name: "Read Scope"})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	_, err = s.service.CreateApiKey(readOnly, model.ApiKey{Name: "escalation", Scope: model.WriteScope})
+	s.ErrorIs(err, model.ErrPermissionDenied)
+	s.ErrorIs(s.service.SetPassword(readOnly, adminUserId, "new password", testPassword), model.ErrPermissionDenied)
+
+	// write-scoped keys change data
+	writer := WithPrincipal(ctx, model.Principal{UserId: adminUserId, ApiKeyId: 2, Scope: model.WriteScope})
+	genre, err := s.service.CreateGenre(writer, model.Genre{Name: "Write Scope"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.service.DeleteGenre(writer, genre.Id))
+}
+
+func (s *appTestSuite) TestFuzzySearch() {
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Fuzzy Movie Title",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	tests := []fuzzySearchTest{
+		{
+			description: "fuzzy search by exact title",
+			user:        regularUserId,
+			query:       "Fuzzy Movie Title",
+			moviesIds:   []uint64{movie.Id},
+			didYouMean:  "",
+			err:         nil,
+		},
+		{
+			description: "fuzzy search by misspelled title",
+			user:        regularUserId,
+			query:       "Fuzy Movei Titel",
+			moviesIds:   []uint64{movie.Id},
+			didYouMean:  "Fuzzy Movie Title",
+			err:         nil,
+		},
+		{
+			description: "fuzzy search with empty query",
+			user:        regularUserId,
+			query:       " ",
+			moviesIds:   []uint64{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "fuzzy search with too big limit",
+			user:        regularUserId,
+			query:       "Fuzzy Movie Title",
+			limit:       model.MaxFuzzySearchLimit + 1,
+			moviesIds:   []uint64{},
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "fuzzy search with non existing user",
+			user:        0,
+			query:       "Fuzzy Movie Title",
+			moviesIds:   []uint64{},
+			err:         model.ErrUserNotExists,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			res, err := s.service.FuzzySearch(as(test.user), test.query, test.limit)
+			assert.ErrorIs(t, err, test.err)
+
+			gotMoviesIds := make([]uint64, 0)
+			for _, m := range res.Movies {
+				if m.Id == movie.Id {
+					gotMoviesIds = append(gotMoviesIds, m.Id)
+				}
+			}
+			assert.Equal(t, test.moviesIds, gotMoviesIds)
+			assert.Equal(t, test.didYouMean, res.DidYouMean)
+		})
+	}
+}
+
+type suggestTest struct {
+	description string
+	user        uint64
+	prefix      string
+	limit       int
+
+	suggestions []model.Suggestion
+
+	err error
+}
+
+func (s *appTestSuite) TestSuggest() {
+	movie, err := s.service.CreateMovie(as(adminUserId), model.Movie{
+		Title:       "Suggested Movie",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	tests := []suggestTest{
+		{
+			description: "suggestion of the movie by prefix",
+			user:        regularUserId,
+			prefix:      "suggested m",
+			suggestions: []model.Suggestion{
+				{Id: movie.Id, Type: model.MovieSuggestion, Text: "Suggested Movie"},
+			},
+			err: nil,
+		},
+		{
+			description: "suggestion by prefix with surrounding spaces",
+			user:        regularUserId,
+			prefix:      " suggested m ",
+			suggestions: []model.Suggestion{
+				{Id: movie.Id, Type: model.MovieSuggestion, Text: "Suggested Movie"},
+			},
+			err: nil,
+		},
+		{
+			description: "suggestion with empty prefix",
+			user:        regularUserId,
+			prefix:      "",
+			suggestions: []model.Suggestion{},
+			err:         nil,
+		},
+		{
+			description: "suggestion with too short prefix",
+			user:        regularUserId,
+			prefix:      " s ",
+			suggestions: []model.Suggestion{},
+			err:         nil,
+		},
+		{
+			description: "suggestion with too big limit",
+			user:        regularUserId,
+			prefix:      "suggested",
+			limit:       model.MaxSuggestLimit + 1,
+			suggestions: nil,
+			err:         model.ErrValidationError,
+		},
+		{
+			description: "suggestion with non existing user",
+			user:        0,
+			prefix:      "suggested",
+			suggestions: nil,
+			err:         model.ErrUserNotExists,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			suggestions, err := s.service.Suggest(as(test.user), test.prefix, test.limit)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.suggestions, suggestions)
+		})
+	}
+}
+
+type getPoolStatsTest struct {
+	description string
+	user        uint64
+	err         error
+}
+
+func (s *appTestSuite) TestGetPoolStats() {
+	tests := []getPoolStatsTest{
+		{
+			description: "successful getting of pool stats",
+			user:        adminUserId,
+			err:         nil,
+		},
+		{
+			description: "getting of pool stats with no admin rights",
+			user:        regularUserId,
+			err:         model.ErrPermissionDenied,
+		},
+		{
+			description: "getting of pool stats with non existing user",
+			user:        0,
+			err:         model.ErrUserNotExists,
+		},
+	}
+
+	for _, test := range tests {
+		s.T().Run(test.description, func(t *testing.T) {
+			_, err := s.service.GetPoolStats(as(test.user))
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
 func TestAppTestSuite(t *testing.T) {
 	suite.Run(t, new(appTestSuite))
 }
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"context"
+	"movie-lib/internal/model"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns the copy of ctx which carries the principal of the
+// request, methods of App act on behalf of this principal
+func WithPrincipal(ctx context.Context, principal model.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal
+func PrincipalFromContext(ctx context.Context) (model.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(model.Principal)
+	return principal, ok
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"movie-lib/internal/model"
+	"sync"
+	"time"
+)
+
+// roleCacheTTL is how long the role of the user is cached. Changes made
+// through this instance drop the cache at once, changes made by other
+// instances are seen after the TTL.
+const roleCacheTTL = 10 * time.Second
+
+type cachedRole struct {
+	role      model.RolePermissions
+	expiresAt time.Time
+}
+
+// roleCache caches roles of active users with their permissions, so every
+// request does not query them from the repository. It is safe for concurrent
+// use.
+type roleCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	roles map[uint64]cachedRole
+	// generation is incremented on every invalidation, so the role read from
+	// the repository before the invalidation is not cached after it
+	generation uint64
+}
+
+func newRoleCache(ttl time.Duration) *roleCache {
+	return &roleCache{
+		ttl:   ttl,
+		roles: make(map[uint64]cachedRole),
+	}
+}
+
+// get returns the cached role of the user and the generation of the cache to
+// pass to put when the role is not cached
+func (c *roleCache) get(userId uint64) (model.RolePermissions, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.roles[userId]
+	if !ok || !time.Now().Before(cached.expiresAt) {
+		return model.RolePermissions{}, c.generation, false
+	}
+	return cached.role, c.generation, true
+}
+
+// put caches the role of the user if the cache was not invalidated since the
+// generation was got
+func (c *roleCache) put(userId uint64, role model.RolePermissions, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	c.roles[userId] = cachedRole{
+		role:      role,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+// invalidate drops all cached roles, it is called when users or roles are
+// changed
+func (c *roleCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	clear(c.roles)
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"movie-lib/internal/model"
+)
+
+// password length limits in bytes, bcrypt ignores bytes after the 72nd one
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// refreshTokenSize is a number of random bytes of the refresh token
+const refreshTokenSize = 32
+
+// API keys look like "mlk_<id>_<secret>", the "mlk_<id>" part is the prefix
+// which is stored in plain text to tell keys apart in lists
+const (
+	apiKeyPrefix     = "mlk_"
+	apiKeyIdSize     = 6
+	apiKeySecretSize = 32
+)
+
+// dummyPasswordHash is compared with passwords of unknown users, so the time
+// of the login does not tell whether the user exists
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// HashPassword returns the bcrypt hash of the password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Join(model.ErrServiceError, err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether the password matches the hash, an empty hash
+// matches no password but takes the same time to check
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NewRefreshToken returns a random refresh token and its hash which is
+// stored instead of the token
+func NewRefreshToken() (string, string, error) {
+	token, err := randomString(refreshTokenSize)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+// NewApiKey returns a random API key, its prefix and its hash which is stored
+// instead of the key
+func NewApiKey() (string, string, string, error) {
+	id, err := randomString(apiKeyIdSize)
+	if err != nil {
+		return "", "", "", err
+	}
+	secret, err := randomString(apiKeySecretSize)
+	if err != nil {
+		return "", "", "", err
+	}
+	prefix := apiKeyPrefix + id
+	key := prefix + "_" + secret
+	return key, prefix, HashToken(key), nil
+}
+
+// randomString returns n random bytes encoded with URL-safe base64
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Join(model.ErrServiceError, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded SHA-256 hash of the random token. Tokens
+// have enough entropy, so unlike passwords they do not need a slow hash and
+// can be looked up by the hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+// Package auth issues and verifies signed access tokens and hashes secrets of
+// users: passwords and refresh tokens
+package auth
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"movie-lib/internal/model"
+	"strconv"
+	"time"
+)
+
+// signing methods of access tokens
+const (
+	HS256 = "HS256"
+	EdDSA = "EdDSA"
+)
+
+// minHMACSecretLength is a minimum length of the HS256 secret in bytes, it
+// equals to the size of the hash
+const minHMACSecretLength = 32
+
+var ErrInvalidConfig = errors.New("invalid auth config")
+
+type Config struct {
+	// SigningMethod is HS256 or EdDSA
+	SigningMethod string
+	// HMACSecret is a secret key of HS256, at least 32 bytes long
+	HMACSecret []byte
+	// Ed25519Seed is a 32 bytes seed of the private key of EdDSA
+	Ed25519Seed []byte
+	// Issuer is written to the iss claim and checked on verification
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// Claims are the data of the verified access token
+type Claims struct {
+	UserId    uint64
+	SessionId uint64
+	ExpiresAt time.Time
+}
+
+// accessClaims are the claims of the access token: the subject is the user id
+// and sid is the id of the session which the token was issued for
+type accessClaims struct {
+	SessionId uint64 `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// Issuer signs and verifies access tokens
+type Issuer struct {
+	method     jwt.SigningMethod
+	signKey    any
+	verifyKey  any
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewIssuer(cfg Config) (*Issuer, error) {
+	if cfg.AccessTokenTTL <= 0 || cfg.RefreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("%w: token ttl should be positive", ErrInvalidConfig)
+	}
+	i := &Issuer{
+		issuer:     cfg.Issuer,
+		accessTTL:  cfg.AccessTokenTTL,
+		refreshTTL: cfg.RefreshTokenTTL,
+	}
+
+	switch cfg.SigningMethod {
+	case HS256:
+		if len(cfg.HMACSecret) < minHMACSecretLength {
+			return nil, fmt.Errorf("%w: hmac secret should be at least %d bytes long",
+				ErrInvalidConfig, minHMACSecretLength)
+		}
+		i.method = jwt.SigningMethodHS256
+		i.signKey = cfg.HMACSecret
+		i.verifyKey = cfg.HMACSecret
+	case EdDSA:
+		if len(cfg.Ed25519Seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%w: ed25519 seed should be %d bytes long",
+				ErrInvalidConfig, ed25519.SeedSize)
+		}
+		key := ed25519.NewKeyFromSeed(cfg.Ed25519Seed)
+		i.method = jwt.SigningMethodEdDSA
+		i.signKey = key
+		i.verifyKey = key.Public()
+	default:
+		return nil, fmt.Errorf("%w: unknown signing method %q", ErrInvalidConfig, cfg.SigningMethod)
+	}
+	return i, nil
+}
+
+// AccessToken returns the signed access token of the session and the time
+// when it expires
+func (i *Issuer) AccessToken(userId, sessionId uint64) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(i.accessTTL)
+	token := jwt.NewWithClaims(i.method, accessClaims{
+		SessionId: sessionId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.issuer,
+			Subject:   strconv.FormatUint(userId, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signed, err := token.SignedString(i.signKey)
+	if err != nil {
+		return "", time.Time{}, errors.Join(model.ErrServiceError, err)
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken verifies the signature, the issuer and the expiration time
+// of the access token and returns its claims, model.ErrInvalidToken is
+// returned for all invalid tokens
+func (i *Issuer) ParseAccessToken(signed string) (Claims, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(signed, &claims, func(*jwt.Token) (any, error) {
+		return i.verifyKey, nil
+	},
+		// the algorithm is fixed, so tokens signed by other methods or with
+		// "none" are rejected before the signature check
+		jwt.WithValidMethods([]string{i.method.Alg()}),
+		jwt.WithIssuer(i.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Claims{}, errors.Join(model.ErrInvalidToken, err)
+	}
+
+	userId, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return Claims{}, errors.Join(model.ErrInvalidToken, err)
+	}
+	return Claims{
+		UserId:    userId,
+		SessionId: claims.SessionId,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// RefreshTokenTTL returns the lifetime of the session since the last refresh
+func (i *Issuer) RefreshTokenTTL() time.Duration {
+	return i.refreshTTL
+}
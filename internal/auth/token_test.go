@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movie-lib/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testSecret = []byte("test-secret-which-is-32-bytes-long")
+	testSeed   = []byte(strings.Repeat("s", ed25519.SeedSize))
+)
+
+type newIssuerTest struct {
+	description string
+	cfg         Config
+	err         error
+}
+
+func TestNewIssuer(t *testing.T) {
+	tests := []newIssuerTest{
+		{
+			description: "hmac issuer",
+			cfg:         Config{SigningMethod: HS256, HMACSecret: testSecret, AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+			err:         nil,
+		},
+		{
+			description: "ed25519 issuer",
+			cfg:         Config{SigningMethod: EdDSA, Ed25519Seed: testSeed, AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+			err:         nil,
+		},
+		{
+			description: "short hmac secret",
+			cfg:         Config{SigningMethod: HS256, HMACSecret: []byte("secret"), AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+			err:         ErrInvalidConfig,
+		},
+		{
+			description: "invalid ed25519 seed",
+			cfg:         Config{SigningMethod: EdDSA, Ed25519Seed: []byte("seed"), AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+			err:         ErrInvalidConfig,
+		},
+		{
+			description: "unknown signing method",
+			cfg:         Config{SigningMethod: "none", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+			err:         ErrInvalidConfig,
+		},
+		{
+			description: "zero ttl",
+			cfg:         Config{SigningMethod: HS256, HMACSecret: testSecret},
+			err:         ErrInvalidConfig,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := NewIssuer(test.cfg)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	for _, cfg := range []Config{
+		{SigningMethod: HS256, HMACSecret: testSecret, Issuer: "test", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+		{SigningMethod: EdDSA, Ed25519Seed: testSeed, Issuer: "test", AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+	} {
+		t.Run(cfg.SigningMethod, func(t *testing.T) {
+			issuer, err := NewIssuer(cfg)
+			require.NoError(t, err)
+
+			token, expiresAt, err := issuer.AccessToken(7, 42)
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+			claims, err := issuer.ParseAccessToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(7), claims.UserId)
+			assert.Equal(t, uint64(42), claims.SessionId)
+			assert.WithinDuration(t, expiresAt, claims.ExpiresAt, time.Second)
+
+			// tokens of other issuers are rejected
+			other, err := NewIssuer(Config{
+				SigningMethod:   HS256,
+				HMACSecret:      []byte(strings.Repeat("o", minHMACSecretLength)),
+				Issuer:          "test",
+				AccessTokenTTL:  time.Minute,
+				RefreshTokenTTL: time.Hour,
+			})
+			require.NoError(t, err)
+			otherToken, _, err := other.AccessToken(7, 42)
+			require.NoError(t, err)
+			_, err = issuer.ParseAccessToken(otherToken)
+			assert.ErrorIs(t, err, model.ErrInvalidToken)
+		})
+	}
+}
+
+type parseAccessTokenTest struct {
+	description string
+	claims      jwt.Claims
+	method      jwt.SigningMethod
+	key         any
+}
+
+func TestParseInvalidAccessToken(t *testing.T) {
+	issuer, err := NewIssuer(Config{
+		SigningMethod:   HS256,
+		HMACSecret:      testSecret,
+		Issuer:          "test",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	require.NoError(t, err)
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Minute))
+
+	tests := []parseAccessTokenTest{
+		{
+			description: "expired token",
+			claims: accessClaims{SessionId: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "test", Subject: "1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+			}},
+			method: jwt.SigningMethodHS256,
+			key:    testSecret,
+		},
+		{
+			description: "token without expiration time",
+			claims: accessClaims{SessionId: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "test", Subject: "1",
+			}},
+			method: jwt.SigningMethodHS256,
+			key:    testSecret,
+		},
+		{
+			description: "token of other issuer",
+			claims: accessClaims{SessionId: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "other", Subject: "1", ExpiresAt: expiresAt,
+			}},
+			method: jwt.SigningMethodHS256,
+			key:    testSecret,
+		},
+		{
+			description: "token with invalid subject",
+			claims: accessClaims{SessionId: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "test", Subject: "admin", ExpiresAt: expiresAt,
+			}},
+			method: jwt.SigningMethodHS256,
+			key:    testSecret,
+		},
+		{
+			description: "token signed by other method",
+			claims: accessClaims{SessionId: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "test", Subject: "1", ExpiresAt: expiresAt,
+			}},
+			method: jwt.SigningMethodHS512,
+			key:    testSecret,
+		},
+		{
+			description: "unsigned token",
+			claims: accessClaims{SessionId: 1, RegisteredClaims: jwt.RegisteredClaims{
+				Issuer: "test", Subject: "1", ExpiresAt: expiresAt,
+			}},
+			method: jwt.SigningMethodNone,
+			key:    jwt.UnsafeAllowNoneSignatureType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(test.method, test.claims).SignedString(test.key)
+			require.NoError(t, err)
+			_, err = issuer.ParseAccessToken(token)
+			assert.ErrorIs(t, err, model.ErrInvalidToken)
+		})
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("password1")
+	require.NoError(t, err)
+	assert.True(t, CheckPassword(hash, "password1"))
+	assert.False(t, CheckPassword(hash, "password2"))
+	assert.False(t, CheckPassword("", ""))
+}
+
+func TestRefreshToken(t *testing.T) {
+	token, hash, err := NewRefreshToken()
+	require.NoError(t, err)
+	other, _, err := NewRefreshToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.Equal(t, hash, HashToken(token))
+	assert.Len(t, hash, 64)
+}
+
+func TestApiKey(t *testing.T) {
+	key, prefix, hash, err := NewApiKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, prefix+"_"))
+	assert.Len(t, prefix, len("mlk_")+8)
+	assert.Equal(t, hash, HashToken(key))
+
+	other, otherPrefix, _, err := NewApiKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, prefix, otherPrefix)
+}
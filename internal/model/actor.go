@@ -14,10 +14,35 @@ type Actor struct {
 	SecondName string
 	Gender
 	Movies []Movie
+
+	// CrewOnly marks the person credited only in crews of movies, such
+	// persons are not listed, searched and suggested as actors. Casting the
+	// person in a movie makes them an actor.
+	CrewOnly bool
+
+	// Relevance is set only by the search
+	Relevance float64
+
+	// Characters and Billing are set only for the cast of the movie
+	Characters []string
+	Billing    int
 }
 
 type UpdateActor struct {
 	FirstName  string
 	SecondName string
 	Gender
+	CrewOnly bool
+}
+
+// ActorSortParams is an allow-list of params for sorting of actors, actors
+// are sorted by second and first name when sorted by Name
+var ActorSortParams = []SortParam{Name, MoviesCount}
+
+// ActorFilter restricts the list of actors, zero fields are not applied
+type ActorFilter struct {
+	Gender Gender
+
+	// MovieId selects actors who appeared in the movie
+	MovieId uint64
 }
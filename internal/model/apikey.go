@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ApiKeyScope limits operations of the API key: read keys can only get data
+type ApiKeyScope string
+
+const (
+	ReadScope  ApiKeyScope = "read"
+	WriteScope ApiKeyScope = "write"
+)
+
+// ApiKey is a long-lived credential of the user for non-interactive clients.
+// Only the hash of the key is stored, the key itself is returned once on
+// creation and the prefix tells keys apart in lists. Keys without the
+// expiration time never expire.
+type ApiKey struct {
+	Id        uint64
+	UserId    uint64
+	Name      string
+	Key       string
+	Prefix    string
+	KeyHash   string
+	Scope     ApiKeyScope
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
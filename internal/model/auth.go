@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Credentials are the data needed to check the password of the user, users
+// without a password hash can not log in
+type Credentials struct {
+	UserId       uint64
+	PasswordHash string
+	Disabled     bool
+}
+
+// Session is a login of the user. Only the hash of the refresh token of the
+// session is stored, the token is replaced on every refresh.
+type Session struct {
+	Id               uint64
+	UserId           uint64
+	RefreshTokenHash string
+	ExpiresAt        time.Time
+	CreatedAt        time.Time
+}
+
+// Tokens are issued on login and refresh: the short-lived access token
+// authorizes requests and the refresh token is exchanged for new tokens
+type Tokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Principal is the authenticated user of the request with the credential
+// which the request is authenticated by: a session of the access token or an
+// API key. Sessions have the write scope.
+type Principal struct {
+	UserId    uint64
+	SessionId uint64
+	ApiKeyId  uint64
+	Scope     ApiKeyScope
+}
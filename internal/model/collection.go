@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+type Visibility string
+
+const (
+	// Private collections can be read only by the owner and the users the
+	// collection is shared with
+	Private Visibility = "private"
+	// Public collections can be read by all users
+	Public Visibility = "public"
+)
+
+// Collection is a named ordered list of movies owned by the user, only the
+// owner can change it
+type Collection struct {
+	Id          uint64
+	OwnerId     uint64
+	Name        string
+	Description string
+	Visibility  Visibility
+	CreatedAt   time.Time
+
+	// SharedWith are ids of users who can read the collection regardless of
+	// its visibility
+	SharedWith []uint64
+
+	// Entries are set only for a single collection, they are ordered by
+	// positions
+	Entries []CollectionEntry
+}
+
+type UpdateCollection struct {
+	Name        string
+	Description string
+	Visibility  Visibility
+	SharedWith  []uint64
+}
+
+// CollectionEntry is a movie of the collection with the note of the owner
+type CollectionEntry struct {
+	MovieId uint64
+	Movie   Movie
+	Note    string
+}
+
+// CollectionFilter restricts the list of collections, zero fields are not
+// applied
+type CollectionFilter struct {
+	OwnerId uint64
+
+	// ReaderId selects collections which the user can read: own, public and
+	// shared with the user
+	ReaderId uint64
+}
@@ -5,15 +5,32 @@ import "errors"
 var (
 	ErrInvalidInput    = errors.New("invalid input body or query params")
 	ErrValidationError = errors.New("given struct is invalid")
+	ErrInvalidCursor   = errors.New("cursor is invalid or does not match the sort order")
 
-	ErrMovieNotExists = errors.New("movie with required id does not exist")
-	ErrActorNotExists = errors.New("actor with required id does not exist")
+	ErrMovieNotExists      = errors.New("movie with required id does not exist")
+	ErrActorNotExists      = errors.New("actor with required id does not exist")
+	ErrGenreNotExists      = errors.New("genre with required id does not exist")
+	ErrPersonNotExists     = errors.New("person with required id does not exist")
+	ErrReviewNotExists     = errors.New("review with required id does not exist")
+	ErrCollectionNotExists = errors.New("collection with required id does not exist")
+	ErrApiKeyNotExists     = errors.New("api key with required id does not exist")
+	ErrRoleNotExists       = errors.New("role with required name does not exist")
 
-	ErrUserNotExists = errors.New("user with required id does not exist")
-	ErrUnauthorized  = errors.New("authorization header with user id is missing")
+	ErrUserNotExists  = errors.New("user with required id does not exist")
+	ErrEntryNotExists = errors.New("movie is not in the list of the user")
+	ErrUnauthorized   = errors.New("authorization header with access token is missing")
+
+	ErrInvalidCredentials = errors.New("username or password is incorrect")
+	ErrInvalidToken       = errors.New("token is invalid, expired or revoked")
+	ErrSessionNotExists   = errors.New("session does not exist or has expired")
 
 	ErrPermissionDenied = errors.New("user with required id does not have permission for this operation")
 
+	ErrConflict    = errors.New("operation conflicts with existing data")
+	ErrLastAdmin   = errors.New("operation would leave no active admins")
+	ErrRoleInUse   = errors.New("role is assigned to users")
+	ErrBuiltInRole = errors.New("built-in role can not be changed or deleted")
+
 	ErrDatabaseError = errors.New("something wrong with database")
 	ErrServiceError  = errors.New("unknown error from the service")
 )
@@ -0,0 +1,10 @@
+package model
+
+type Genre struct {
+	Id   uint64
+	Name string
+}
+
+type UpdateGenre struct {
+	Name string
+}
@@ -9,7 +9,28 @@ type Movie struct {
 	ReleaseDate time.Time
 	Rating      float64
 	Actors      []Actor
+	// ActorsId is a short form of Cast for actors without characters, the
+	// actors are billed after the Cast in the order of the list
 	ActorsId    []uint64
+	Cast        []CastCredit
+	Genres      []Genre
+	GenresId    []uint64
+	Crew        []CrewMember
+	CrewCredits []CrewCredit
+
+	// CommunityRating is an average rating of the reviews of users and
+	// VotesCount is a number of the reviews, the rating is zero without
+	// reviews
+	CommunityRating float64
+	VotesCount      uint64
+
+	// Status is set only for the movie requested by the user
+	Status *MovieStatus
+
+	// Relevance and Snippet are set only by the search. Snippet is a part of
+	// the description with matches of the query wrapped in <b></b>.
+	Relevance float64
+	Snippet   string
 }
 
 type UpdateMovie struct {
@@ -18,12 +39,82 @@ type UpdateMovie struct {
 	ReleaseDate time.Time
 	Rating      float64
 	Actors      []uint64
+	Cast        []CastCredit
+	Genres      []uint64
+	Crew        []CrewCredit
+}
+
+// CastCredit links the actor to the movie with the characters played by the
+// actor
+type CastCredit struct {
+	ActorId    uint64
+	Characters []string
+	// Billing is a position of the actor in the cast, zero means the
+	// position of the credit in the list
+	Billing int
+}
+
+// MovieCast joins the cast given by credits and bare ids of actors, the
+// actors are billed by their positions in the joined list unless the
+// billing of the credit is set
+func MovieCast(cast []CastCredit, actorsId []uint64) []CastCredit {
+	res := make([]CastCredit, 0, len(cast)+len(actorsId))
+	res = append(res, cast...)
+	for _, id := range actorsId {
+		res = append(res, CastCredit{ActorId: id})
+	}
+	for i := range res {
+		if res[i].Billing == 0 {
+			res[i].Billing = i + 1
+		}
+		if res[i].Characters == nil {
+			res[i].Characters = make([]string, 0)
+		}
+	}
+	return res
 }
 
-type SortParam string
+// MovieSortParams is an allow-list of params for sorting of movies
+var MovieSortParams = []SortParam{Title, Rating, ReleaseDate}
+
+// SearchMovieSortParams is an allow-list of params for sorting of found movies
+var SearchMovieSortParams = []SortParam{Relevance, Title, Rating, ReleaseDate}
+
+// Match defines whether the movie should have any or all of the actors or
+// genres of the filter
+type Match string
 
 const (
-	Title       SortParam = "title"
-	Rating      SortParam = "rating"
-	ReleaseDate SortParam = "release_date"
+	// MatchAny selects movies with at least one of the actors or genres
+	MatchAny Match = "any"
+	// MatchAll selects movies with all the actors or genres
+	MatchAll Match = "all"
 )
+
+// MovieFilter restricts the list of movies, nil and empty fields are not
+// applied. Bounds of the ranges are inclusive.
+type MovieFilter struct {
+	ReleaseDateFrom *time.Time
+	ReleaseDateTo   *time.Time
+	RatingFrom      *float64
+	RatingTo        *float64
+
+	// ActorsId selects movies with any or all of the actors depending on
+	// ActorsMatch, MatchAny is used by default
+	ActorsId    []uint64
+	ActorsMatch Match
+
+	// ActorGender selects movies with at least one actor of the gender
+	ActorGender Gender
+
+	// GenresId selects movies with any or all of the genres depending on
+	// GenresMatch, MatchAny is used by default
+	GenresId    []uint64
+	GenresMatch Match
+
+	// InWatchlist and Watched select movies which are or are not in the
+	// watchlist or the watched history of the user UserId
+	InWatchlist *bool
+	Watched     *bool
+	UserId      uint64
+}
@@ -0,0 +1,54 @@
+package model
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// Page is a request of one page of a list. Cursor is an opaque string
+// returned as NextCursor with the previous page, empty cursor means the
+// first page.
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// MovieList is a page of movies. NextCursor is empty on the last page,
+// Total is a number of movies in all pages.
+type MovieList struct {
+	Movies     []Movie
+	NextCursor string
+	Total      uint64
+}
+
+// ActorList is a page of actors. NextCursor is empty on the last page,
+// Total is a number of actors in all pages.
+type ActorList struct {
+	Actors     []Actor
+	NextCursor string
+	Total      uint64
+}
+
+// ReviewList is a page of reviews. NextCursor is empty on the last page,
+// Total is a number of reviews in all pages.
+type ReviewList struct {
+	Reviews    []Review
+	NextCursor string
+	Total      uint64
+}
+
+// CollectionList is a page of collections. NextCursor is empty on the last
+// page, Total is a number of collections in all pages.
+type CollectionList struct {
+	Collections []Collection
+	NextCursor  string
+	Total       uint64
+}
+
+// UserList is a page of users. NextCursor is empty on the last page, Total
+// is a number of users in all pages.
+type UserList struct {
+	Users      []User
+	NextCursor string
+	Total      uint64
+}
@@ -0,0 +1,50 @@
+package model
+
+// Permission allows users to perform a group of operations
+type Permission string
+
+const (
+	MoviesWrite         Permission = "movies:write"
+	MoviesDelete        Permission = "movies:delete"
+	ActorsWrite         Permission = "actors:write"
+	ActorsDelete        Permission = "actors:delete"
+	GenresWrite         Permission = "genres:write"
+	GenresDelete        Permission = "genres:delete"
+	ReviewsModerate     Permission = "reviews:moderate"
+	CollectionsModerate Permission = "collections:moderate"
+	UsersManage         Permission = "users:manage"
+	RolesManage         Permission = "roles:manage"
+	StatsRead           Permission = "stats:read"
+)
+
+// Permissions lists all known permissions
+var Permissions = []Permission{
+	MoviesWrite,
+	MoviesDelete,
+	ActorsWrite,
+	ActorsDelete,
+	GenresWrite,
+	GenresDelete,
+	ReviewsModerate,
+	CollectionsModerate,
+	UsersManage,
+	RolesManage,
+	StatsRead,
+}
+
+// RolePermissions is a role with permissions granted to its users,
+// permissions are ordered by name
+type RolePermissions struct {
+	Role        Role
+	Permissions []Permission
+}
+
+// Has reports whether the role grants the permission
+func (r RolePermissions) Has(permission Permission) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
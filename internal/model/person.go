@@ -0,0 +1,42 @@
+package model
+
+// CreditRole is a role of the person in the movie. Actors are linked to
+// movies as the cast, other roles are crew credits.
+type CreditRole string
+
+const (
+	ActorRole    CreditRole = "actor"
+	DirectorRole CreditRole = "director"
+	WriterRole   CreditRole = "writer"
+	ComposerRole CreditRole = "composer"
+	ProducerRole CreditRole = "producer"
+)
+
+// CrewRoles is an ordered list of roles of crew credits, crew of the movie is
+// ordered by it
+var CrewRoles = []CreditRole{DirectorRole, WriterRole, ComposerRole, ProducerRole}
+
+// Person is anyone who took part in movies. Persons are stored as actors, so
+// an actor is a person who may also have crew credits, and persons who are
+// not actors are marked as CrewOnly.
+type Person = Actor
+
+// CrewCredit links the person to the movie in the crew role
+type CrewCredit struct {
+	PersonId uint64
+	Role     CreditRole
+}
+
+// CrewMember is the person credited in the crew of the movie
+type CrewMember struct {
+	Person
+	Role CreditRole
+}
+
+// Filmography is a list of movies of the person grouped by roles
+type Filmography struct {
+	Person Person
+	// Credits maps roles to movies ordered by release date, roles without
+	// movies are omitted
+	Credits map[CreditRole][]Movie
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 10
+)
+
+// Review is a rating of the movie given by the user with an optional text,
+// every user can review the movie only once
+type Review struct {
+	Id        uint64
+	MovieId   uint64
+	UserId    uint64
+	Rating    int
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type UpdateReview struct {
+	Rating int
+	Text   string
+}
+
+// ReviewFilter restricts the list of reviews, zero fields are not applied
+type ReviewFilter struct {
+	MovieId uint64
+	UserId  uint64
+}
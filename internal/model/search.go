@@ -0,0 +1,39 @@
+package model
+
+const (
+	DefaultFuzzySearchLimit = 10
+	MaxFuzzySearchLimit     = 50
+
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 20
+	// MinSuggestPrefixLength is the minimal number of characters of the
+	// prefix, shorter prefixes match too many titles and names
+	MinSuggestPrefixLength = 2
+)
+
+// FuzzySearchResult contains movies with titles and actors with names
+// similar to the query ordered by similarity, the similarity is stored in
+// their Relevance
+type FuzzySearchResult struct {
+	Movies []Movie
+	Actors []Actor
+
+	// DidYouMean is the most similar title or name, it is suggested only when
+	// the full-text search of movies finds nothing
+	DidYouMean string
+}
+
+type SuggestionType string
+
+const (
+	MovieSuggestion SuggestionType = "movie"
+	ActorSuggestion SuggestionType = "actor"
+)
+
+// Suggestion is a movie or an actor whose title or name starts with the
+// typed prefix, Text is the title of the movie or the full name of the actor
+type Suggestion struct {
+	Id   uint64
+	Type SuggestionType
+	Text string
+}
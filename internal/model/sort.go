@@ -0,0 +1,46 @@
+package model
+
+import "strings"
+
+type SortParam string
+
+const (
+	Title       SortParam = "title"
+	Rating      SortParam = "rating"
+	ReleaseDate SortParam = "release_date"
+	// Relevance is a relevance of the movie to the search query, it is
+	// available only for the search
+	Relevance SortParam = "relevance"
+
+	Name        SortParam = "name"
+	MoviesCount SortParam = "movies_count"
+)
+
+// SortKey is a sort param with direction
+type SortKey struct {
+	Param SortParam
+	Desc  bool
+}
+
+// Sort is a list of sort keys, items with equal values of the first key are
+// compared by the next one and so on
+type Sort []SortKey
+
+// ParseSort parses comma separated list of sort params like "-rating,title",
+// "-" before the param means descending order. Params are not validated.
+func ParseSort(s string) Sort {
+	if s == "" {
+		return nil
+	}
+	params := strings.Split(s, ",")
+	sort := make(Sort, 0, len(params))
+	for _, param := range params {
+		param = strings.TrimSpace(param)
+		desc := strings.HasPrefix(param, "-")
+		sort = append(sort, SortKey{
+			Param: SortParam(strings.TrimPrefix(param, "-")),
+			Desc:  desc,
+		})
+	}
+	return sort
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Role string
 
 const (
@@ -7,3 +9,21 @@ const (
 	Regular Role = "regular"
 	Admin   Role = "admin"
 )
+
+// User is an account of the user. Usernames are unique regardless of case,
+// the email is optional. Disabled users can not perform any operation.
+type User struct {
+	Id        uint64
+	Username  string
+	Email     string
+	Role      Role
+	Disabled  bool
+	CreatedAt time.Time
+}
+
+type UpdateUser struct {
+	Username string
+	Email    string
+	Role     Role
+	Disabled bool
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// MovieStatus is a status of the movie in the lists of the user
+type MovieStatus struct {
+	InWatchlist bool
+	Watched     bool
+	// WatchedAt is a date when the user watched the movie, it is zero if
+	// the movie is not watched
+	WatchedAt time.Time
+}
@@ -1,10 +1,8 @@
 package httpserver
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"movie-lib/internal/app"
 	"movie-lib/internal/model"
@@ -13,9 +11,10 @@ import (
 )
 
 // @Summary		Добавление актёра
-// @Description	Добавляет нового актёра
+// @Description	Добавляет нового актёра или члена съёмочной группы, который не снимался как актёр
 // @Tags			actors
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
 // @Param			input	body		createActorData	true	"Информация о новом актёре"
@@ -25,42 +24,39 @@ import (
 // @Failure		401		{object}	actorResponse	"Ошибка авторизации"
 // @Failure		403		{object}	actorResponse	"Ошибка авторизации"
 // @Router			/actors/ [post]
-func createActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func createActorHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 		var data createActorData
 		if err = json.Unmarshal(body, &data); err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		actor, err := a.CreateActor(ctx, userId, model.Actor{
+		actor, err := a.CreateActor(r.Context(), model.Actor{
 			FirstName:  data.FirstName,
 			SecondName: data.SecondName,
 			Gender:     data.Gender,
+			CrewOnly:   data.CrewOnly,
 		})
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprint(w, actorResponseOk(actor))
+			writeResponse(w, actorResponseOk(actor))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
 		case errors.Is(err, model.ErrPermissionDenied):
-			http.Error(w, errorResponse(model.ErrPermissionDenied), http.StatusForbidden)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -69,6 +65,7 @@ func createActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Description	Обновляет поля актёра по id
 // @Tags			actors
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
 // @Param			actor_id	query		string			true	"id актёра"
@@ -80,49 +77,46 @@ func createActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Failure		401			{object}	actorResponse	"Ошибка авторизации"
 // @Failure		403			{object}	actorResponse	"Ошибка авторизации"
 // @Router			/actors/ [put]
-func updateActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func updateActorHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
 		actorId, err := strconv.ParseUint(r.URL.Query().Get("actor_id"), 10, 64)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 		var data updateActorData
 		if err = json.Unmarshal(body, &data); err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		actor, err := a.UpdateActor(ctx, userId, actorId, model.UpdateActor{
+		actor, err := a.UpdateActor(r.Context(), actorId, model.UpdateActor{
 			FirstName:  data.FirstName,
 			SecondName: data.SecondName,
 			Gender:     data.Gender,
+			CrewOnly:   data.CrewOnly,
 		})
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprint(w, actorResponseOk(actor))
+			writeResponse(w, actorResponseOk(actor))
 		case errors.Is(err, model.ErrActorNotExists):
-			http.Error(w, errorResponse(model.ErrActorNotExists), http.StatusNotFound)
+			writeError(w, model.ErrActorNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
 		case errors.Is(err, model.ErrPermissionDenied):
-			http.Error(w, errorResponse(model.ErrPermissionDenied), http.StatusForbidden)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -131,6 +125,7 @@ func updateActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Description	Удаляет актёра по id
 // @Tags			actors
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Produce		json
 // @Param			actor_id	query		string			true	"id актёра"
 // @Success		200			{object}	actorResponse	"Пустая структура"
@@ -140,35 +135,29 @@ func updateActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Failure		401			{object}	actorResponse	"Ошибка авторизации"
 // @Failure		403			{object}	actorResponse	"Ошибка авторизации"
 // @Router			/actors/ [delete]
-func deleteActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func deleteActorHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
 		actorId, err := strconv.ParseUint(r.URL.Query().Get("actor_id"), 10, 64)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		err = a.DeleteActor(ctx, userId, actorId)
+		err = a.DeleteActor(r.Context(), actorId)
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprint(w, errorResponse(nil))
+			writeResponse(w, errorResponse(nil))
 		case errors.Is(err, model.ErrActorNotExists):
-			http.Error(w, errorResponse(model.ErrActorNotExists), http.StatusNotFound)
+			writeError(w, model.ErrActorNotExists, http.StatusNotFound)
 		case errors.Is(err, model.ErrPermissionDenied):
-			http.Error(w, errorResponse(model.ErrPermissionDenied), http.StatusForbidden)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -177,6 +166,7 @@ func deleteActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Description	Возвращает актёра с указанным id
 // @Tags			actors
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Produce		json
 // @Param			actor_id	query		string			true	"id актёра"
 // @Success		200			{object}	actorResponse	"Информация об актёре"
@@ -186,69 +176,86 @@ func deleteActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Failure		401			{object}	actorResponse	"Ошибка авторизации"
 // @Failure		403			{object}	actorResponse	"Ошибка авторизации"
 // @Router			/actors/ [get]
-func getActorHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func getActorHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
-
 		actorId, err := strconv.ParseUint(r.URL.Query().Get("actor_id"), 10, 64)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
 		var actor model.Actor
-		actor, err = a.GetActor(ctx, userId, actorId)
+		actor, err = a.GetActor(r.Context(), actorId)
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprint(w, actorResponseOk(actor))
+			writeResponse(w, actorResponseOk(actor))
 		case errors.Is(err, model.ErrActorNotExists):
-			http.Error(w, errorResponse(model.ErrActorNotExists), http.StatusNotFound)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrActorNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
 
 // @Summary		Получение списка актёров
-// @Description	Возвращает список актёров
+// @Description	Возвращает страницу списка актёров, удовлетворяющих фильтрам, с их фильмами и общее количество таких актёров
 // @Tags			actors
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Produce		json
-// @Success		200	{object}	actorListResponse	"Информация об актёрах"
-// @Failure		500	{object}	actorListResponse	"Проблемы на стороне сервера"
-// @Failure		401	{object}	actorListResponse	"Ошибка авторизации"
-// @Failure		403	{object}	actorListResponse	"Ошибка авторизации"
+// @Param			pattern		query		string				false	"Поиск по имени и фамилии актёра без учёта регистра, каждое слово должно содержаться в имени или фамилии"
+// @Param			gender		query		string				false	"Пол актёра (male/female)"
+// @Param			movie_id	query		int					false	"Актёры, игравшие в фильме с указанным id"
+// @Param			sort_by		query		string				false	"Параметры сортировки через запятую, минус перед параметром означает сортировку по убыванию, например -movies_count,name. Поддерживаемые параметры: name (фамилия и имя), movies_count (количество фильмов). По умолчанию актёры упорядочены по id"
+// @Param			limit		query		int					false	"Количество актёров на странице, по умолчанию 50, не больше 500"
+// @Param			cursor		query		string				false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200			{object}	actorListResponse	"Информация об актёрах"
+// @Failure		400			{object}	actorListResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	actorListResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	actorListResponse	"Ошибка авторизации"
+// @Failure		403			{object}	actorListResponse	"Ошибка авторизации"
 // @Router			/actors/list/ [get]
-func getActorsListHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func getActorsListHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
+		page, err := parsePage(r)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		actors, err := a.GetActors(ctx, userId)
+		var filter model.ActorFilter
+		filter, err = parseActorFilter(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		sortBy := model.ParseSort(r.URL.Query().Get("sort_by"))
+
+		var actors model.ActorList
+		if pattern := parsePattern(r); pattern != "" {
+			actors, err = a.SearchActors(r.Context(), pattern, filter, sortBy, page)
+		} else {
+			actors, err = a.GetActors(r.Context(), filter, sortBy, page)
+		}
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprint(w, actorListResponseOk(actors))
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeResponse(w, actorListResponseOk(actors))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrInvalidCursor):
+			writeError(w, model.ErrInvalidCursor, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
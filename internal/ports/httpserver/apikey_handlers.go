@@ -0,0 +1,145 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Summary		Создание API-ключа
+// @Description	Создаёт долгоживущий API-ключ текущего пользователя для сервисов. Ключ передаётся в заголовке X-API-Key и возвращается только один раз. Ключи создаются только по токену доступа
+// @Tags			api-keys
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		createApiKeyData	true	"Название, область действия и время истечения ключа"
+// @Success		200		{object}	apiKeyResponse		"Информация о ключе вместе с самим ключом"
+// @Failure		400		{object}	apiKeyResponse		"Неверный формат входных данных"
+// @Failure		500		{object}	apiKeyResponse		"Проблемы на стороне сервера"
+// @Failure		401		{object}	apiKeyResponse		"Ошибка авторизации"
+// @Failure		403		{object}	apiKeyResponse		"Ошибка авторизации"
+// @Router			/api-keys/ [post]
+func createApiKeyHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data createApiKeyData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		key := model.ApiKey{
+			Name:  data.Name,
+			Scope: data.Scope,
+		}
+		if data.ExpiresAt != nil {
+			expiresAt := time.Unix(*data.ExpiresAt, 0).UTC()
+			key.ExpiresAt = &expiresAt
+		}
+		key, err = a.CreateApiKey(r.Context(), key)
+
+		switch {
+		case err == nil:
+			writeResponse(w, apiKeyResponseOk(key))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Отзыв API-ключа
+// @Description	Удаляет API-ключ, после этого он перестаёт приниматься. Пользователи с разрешением users:manage могут отозвать ключ любого пользователя, остальные только свой
+// @Tags			api-keys
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			key_id	query		string			true	"id ключа"
+// @Success		200		{object}	apiKeyResponse	"Пустая структура"
+// @Failure		404		{object}	apiKeyResponse	"Ключа не существует"
+// @Failure		400		{object}	apiKeyResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	apiKeyResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	apiKeyResponse	"Ошибка авторизации"
+// @Failure		403		{object}	apiKeyResponse	"Ошибка авторизации"
+// @Router			/api-keys/ [delete]
+func revokeApiKeyHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.URL.Query().Get("key_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.RevokeApiKey(r.Context(), id)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrApiKeyNotExists):
+			writeError(w, model.ErrApiKeyNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка API-ключей
+// @Description	Возвращает API-ключи пользователя, упорядоченные по id, включая истёкшие. Сами ключи не возвращаются, только их начало. Пользователи с разрешением users:manage могут получить ключи любого пользователя, остальные только свои
+// @Tags			api-keys
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			user_id	query		string				false	"id пользователя, по умолчанию текущий пользователь"
+// @Success		200		{object}	apiKeyListResponse	"Информация о ключах"
+// @Failure		400		{object}	apiKeyListResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	apiKeyListResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	apiKeyListResponse	"Ошибка авторизации"
+// @Failure		403		{object}	apiKeyListResponse	"Ошибка авторизации"
+// @Router			/api-keys/list/ [get]
+func getApiKeysListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// the principal is set by authMiddleware
+		principal, _ := app.PrincipalFromContext(r.Context())
+		ownerId := principal.UserId
+		if r.URL.Query().Has("user_id") {
+			var err error
+			if ownerId, err = strconv.ParseUint(r.URL.Query().Get("user_id"), 10, 64); err != nil {
+				writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+				return
+			}
+		}
+
+		keys, err := a.GetApiKeys(r.Context(), ownerId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, apiKeyListResponseOk(keys))
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
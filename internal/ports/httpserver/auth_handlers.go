@@ -0,0 +1,133 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Вход
+// @Description	Проверяет имя пользователя и пароль и начинает новую сессию. Возвращает короткоживущий токен доступа и одноразовый токен обновления
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		loginData		true	"Имя пользователя и пароль"
+// @Success		200		{object}	tokensResponse	"Токены сессии"
+// @Failure		400		{object}	tokensResponse	"Неверный формат входных данных"
+// @Failure		401		{object}	tokensResponse	"Неверное имя пользователя или пароль"
+// @Failure		500		{object}	tokensResponse	"Проблемы на стороне сервера"
+// @Router			/auth/login/ [post]
+func loginHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data loginData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := a.Login(r.Context(), data.Username, data.Password)
+
+		switch {
+		case err == nil:
+			writeResponse(w, tokensResponseOk(tokens))
+		case errors.Is(err, model.ErrInvalidCredentials):
+			writeError(w, model.ErrInvalidCredentials, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Обновление токенов
+// @Description	Обменивает токен обновления на новую пару токенов той же сессии и продлевает сессию. Каждый токен обновления можно использовать только один раз
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		refreshData		true	"Токен обновления"
+// @Success		200		{object}	tokensResponse	"Новые токены сессии"
+// @Failure		400		{object}	tokensResponse	"Неверный формат входных данных"
+// @Failure		401		{object}	tokensResponse	"Токен недействителен, истёк или отозван"
+// @Failure		500		{object}	tokensResponse	"Проблемы на стороне сервера"
+// @Router			/auth/refresh/ [post]
+func refreshHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data refreshData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := a.Refresh(r.Context(), data.RefreshToken)
+
+		switch {
+		case err == nil:
+			writeResponse(w, tokensResponseOk(tokens))
+		case errors.Is(err, model.ErrInvalidToken):
+			writeError(w, model.ErrInvalidToken, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Выход
+// @Description	Отзывает текущую сессию или все сессии пользователя, их токены доступа и обновления перестают приниматься
+// @Tags			auth
+// @Security		ApiKeyAuth
+// @Produce		json
+// @Param			all	query		bool			false	"Завершить все сессии пользователя"
+// @Success		200	{object}	tokensResponse	"Пустая структура"
+// @Failure		400	{object}	tokensResponse	"Неверный формат входных данных"
+// @Failure		500	{object}	tokensResponse	"Проблемы на стороне сервера"
+// @Failure		401	{object}	tokensResponse	"Ошибка авторизации"
+// @Failure		403	{object}	tokensResponse	"Запрос авторизован API-ключом"
+// @Router			/auth/logout/ [post]
+func logoutHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			all bool
+			err error
+		)
+		if r.URL.Query().Has("all") {
+			if all, err = strconv.ParseBool(r.URL.Query().Get("all")); err != nil {
+				writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+				return
+			}
+		}
+
+		err = a.Logout(r.Context(), all)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrSessionNotExists):
+			writeError(w, model.ErrInvalidToken, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
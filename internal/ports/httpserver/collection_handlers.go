@@ -0,0 +1,486 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Создание подборки
+// @Description	Создаёт именованную подборку фильмов, владельцем которой становится пользователь. Подборка по умолчанию приватная, её можно открыть всем пользователям или отдельным пользователям только для чтения
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		createCollectionData	true	"Информация о новой подборке"
+// @Success		200		{object}	collectionResponse		"Информация о подборке"
+// @Failure		400		{object}	collectionResponse		"Неверный формат входных данных"
+// @Failure		500		{object}	collectionResponse		"Проблемы на стороне сервера"
+// @Failure		401		{object}	collectionResponse		"Ошибка авторизации"
+// @Failure		403		{object}	collectionResponse		"Ошибка авторизации"
+// @Router			/collections/ [post]
+func createCollectionHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data createCollectionData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		collection, err := a.CreateCollection(r.Context(), model.Collection{
+			Name:        data.Name,
+			Description: data.Description,
+			Visibility:  data.Visibility,
+			SharedWith:  data.SharedWith,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionResponseOk(collection))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Обновление подборки
+// @Description	Изменяет название, описание, видимость подборки и список пользователей, которым она доступна, изменить подборку может только её владелец
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			collection_id	query		string					true	"id подборки"
+// @Param			input			body		updateCollectionData	true	"Новые поля"
+// @Success		200				{object}	collectionResponse		"Информация о подборке"
+// @Failure		404				{object}	collectionResponse		"Подборки не существует"
+// @Failure		400				{object}	collectionResponse		"Неверный формат входных данных"
+// @Failure		500				{object}	collectionResponse		"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse		"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse		"Ошибка авторизации"
+// @Router			/collections/ [put]
+func updateCollectionHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data updateCollectionData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		collection, err := a.UpdateCollection(r.Context(), collectionId, model.UpdateCollection{
+			Name:        data.Name,
+			Description: data.Description,
+			Visibility:  data.Visibility,
+			SharedWith:  data.SharedWith,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionResponseOk(collection))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление подборки
+// @Description	Удаляет подборку по id, владелец может удалить свою подборку, а пользователь с разрешением collections:moderate любую
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			collection_id	query		string				true	"id подборки"
+// @Success		200				{object}	collectionResponse	"Пустая структура"
+// @Failure		404				{object}	collectionResponse	"Подборки не существует"
+// @Failure		400				{object}	collectionResponse	"Неверный формат входных данных"
+// @Failure		500				{object}	collectionResponse	"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse	"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse	"Ошибка авторизации"
+// @Router			/collections/ [delete]
+func deleteCollectionHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.DeleteCollection(r.Context(), collectionId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение подборки
+// @Description	Возвращает подборку с фильмами по порядку и заметками к ним. Подборка доступна владельцу, всем пользователям, если она публичная, и пользователям, которым она открыта
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			collection_id	query		string				true	"id подборки"
+// @Success		200				{object}	collectionResponse	"Информация о подборке"
+// @Failure		404				{object}	collectionResponse	"Подборки не существует"
+// @Failure		400				{object}	collectionResponse	"Неверный формат входных данных"
+// @Failure		500				{object}	collectionResponse	"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse	"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse	"Подборка недоступна пользователю"
+// @Router			/collections/ [get]
+func getCollectionHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var collection model.Collection
+		collection, err = a.GetCollection(r.Context(), collectionId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionResponseOk(collection))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка подборок
+// @Description	Возвращает страницу списка доступных пользователю подборок без фильмов: своих, публичных и открытых ему, и общее количество подходящих подборок
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			owner_id	query		int						false	"Подборки пользователя с указанным id"
+// @Param			limit		query		int						false	"Количество подборок на странице, по умолчанию 50, не больше 500"
+// @Param			cursor		query		string					false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200			{object}	collectionListResponse	"Информация о подборках"
+// @Failure		400			{object}	collectionListResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	collectionListResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	collectionListResponse	"Ошибка авторизации"
+// @Failure		403			{object}	collectionListResponse	"Ошибка авторизации"
+// @Router			/collections/list/ [get]
+func getCollectionsListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := parsePage(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var filter model.CollectionFilter
+		filter, err = parseCollectionFilter(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var collections model.CollectionList
+		collections, err = a.GetCollections(r.Context(), filter, page)
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionListResponseOk(collections))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrInvalidCursor):
+			writeError(w, model.ErrInvalidCursor, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Добавление фильма в подборку
+// @Description	Добавляет фильм с заметкой в конец подборки, изменять фильмы подборки может только её владелец
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			collection_id	query		string					true	"id подборки"
+// @Param			input			body		addCollectionEntryData	true	"Фильм и заметка к нему"
+// @Success		200				{object}	collectionResponse		"Информация о подборке"
+// @Failure		404				{object}	collectionResponse		"Подборки или фильма не существует"
+// @Failure		400				{object}	collectionResponse		"Неверный формат входных данных"
+// @Failure		409				{object}	collectionResponse		"Фильм уже есть в подборке"
+// @Failure		500				{object}	collectionResponse		"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse		"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse		"Ошибка авторизации"
+// @Router			/collections/entries/ [post]
+func addCollectionEntryHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data addCollectionEntryData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		collection, err := a.AddCollectionEntry(r.Context(), collectionId, model.CollectionEntry{
+			MovieId: data.MovieId,
+			Note:    data.Note,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionResponseOk(collection))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrMovieNotExists):
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Изменение заметки к фильму подборки
+// @Description	Изменяет заметку владельца подборки к фильму
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			collection_id	query		string						true	"id подборки"
+// @Param			movie_id		query		string						true	"id фильма"
+// @Param			input			body		updateCollectionEntryData	true	"Новая заметка"
+// @Success		200				{object}	collectionResponse			"Информация о подборке"
+// @Failure		404				{object}	collectionResponse			"Подборки не существует или фильма нет в подборке"
+// @Failure		400				{object}	collectionResponse			"Неверный формат входных данных"
+// @Failure		500				{object}	collectionResponse			"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse			"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse			"Ошибка авторизации"
+// @Router			/collections/entries/ [put]
+func updateCollectionEntryHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data updateCollectionEntryData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		collection, err := a.UpdateCollectionEntry(r.Context(), collectionId, model.CollectionEntry{
+			MovieId: movieId,
+			Note:    data.Note,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionResponseOk(collection))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrEntryNotExists):
+			writeError(w, model.ErrEntryNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление фильма из подборки
+// @Description	Удаляет фильм из подборки, порядок остальных фильмов сохраняется
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			collection_id	query		string				true	"id подборки"
+// @Param			movie_id		query		string				true	"id фильма"
+// @Success		200				{object}	collectionResponse	"Пустая структура"
+// @Failure		404				{object}	collectionResponse	"Подборки не существует или фильма нет в подборке"
+// @Failure		400				{object}	collectionResponse	"Неверный формат входных данных"
+// @Failure		500				{object}	collectionResponse	"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse	"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse	"Ошибка авторизации"
+// @Router			/collections/entries/ [delete]
+func removeCollectionEntryHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.RemoveCollectionEntry(r.Context(), collectionId, movieId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrEntryNotExists):
+			writeError(w, model.ErrEntryNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Изменение порядка фильмов подборки
+// @Description	Расставляет фильмы подборки в указанном порядке, список должен содержать каждый фильм подборки ровно один раз
+// @Tags			collections
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			collection_id	query		string					true	"id подборки"
+// @Param			input			body		reorderCollectionData	true	"Новый порядок фильмов"
+// @Success		200				{object}	collectionResponse		"Информация о подборке"
+// @Failure		404				{object}	collectionResponse		"Подборки не существует"
+// @Failure		400				{object}	collectionResponse		"Неверный формат входных данных"
+// @Failure		500				{object}	collectionResponse		"Проблемы на стороне сервера"
+// @Failure		401				{object}	collectionResponse		"Ошибка авторизации"
+// @Failure		403				{object}	collectionResponse		"Ошибка авторизации"
+// @Router			/collections/order/ [put]
+func reorderCollectionHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionId, err := strconv.ParseUint(r.URL.Query().Get("collection_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data reorderCollectionData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		collection, err := a.ReorderCollection(r.Context(), collectionId, data.Movies)
+
+		switch {
+		case err == nil:
+			writeResponse(w, collectionResponseOk(collection))
+		case errors.Is(err, model.ErrCollectionNotExists):
+			writeError(w, model.ErrCollectionNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Добавление жанра
+// @Description	Добавляет новый жанр
+// @Tags			genres
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		createGenreData	true	"Информация о новом жанре"
+// @Success		200		{object}	genreResponse	"Информация о жанре"
+// @Failure		400		{object}	genreResponse	"Неверный формат входных данных"
+// @Failure		409		{object}	genreResponse	"Жанр с таким названием уже существует"
+// @Failure		500		{object}	genreResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	genreResponse	"Ошибка авторизации"
+// @Failure		403		{object}	genreResponse	"Ошибка авторизации"
+// @Router			/genres/ [post]
+func createGenreHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data createGenreData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		genre, err := a.CreateGenre(r.Context(), model.Genre{
+			Name: data.Name,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, genreResponseOk(genre))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Обновление жанра
+// @Description	Переименовывает жанр по id
+// @Tags			genres
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			genre_id	query		string			true	"id жанра"
+// @Param			input		body		updateGenreData	true	"Новые поля"
+// @Success		200			{object}	genreResponse	"Информация о жанре"
+// @Failure		404			{object}	genreResponse	"Жанра не существует"
+// @Failure		400			{object}	genreResponse	"Неверный формат входных данных"
+// @Failure		409			{object}	genreResponse	"Жанр с таким названием уже существует"
+// @Failure		500			{object}	genreResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	genreResponse	"Ошибка авторизации"
+// @Failure		403			{object}	genreResponse	"Ошибка авторизации"
+// @Router			/genres/ [put]
+func updateGenreHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genreId, err := strconv.ParseUint(r.URL.Query().Get("genre_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data updateGenreData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		genre, err := a.UpdateGenre(r.Context(), genreId, model.UpdateGenre{
+			Name: data.Name,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, genreResponseOk(genre))
+		case errors.Is(err, model.ErrGenreNotExists):
+			writeError(w, model.ErrGenreNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление жанра
+// @Description	Удаляет жанр по id, фильмы жанра остаются без него
+// @Tags			genres
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			genre_id	query		string			true	"id жанра"
+// @Success		200			{object}	genreResponse	"Пустая структура"
+// @Failure		404			{object}	genreResponse	"Жанра не существует"
+// @Failure		400			{object}	genreResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	genreResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	genreResponse	"Ошибка авторизации"
+// @Failure		403			{object}	genreResponse	"Ошибка авторизации"
+// @Router			/genres/ [delete]
+func deleteGenreHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genreId, err := strconv.ParseUint(r.URL.Query().Get("genre_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.DeleteGenre(r.Context(), genreId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrGenreNotExists):
+			writeError(w, model.ErrGenreNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение жанра
+// @Description	Возвращает жанр с указанным id
+// @Tags			genres
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			genre_id	query		string			true	"id жанра"
+// @Success		200			{object}	genreResponse	"Информация о жанре"
+// @Failure		404			{object}	genreResponse	"Жанра не существует"
+// @Failure		400			{object}	genreResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	genreResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	genreResponse	"Ошибка авторизации"
+// @Failure		403			{object}	genreResponse	"Ошибка авторизации"
+// @Router			/genres/ [get]
+func getGenreHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genreId, err := strconv.ParseUint(r.URL.Query().Get("genre_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var genre model.Genre
+		genre, err = a.GetGenre(r.Context(), genreId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, genreResponseOk(genre))
+		case errors.Is(err, model.ErrGenreNotExists):
+			writeError(w, model.ErrGenreNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка жанров
+// @Description	Возвращает все жанры, упорядоченные по названию
+// @Tags			genres
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Success		200	{object}	genreListResponse	"Информация о жанрах"
+// @Failure		500	{object}	genreListResponse	"Проблемы на стороне сервера"
+// @Failure		401	{object}	genreListResponse	"Ошибка авторизации"
+// @Failure		403	{object}	genreListResponse	"Ошибка авторизации"
+// @Router			/genres/list/ [get]
+func getGenresListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		genres, err := a.GetGenres(r.Context())
+
+		switch {
+		case err == nil:
+			writeResponse(w, genreListResponseOk(genres))
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
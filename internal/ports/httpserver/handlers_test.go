@@ -0,0 +1,74 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"movie-lib/internal/app"
+	"movie-lib/internal/auth"
+	"movie-lib/internal/model"
+	"movie-lib/internal/repo"
+	"movie-lib/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const adminUserId = 1
+
+// newTestApp returns the app with the in-memory repository
+func newTestApp(t *testing.T) app.App {
+	tokens, err := auth.NewIssuer(auth.Config{
+		SigningMethod:   auth.HS256,
+		HMACSecret:      []byte("test-secret-which-is-32-bytes-long"),
+		Issuer:          "movie-lib-test",
+		AccessTokenTTL:  time.Minute,
+		RefreshTokenTTL: time.Hour,
+	})
+	require.NoError(t, err)
+	return app.New(repo.NewMemory(), logger.DefaultLogger(os.Stdout), tokens)
+}
+
+// serveAs serves the GET request made by the user
+func serveAs(handler http.Handler, userId uint64, target string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r = r.WithContext(app.WithPrincipal(r.Context(), model.Principal{UserId: userId, Scope: model.WriteScope}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestListWithEmptyPattern(t *testing.T) {
+	a := newTestApp(t)
+	ctx := app.WithPrincipal(context.Background(), model.Principal{UserId: adminUserId, Scope: model.WriteScope})
+	actor, err := a.CreateActor(ctx, model.Actor{FirstName: "Pattern", Gender: model.Female})
+	require.NoError(t, err)
+	_, err = a.CreateMovie(ctx, model.Movie{
+		Title:       "Pattern",
+		ReleaseDate: time.Unix(1577826000, 0), // 2020-01-01
+		Rating:      5,
+		ActorsId:    []uint64{actor.Id},
+	})
+	require.NoError(t, err)
+
+	// an empty pattern lists all items instead of searching
+	for _, target := range []string{"/api/v1/movies/list/?pattern=", "/api/v1/movies/list/?pattern=%20"} {
+		w := serveAs(getMovieListHandler(a), adminUserId, target)
+		require.Equal(t, http.StatusOK, w.Code, target)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), target)
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"), target)
+		var resp movieListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1, target)
+	}
+	for _, target := range []string{"/api/v1/actors/list/?pattern=", "/api/v1/actors/list/?pattern=%20"} {
+		w := serveAs(getActorsListHandler(a), adminUserId, target)
+		require.Equal(t, http.StatusOK, w.Code, target)
+		var resp actorListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1, target)
+	}
+}
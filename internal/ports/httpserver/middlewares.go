@@ -1,11 +1,19 @@
 package httpserver
 
 import (
+	"errors"
 	"fmt"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
 	"movie-lib/pkg/logger"
 	"net/http"
+	"strings"
 )
 
+// apiKeyHeader is the header with the API key, it is accepted instead of the
+// access token in the Authorization header
+const apiKeyHeader = "X-API-Key"
+
 type ResponseWriterInterceptor struct {
 	http.ResponseWriter
 	StatusCode int
@@ -28,3 +36,59 @@ func logMiddleware(next http.Handler, log logger.Logger) http.Handler {
 		}
 	})
 }
+
+// authMiddleware authenticates the request by the API key from the header
+// "X-API-Key" or by the access token from the header
+// "Authorization: Bearer <token>" once and passes its principal to the next
+// handler in the request context, where the app reads it. Requests without
+// valid credentials are rejected with 401, read API keys are allowed only to
+// get data and are rejected with 403 otherwise.
+func authMiddleware(next http.Handler, a app.App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			principal model.Principal
+			err       error
+		)
+		if key := r.Header.Get(apiKeyHeader); key != "" {
+			principal, err = a.AuthenticateApiKey(r.Context(), key)
+		} else {
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+				return
+			}
+			principal, err = a.Authenticate(r.Context(), token)
+		}
+
+		switch {
+		case err == nil && principal.Scope == model.ReadScope &&
+			r.Method != http.MethodGet && r.Method != http.MethodHead:
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case err == nil:
+			next.ServeHTTP(w, r.WithContext(app.WithPrincipal(r.Context(), principal)))
+		case errors.Is(err, model.ErrInvalidToken):
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, model.ErrInvalidToken, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	})
+}
+
+// sessionMiddleware rejects requests authenticated by API keys, so API keys
+// can not be used to manage credentials of the user
+func sessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := app.PrincipalFromContext(r.Context())
+		switch {
+		case !ok:
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case principal.SessionId == 0:
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
@@ -1,10 +1,8 @@
 package httpserver
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"movie-lib/internal/app"
 	"movie-lib/internal/model"
@@ -17,58 +15,62 @@ import (
 // @Description	Добавляет новый фильм
 // @Tags			movies
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
 // @Param			input	body		createMovieData	true	"Информация о новом фильме"
 // @Success		200		{object}	movieResponse	"Информация о фильме"
 // @Failure		400		{object}	movieResponse	"Неверный формат входных данных"
-// @Failure		404		{object}	movieResponse	"Актёра из списка не существует"
+// @Failure		404		{object}	movieResponse	"Актёра, жанра или члена съёмочной группы из списка не существует"
+// @Failure		409		{object}	movieResponse	"Конфликт с существующими данными"
 // @Failure		500		{object}	movieResponse	"Проблемы на стороне сервера"
 // @Failure		401		{object}	movieResponse	"Ошибка авторизации"
 // @Failure		403		{object}	movieResponse	"Ошибка авторизации"
 // @Router			/movies/ [post]
-func createMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func createMovieHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 		var data createMovieData
 		if err = json.Unmarshal(body, &data); err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		movie, err := a.CreateMovie(ctx, userId, model.Movie{
+		movie, err := a.CreateMovie(r.Context(), model.Movie{
 			Title:       data.Title,
 			Description: data.Description,
 			ReleaseDate: time.Unix(data.ReleaseDate, 0),
 			Rating:      data.Rating,
-			ActorsId:    data.ActorsId,
+			Cast:        castCreditsFromData(data.Cast),
+			GenresId:    data.GenresId,
+			CrewCredits: crewCreditsFromData(data.Crew),
 		})
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprintf(w, movieResponseOk(movie))
+			writeResponse(w, movieResponseOk(movie))
 		case errors.Is(err, model.ErrValidationError):
-			http.Error(w, errorResponse(model.ErrValidationError), http.StatusBadRequest)
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
 		case errors.Is(err, model.ErrActorNotExists):
-			http.Error(w, errorResponse(model.ErrActorNotExists), http.StatusNotFound)
+			writeError(w, model.ErrActorNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrGenreNotExists):
+			writeError(w, model.ErrGenreNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPersonNotExists):
+			writeError(w, model.ErrPersonNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
 		case errors.Is(err, model.ErrPermissionDenied):
-			http.Error(w, errorResponse(model.ErrPermissionDenied), http.StatusForbidden)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -77,66 +79,70 @@ func createMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Description	Обновляет поля фильма по id
 // @Tags			movies
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
 // @Param			movie_id	query		string			true	"id фильма"
 // @Param			input		body		updateMovieData	true	"Новые поля"
 // @Success		200			{object}	movieResponse	"Информация о фильме"
 // @Failure		400			{object}	movieResponse	"Неверный формат входных данных"
-// @Failure		404			{object}	movieResponse	"Фильма либо актёра из списка не существует"
+// @Failure		404			{object}	movieResponse	"Фильма либо актёра, жанра или члена съёмочной группы из списка не существует"
+// @Failure		409			{object}	movieResponse	"Конфликт с существующими данными"
 // @Failure		500			{object}	movieResponse	"Проблемы на стороне сервера"
 // @Failure		401			{object}	movieResponse	"Ошибка авторизации"
 // @Failure		403			{object}	movieResponse	"Ошибка авторизации"
 // @Router			/movies/ [put]
-func updateMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func updateMovieHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
 		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 		var data updateMovieData
 		if err = json.Unmarshal(body, &data); err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		movie, err := a.UpdateMovie(ctx, userId, movieId, model.UpdateMovie{
+		movie, err := a.UpdateMovie(r.Context(), movieId, model.UpdateMovie{
 			Title:       data.Title,
 			Description: data.Description,
 			ReleaseDate: time.Unix(data.ReleaseDate, 0),
 			Rating:      data.Rating,
-			Actors:      data.ActorsId,
+			Cast:        castCreditsFromData(data.Cast),
+			Genres:      data.GenresId,
+			Crew:        crewCreditsFromData(data.Crew),
 		})
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprintf(w, movieResponseOk(movie))
+			writeResponse(w, movieResponseOk(movie))
 		case errors.Is(err, model.ErrMovieNotExists):
-			http.Error(w, errorResponse(model.ErrMovieNotExists), http.StatusNotFound)
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
 		case errors.Is(err, model.ErrActorNotExists):
-			http.Error(w, errorResponse(model.ErrActorNotExists), http.StatusNotFound)
+			writeError(w, model.ErrActorNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrGenreNotExists):
+			writeError(w, model.ErrGenreNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPersonNotExists):
+			writeError(w, model.ErrPersonNotExists, http.StatusNotFound)
 		case errors.Is(err, model.ErrValidationError):
-			http.Error(w, errorResponse(model.ErrValidationError), http.StatusBadRequest)
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
 		case errors.Is(err, model.ErrPermissionDenied):
-			http.Error(w, errorResponse(model.ErrPermissionDenied), http.StatusForbidden)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -145,6 +151,7 @@ func updateMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Description	Удаление фильма по id
 // @Tags			movies
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
 // @Param			movie_id	query		string			true	"id фильма"
@@ -155,95 +162,96 @@ func updateMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Failure		401			{object}	movieResponse	"Ошибка авторизации"
 // @Failure		403			{object}	movieResponse	"Ошибка авторизации"
 // @Router			/movies/ [delete]
-func deleteMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func deleteMovieHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
 		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
-		err = a.DeleteMovie(ctx, userId, movieId)
+		err = a.DeleteMovie(r.Context(), movieId)
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprintf(w, errorResponse(nil))
+			writeResponse(w, errorResponse(nil))
 		case errors.Is(err, model.ErrMovieNotExists):
-			http.Error(w, errorResponse(model.ErrMovieNotExists), http.StatusNotFound)
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
 		case errors.Is(err, model.ErrPermissionDenied):
-			http.Error(w, errorResponse(model.ErrPermissionDenied), http.StatusForbidden)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
 
 // @Summary		Получение списка фильмов
-// @Description	Возвращает список фильмов
+// @Description	Возвращает страницу списка фильмов, удовлетворяющих фильтрам, и общее количество таких фильмов
 // @Tags			movies
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
-// @Param			pattern	query		string				false	"Поиск по названию фильма/фамилии/имени актёра"
-// @Param			sort_by	query		string				false	"Параметр для сортировки. Поддерживаемые параметры: title, rating, release_date"
-// @Success		200		{object}	movieListResponse	"Информация о фильмах"
-// @Failure		400		{object}	movieListResponse	"Неверный формат входных данных"
-// @Failure		500		{object}	movieListResponse	"Проблемы на стороне сервера"
-// @Failure		401		{object}	movieListResponse	"Ошибка авторизации"
-// @Failure		403		{object}	movieListResponse	"Ошибка авторизации"
+// @Param			pattern				query		string				false	"Полнотекстовый поиск по названию, описанию фильма и именам актёров. Поддерживаются or и исключение слов через минус"
+// @Param			sort_by				query		string				false	"Параметры сортировки через запятую, минус перед параметром означает сортировку по убыванию, например -rating,title. Поддерживаемые параметры: title, rating, release_date, а при поиске по pattern также relevance. По умолчанию -rating, при поиске -relevance"
+// @Param			release_date_from	query		int					false	"Минимальная дата выхода (timestamp)"
+// @Param			release_date_to		query		int					false	"Максимальная дата выхода (timestamp)"
+// @Param			rating_from			query		number				false	"Минимальный рейтинг"
+// @Param			rating_to			query		number				false	"Максимальный рейтинг"
+// @Param			actors				query		string				false	"id актёров через запятую"
+// @Param			actors_match		query		string				false	"Фильмы с любым (any, по умолчанию) или со всеми (all) актёрами из actors"
+// @Param			actor_gender		query		string				false	"Фильмы, в которых играл актёр указанного пола (male/female)"
+// @Param			genres				query		string				false	"id жанров через запятую"
+// @Param			genres_match		query		string				false	"Фильмы с любым (any, по умолчанию) или со всеми (all) жанрами из genres"
+// @Param			watchlist			query		bool				false	"Фильмы, которые есть (true) или которых нет (false) в списке «Буду смотреть» пользователя"
+// @Param			watched				query		bool				false	"Фильмы, которые пользователь посмотрел (true) или не посмотрел (false)"
+// @Param			limit				query		int					false	"Количество фильмов на странице, по умолчанию 50, не больше 500"
+// @Param			cursor				query		string				false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200					{object}	movieListResponse	"Информация о фильмах"
+// @Failure		400					{object}	movieListResponse	"Неверный формат входных данных"
+// @Failure		500					{object}	movieListResponse	"Проблемы на стороне сервера"
+// @Failure		401					{object}	movieListResponse	"Ошибка авторизации"
+// @Failure		403					{object}	movieListResponse	"Ошибка авторизации"
 // @Router			/movies/list/ [get]
-func getMovieListHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func getMovieListHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
+		page, err := parsePage(r)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
+		var filter model.MovieFilter
+		filter, err = parseMovieFilter(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		sortBy := model.ParseSort(r.URL.Query().Get("sort_by"))
 
-		if r.URL.Query().Has("pattern") {
-			pattern := r.URL.Query().Get("pattern")
-
-			var movies []model.Movie
-			movies, err = a.SearchMovies(ctx, userId, pattern)
-
-			switch {
-			case err == nil:
-				w.WriteHeader(http.StatusOK)
-				_, _ = fmt.Fprintf(w, movieListResponseOk(movies))
-			case errors.Is(err, model.ErrUserNotExists):
-				http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
-			case errors.Is(err, model.ErrDatabaseError):
-				http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
-			default:
-				http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
-			}
+		var movies model.MovieList
+		if pattern := parsePattern(r); pattern != "" {
+			movies, err = a.SearchMovies(r.Context(), pattern, filter, sortBy, page)
 		} else {
-			sortParam := r.URL.Query().Get("sort_by")
-
-			var movies []model.Movie
-			movies, err = a.GetMovies(ctx, userId, model.SortParam(sortParam))
+			movies, err = a.GetMovies(r.Context(), filter, sortBy, page)
+		}
 
-			switch {
-			case err == nil:
-				w.WriteHeader(http.StatusOK)
-				_, _ = fmt.Fprintf(w, movieListResponseOk(movies))
-			case errors.Is(err, model.ErrUserNotExists):
-				http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
-			case errors.Is(err, model.ErrDatabaseError):
-				http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
-			default:
-				http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
-			}
+		switch {
+		case err == nil:
+			writeResponse(w, movieListResponseOk(movies))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrInvalidCursor):
+			writeError(w, model.ErrInvalidCursor, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -252,6 +260,7 @@ func getMovieListHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Description	Возвращает фильм с указанным id
 // @Tags			movies
 // @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
 // @Accept			json
 // @Produce		json
 // @Param			movie_id	query		string			true	"id фильма"
@@ -262,36 +271,28 @@ func getMovieListHandler(ctx context.Context, a app.App) http.HandlerFunc {
 // @Failure		401			{object}	movieResponse	"Ошибка авторизации"
 // @Failure		403			{object}	movieResponse	"Ошибка авторизации"
 // @Router			/movies/ [get]
-func getMovieHandler(ctx context.Context, a app.App) http.HandlerFunc {
+func getMovieHandler(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userId, err := strconv.ParseUint(r.Header.Get("Authorization"), 10, 64)
-		if err != nil {
-			http.Error(w, errorResponse(model.ErrUnauthorized), http.StatusUnauthorized)
-			return
-		}
-
-		var movieId uint64
-		movieId, err = strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
 		if err != nil {
-			http.Error(w, errorResponse(model.ErrInvalidInput), http.StatusBadRequest)
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
 			return
 		}
 
 		var movie model.Movie
-		movie, err = a.GetMovie(ctx, userId, movieId)
+		movie, err = a.GetMovie(r.Context(), movieId)
 
 		switch {
 		case err == nil:
-			w.WriteHeader(http.StatusOK)
-			_, _ = fmt.Fprintf(w, movieResponseOk(movie))
+			writeResponse(w, movieResponseOk(movie))
 		case errors.Is(err, model.ErrMovieNotExists):
-			http.Error(w, errorResponse(model.ErrMovieNotExists), http.StatusNotFound)
-		case errors.Is(err, model.ErrUserNotExists):
-			http.Error(w, errorResponse(model.ErrUserNotExists), http.StatusForbidden)
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
 		case errors.Is(err, model.ErrDatabaseError):
-			http.Error(w, errorResponse(model.ErrDatabaseError), http.StatusInternalServerError)
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
 		default:
-			http.Error(w, errorResponse(model.ErrServiceError), http.StatusInternalServerError)
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
 		}
 	}
 }
@@ -0,0 +1,165 @@
+package httpserver
+
+import (
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsePage reads optional limit and cursor query params
+func parsePage(r *http.Request) (model.Page, error) {
+	var page model.Page
+	if r.URL.Query().Has("limit") {
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			return model.Page{}, model.ErrInvalidInput
+		}
+		page.Limit = limit
+	}
+	page.Cursor = r.URL.Query().Get("cursor")
+	return page, nil
+}
+
+// parsePattern reads the optional search pattern, an empty pattern means no
+// search, so all items are listed
+func parsePattern(r *http.Request) string {
+	return strings.TrimSpace(r.URL.Query().Get("pattern"))
+}
+
+// parseMovieFilter reads optional query params of the movie filter:
+// release_date_from, release_date_to (timestamps), rating_from, rating_to,
+// actors (comma separated ids), actors_match (any/all), actor_gender, genres
+// (comma separated ids), genres_match (any/all), watchlist and watched
+// (booleans)
+func parseMovieFilter(r *http.Request) (model.MovieFilter, error) {
+	query := r.URL.Query()
+	var filter model.MovieFilter
+
+	for param, dst := range map[string]**time.Time{
+		"release_date_from": &filter.ReleaseDateFrom,
+		"release_date_to":   &filter.ReleaseDateTo,
+	} {
+		if !query.Has(param) {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(query.Get(param), 10, 64)
+		if err != nil {
+			return model.MovieFilter{}, model.ErrInvalidInput
+		}
+		date := time.Unix(timestamp, 0).UTC()
+		*dst = &date
+	}
+
+	for param, dst := range map[string]**float64{
+		"rating_from": &filter.RatingFrom,
+		"rating_to":   &filter.RatingTo,
+	} {
+		if !query.Has(param) {
+			continue
+		}
+		rating, err := strconv.ParseFloat(query.Get(param), 64)
+		if err != nil {
+			return model.MovieFilter{}, model.ErrInvalidInput
+		}
+		*dst = &rating
+	}
+
+	var err error
+	if filter.ActorsId, err = parseIds(query.Get("actors")); err != nil {
+		return model.MovieFilter{}, err
+	}
+	filter.ActorsMatch = model.Match(query.Get("actors_match"))
+	filter.ActorGender = model.Gender(query.Get("actor_gender"))
+	if filter.GenresId, err = parseIds(query.Get("genres")); err != nil {
+		return model.MovieFilter{}, err
+	}
+	filter.GenresMatch = model.Match(query.Get("genres_match"))
+
+	for param, dst := range map[string]**bool{
+		"watchlist": &filter.InWatchlist,
+		"watched":   &filter.Watched,
+	} {
+		if !query.Has(param) {
+			continue
+		}
+		value, err := strconv.ParseBool(query.Get(param))
+		if err != nil {
+			return model.MovieFilter{}, model.ErrInvalidInput
+		}
+		*dst = &value
+	}
+	return filter, nil
+}
+
+// parseIds parses comma separated ids, empty string means no ids
+func parseIds(s string) ([]uint64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ids := make([]uint64, 0)
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, model.ErrInvalidInput
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseActorFilter reads optional query params of the actor filter: gender
+// and movie_id
+func parseActorFilter(r *http.Request) (model.ActorFilter, error) {
+	query := r.URL.Query()
+	var filter model.ActorFilter
+
+	if query.Has("movie_id") {
+		movieId, err := strconv.ParseUint(query.Get("movie_id"), 10, 64)
+		if err != nil {
+			return model.ActorFilter{}, model.ErrInvalidInput
+		}
+		filter.MovieId = movieId
+	}
+	filter.Gender = model.Gender(query.Get("gender"))
+	return filter, nil
+}
+
+// parseReviewFilter reads optional query params of the review filter:
+// movie_id and user_id
+func parseReviewFilter(r *http.Request) (model.ReviewFilter, error) {
+	query := r.URL.Query()
+	var filter model.ReviewFilter
+
+	for param, dst := range map[string]*uint64{
+		"movie_id": &filter.MovieId,
+		"user_id":  &filter.UserId,
+	} {
+		if !query.Has(param) {
+			continue
+		}
+		id, err := strconv.ParseUint(query.Get(param), 10, 64)
+		if err != nil {
+			return model.ReviewFilter{}, model.ErrInvalidInput
+		}
+		*dst = id
+	}
+	return filter, nil
+}
+
+// parseCollectionFilter reads optional query param of the collection filter:
+// owner_id
+func parseCollectionFilter(r *http.Request) (model.CollectionFilter, error) {
+	query := r.URL.Query()
+	var filter model.CollectionFilter
+
+	if query.Has("owner_id") {
+		ownerId, err := strconv.ParseUint(query.Get("owner_id"), 10, 64)
+		if err != nil {
+			return model.CollectionFilter{}, model.ErrInvalidInput
+		}
+		filter.OwnerId = ownerId
+	}
+	return filter, nil
+}
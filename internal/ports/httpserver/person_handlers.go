@@ -0,0 +1,49 @@
+package httpserver
+
+import (
+	"errors"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Фильмография персоны
+// @Description	Возвращает актёра или члена съёмочной группы и все его фильмы, сгруппированные по ролям
+// @Tags			persons
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			person_id	query		string				true	"id персоны (совпадает с id актёра)"
+// @Success		200			{object}	filmographyResponse	"Фильмография"
+// @Failure		404			{object}	filmographyResponse	"Персоны не существует"
+// @Failure		400			{object}	filmographyResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	filmographyResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	filmographyResponse	"Ошибка авторизации"
+// @Failure		403			{object}	filmographyResponse	"Ошибка авторизации"
+// @Router			/persons/filmography/ [get]
+func getFilmographyHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		personId, err := strconv.ParseUint(r.URL.Query().Get("person_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var filmography model.Filmography
+		filmography, err = a.GetFilmography(r.Context(), personId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, filmographyResponseOk(filmography))
+		case errors.Is(err, model.ErrPersonNotExists):
+			writeError(w, model.ErrPersonNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
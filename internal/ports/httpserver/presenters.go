@@ -1,31 +1,210 @@
 package httpserver
 
-import "movie-lib/internal/model"
+import (
+	"bytes"
+	"encoding/json"
+	"movie-lib/internal/model"
+)
 
 type createActorData struct {
 	FirstName    string `json:"first_name"`
 	SecondName   string `json:"second_name"`
 	model.Gender `json:"gender"`
+	// Персона только член съёмочной группы: она не попадает в списки, поиск и
+	// подсказки актёров, пока её не добавят в актёрский состав фильма
+	CrewOnly bool `json:"crew_only"`
 }
 
 type updateActorData struct {
 	FirstName    string `json:"first_name"`
 	SecondName   string `json:"second_name"`
 	model.Gender `json:"gender"`
+	// Персона только член съёмочной группы
+	CrewOnly bool `json:"crew_only"`
 }
 
 type createMovieData struct {
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	ReleaseDate int64    `json:"release_date"`
-	Rating      float64  `json:"rating"`
-	ActorsId    []uint64 `json:"actors"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ReleaseDate int64   `json:"release_date"`
+	Rating      float64 `json:"rating"`
+	// Актёры фильма: объекты с id актёра, персонажами и позицией в титрах либо просто id актёров
+	Cast     []castCreditData `json:"actors"`
+	GenresId []uint64         `json:"genres"`
+	Crew     []crewCreditData `json:"crew"`
 }
 
 type updateMovieData struct {
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	ReleaseDate int64    `json:"release_date"`
-	Rating      float64  `json:"rating"`
-	ActorsId    []uint64 `json:"actors"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ReleaseDate int64   `json:"release_date"`
+	Rating      float64 `json:"rating"`
+	// Актёры фильма: объекты с id актёра, персонажами и позицией в титрах либо просто id актёров
+	Cast     []castCreditData `json:"actors"`
+	GenresId []uint64         `json:"genres"`
+	Crew     []crewCreditData `json:"crew"`
+}
+
+type createGenreData struct {
+	Name string `json:"name"`
+}
+
+type updateGenreData struct {
+	Name string `json:"name"`
+}
+
+type createReviewData struct {
+	MovieId uint64 `json:"movie_id"`
+	// Оценка от 1 до 10
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+type updateReviewData struct {
+	// Оценка от 1 до 10
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+type createCollectionData struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Видимость: private (по умолчанию) или public
+	Visibility model.Visibility `json:"visibility"`
+	// id пользователей, которым подборка доступна для чтения
+	SharedWith []uint64 `json:"shared_with"`
+}
+
+type updateCollectionData struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Видимость: private (по умолчанию) или public
+	Visibility model.Visibility `json:"visibility"`
+	// id пользователей, которым подборка доступна для чтения, заменяют прежний список
+	SharedWith []uint64 `json:"shared_with"`
+}
+
+type addCollectionEntryData struct {
+	MovieId uint64 `json:"movie_id"`
+	Note    string `json:"note"`
+}
+
+type updateCollectionEntryData struct {
+	Note string `json:"note"`
+}
+
+type reorderCollectionData struct {
+	// id всех фильмов подборки в новом порядке
+	Movies []uint64 `json:"movies"`
+}
+
+type createUserData struct {
+	// Имя пользователя от 3 до 50 букв, цифр и символов «_», «.» и «-», уникальное без учёта регистра
+	Username string `json:"username"`
+	// Необязательный адрес электронной почты
+	Email string `json:"email"`
+	// Роль: regular (по умолчанию), admin или другая роль из списка ролей
+	Role     model.Role `json:"role"`
+	Disabled bool       `json:"disabled"`
+	// Пароль от 8 до 72 байт
+	Password string `json:"password"`
+}
+
+type updateUserData struct {
+	// Имя пользователя от 3 до 50 букв, цифр и символов «_», «.» и «-», уникальное без учёта регистра
+	Username string `json:"username"`
+	// Необязательный адрес электронной почты
+	Email string `json:"email"`
+	// Роль: regular, admin или другая роль из списка ролей
+	Role model.Role `json:"role"`
+	// Заблокированный пользователь не может выполнять никакие операции
+	Disabled bool `json:"disabled"`
+}
+
+type setPasswordData struct {
+	// Новый пароль от 8 до 72 байт
+	Password string `json:"password"`
+	// Текущий пароль, обязателен при смене своего пароля
+	CurrentPassword string `json:"current_password"`
+}
+
+type loginData struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type refreshData struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type createApiKeyData struct {
+	// Название ключа от 1 до 100 символов
+	Name string `json:"name"`
+	// Область действия: read (по умолчанию, только получение данных) или write
+	Scope model.ApiKeyScope `json:"scope"`
+	// Необязательное время истечения (timestamp), ключ без него не истекает
+	ExpiresAt *int64 `json:"expires_at"`
+}
+
+type createRoleData struct {
+	// Название роли от 1 до 20 строчных латинских букв, цифр и символов «_» и «-»
+	Name model.Role `json:"name"`
+	// Разрешения роли, например movies:write или reviews:moderate
+	Permissions []model.Permission `json:"permissions"`
+}
+
+type updateRoleData struct {
+	// Новые разрешения роли, заменяют прежние
+	Permissions []model.Permission `json:"permissions"`
+}
+
+type crewCreditData struct {
+	PersonId uint64 `json:"person_id"`
+	// Роль: director, writer, composer или producer
+	Role model.CreditRole `json:"role"`
+}
+
+func crewCreditsFromData(data []crewCreditData) []model.CrewCredit {
+	crew := make([]model.CrewCredit, 0, len(data))
+	for _, credit := range data {
+		crew = append(crew, model.CrewCredit{
+			PersonId: credit.PersonId,
+			Role:     credit.Role,
+		})
+	}
+	return crew
+}
+
+// castCreditData is an actor of the cast, it is decoded either from the
+// object or from the bare id of the actor which was the only supported form
+// before characters and billing were added
+type castCreditData struct {
+	ActorId uint64 `json:"actor_id"`
+	// Имена персонажей актёра
+	Characters []string `json:"characters"`
+	// Позиция актёра в титрах, по умолчанию позиция в списке
+	Billing int `json:"billing"`
+}
+
+func (c *castCreditData) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		*c = castCreditData{}
+		return json.Unmarshal(data, &c.ActorId)
+	}
+	// the alias type has no UnmarshalJSON method, so the object is decoded
+	// without recursion
+	type credit castCreditData
+	return json.Unmarshal(data, (*credit)(c))
+}
+
+func castCreditsFromData(data []castCreditData) []model.CastCredit {
+	cast := make([]model.CastCredit, 0, len(data))
+	for _, credit := range data {
+		cast = append(cast, model.CastCredit{
+			ActorId:    credit.ActorId,
+			Characters: credit.Characters,
+			Billing:    credit.Billing,
+		})
+	}
+	return cast
 }
@@ -2,7 +2,10 @@ package httpserver
 
 import (
 	"encoding/json"
+	"fmt"
 	"movie-lib/internal/model"
+	"net/http"
+	"time"
 )
 
 func errorResponse(err error) string {
@@ -15,6 +18,30 @@ func errorResponse(err error) string {
 	return string(data)
 }
 
+// writeJSON writes the JSON body with the status code and marks it as JSON,
+// so clients and browsers do not sniff its type
+func writeJSON(w http.ResponseWriter, body string, code int) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintln(w, body)
+}
+
+// writeResponse writes the successful response with the status 200
+func writeResponse(w http.ResponseWriter, body string) {
+	writeJSON(w, body, http.StatusOK)
+}
+
+// writeError writes the error response with the status code, unlike
+// http.Error it marks the body as JSON. Responses with the status 401 tell
+// the client to authenticate with the bearer token.
+func writeError(w http.ResponseWriter, err error, code int) {
+	if code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	writeJSON(w, errorResponse(err), code)
+}
+
 func actorResponseOk(actor model.Actor) string {
 	data := actorToActorData(actor)
 	resp := actorResponse{
@@ -35,21 +62,25 @@ func movieResponseOk(movie model.Movie) string {
 	return string(body)
 }
 
-func actorListResponseOk(actors []model.Actor) string {
-	data := actorsToActorListData(actors)
+func actorListResponseOk(actors model.ActorList) string {
+	data := actorsToActorListData(actors.Actors)
 	resp := actorListResponse{
-		Data: data,
-		Err:  nil,
+		Data:       data,
+		NextCursor: actors.NextCursor,
+		Total:      actors.Total,
+		Err:        nil,
 	}
 	body, _ := json.Marshal(resp)
 	return string(body)
 }
 
-func movieListResponseOk(movies []model.Movie) string {
-	data := moviesToMovieListData(movies)
+func movieListResponseOk(movies model.MovieList) string {
+	data := moviesToMovieListData(movies.Movies)
 	resp := movieListResponse{
-		Data: data,
-		Err:  nil,
+		Data:       data,
+		NextCursor: movies.NextCursor,
+		Total:      movies.Total,
+		Err:        nil,
 	}
 	body, _ := json.Marshal(resp)
 	return string(body)
@@ -61,6 +92,8 @@ func actorToActorData(actor model.Actor) actorData {
 		FirstName:  actor.FirstName,
 		SecondName: actor.SecondName,
 		Gender:     actor.Gender,
+		CrewOnly:   actor.CrewOnly,
+		Relevance:  actor.Relevance,
 	}
 
 	data.Movies = make([]movieData, 0, len(actor.Movies))
@@ -83,6 +116,21 @@ func movieToMovieData(movie model.Movie) movieData {
 		Description: movie.Description,
 		ReleaseDate: movie.ReleaseDate.UTC().Unix(),
 		Rating:      movie.Rating,
+		Relevance:   movie.Relevance,
+		Snippet:     movie.Snippet,
+
+		CommunityRating: movie.CommunityRating,
+		VotesCount:      movie.VotesCount,
+	}
+
+	if movie.Status != nil {
+		data.Status = &movieStatusData{
+			InWatchlist: movie.Status.InWatchlist,
+			Watched:     movie.Status.Watched,
+		}
+		if movie.Status.Watched {
+			data.Status.WatchedAt = movie.Status.WatchedAt.UTC().Unix()
+		}
 	}
 
 	data.Actors = make([]actorData, 0, len(movie.Actors))
@@ -92,17 +140,52 @@ func movieToMovieData(movie model.Movie) movieData {
 			FirstName:  actor.FirstName,
 			SecondName: actor.SecondName,
 			Gender:     actor.Gender,
+			Characters: actor.Characters,
+			Billing:    actor.Billing,
+		})
+	}
+
+	data.Genres = make([]genreData, 0, len(movie.Genres))
+	for _, genre := range movie.Genres {
+		data.Genres = append(data.Genres, genreToGenreData(genre))
+	}
+
+	data.Crew = make([]crewMemberData, 0, len(movie.Crew))
+	for _, member := range movie.Crew {
+		data.Crew = append(data.Crew, crewMemberData{
+			Id:         member.Id,
+			FirstName:  member.FirstName,
+			SecondName: member.SecondName,
+			Gender:     member.Gender,
+			Role:       member.Role,
 		})
 	}
 	return data
 }
 
+type crewMemberData struct {
+	Id           uint64 `json:"id"`
+	FirstName    string `json:"first_name"`
+	SecondName   string `json:"second_name"`
+	model.Gender `json:"gender"`
+	// Роль: director, writer, composer или producer
+	Role model.CreditRole `json:"role"`
+}
+
 type actorData struct {
 	Id           uint64 `json:"id"`
 	FirstName    string `json:"first_name"`
 	SecondName   string `json:"second_name"`
 	model.Gender `json:"gender"`
 	Movies       []movieData `json:"movies,omitempty"`
+	// Персона только член съёмочной группы, не актёр
+	CrewOnly bool `json:"crew_only,omitempty"`
+	// Похожесть имени актёра на запрос, только при нечётком поиске
+	Relevance float64 `json:"relevance,omitempty"`
+	// Персонажи актёра в фильме, только в составе фильма
+	Characters []string `json:"characters,omitempty"`
+	// Позиция актёра в титрах фильма, только в составе фильма
+	Billing int `json:"billing,omitempty"`
 }
 
 type actorResponse struct {
@@ -111,12 +194,33 @@ type actorResponse struct {
 }
 
 type movieData struct {
-	Id          uint64      `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	ReleaseDate int64       `json:"release_date"`
-	Rating      float64     `json:"rating"`
-	Actors      []actorData `json:"actors,omitempty"`
+	Id          uint64  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ReleaseDate int64   `json:"release_date"`
+	Rating      float64 `json:"rating"`
+	// Средняя оценка пользователей, отсутствует, если оценок нет
+	CommunityRating float64 `json:"community_rating,omitempty"`
+	// Количество оценок пользователей
+	VotesCount uint64      `json:"votes_count,omitempty"`
+	Actors     []actorData `json:"actors,omitempty"`
+	Genres     []genreData `json:"genres,omitempty"`
+	// Съёмочная группа, упорядоченная по ролям: director, writer, composer, producer
+	Crew []crewMemberData `json:"crew,omitempty"`
+	// Релевантность фильма запросу, только при поиске по pattern и нечётком поиске
+	Relevance float64 `json:"relevance,omitempty"`
+	// Фрагмент описания с найденными словами в <b></b>, только при поиске по pattern
+	Snippet string `json:"snippet,omitempty"`
+	// Статус фильма в списках пользователя, выполнившего запрос
+	Status *movieStatusData `json:"status,omitempty"`
+}
+
+type movieStatusData struct {
+	// Фильм есть в списке «Буду смотреть»
+	InWatchlist bool `json:"in_watchlist"`
+	Watched     bool `json:"watched"`
+	// Дата просмотра (timestamp), только для просмотренных фильмов
+	WatchedAt int64 `json:"watched_at,omitempty"`
 }
 
 type movieResponse struct {
@@ -133,8 +237,10 @@ func actorsToActorListData(actors []model.Actor) []actorData {
 }
 
 type actorListResponse struct {
-	Data []actorData `json:"data"`
-	Err  *string     `json:"error"`
+	Data       []actorData `json:"data"`
+	NextCursor string      `json:"next_cursor"`
+	Total      uint64      `json:"total"`
+	Err        *string     `json:"error"`
 }
 
 func moviesToMovieListData(movies []model.Movie) []movieData {
@@ -146,6 +252,531 @@ func moviesToMovieListData(movies []model.Movie) []movieData {
 }
 
 type movieListResponse struct {
-	Data []movieData `json:"data"`
+	Data       []movieData `json:"data"`
+	NextCursor string      `json:"next_cursor"`
+	Total      uint64      `json:"total"`
+	Err        *string     `json:"error"`
+}
+
+func filmographyResponseOk(filmography model.Filmography) string {
+	data := filmographyData{
+		Person: actorData{
+			Id:         filmography.Person.Id,
+			FirstName:  filmography.Person.FirstName,
+			SecondName: filmography.Person.SecondName,
+			Gender:     filmography.Person.Gender,
+			CrewOnly:   filmography.Person.CrewOnly,
+		},
+		Credits: make(map[model.CreditRole][]movieData, len(filmography.Credits)),
+	}
+	for role, movies := range filmography.Credits {
+		data.Credits[role] = moviesToMovieListData(movies)
+	}
+	resp := filmographyResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+type filmographyData struct {
+	Person actorData `json:"person"`
+	// Фильмы персоны по ролям (actor, director, writer, composer, producer), упорядоченные по дате выхода
+	Credits map[model.CreditRole][]movieData `json:"credits"`
+}
+
+type filmographyResponse struct {
+	Data *filmographyData `json:"data"`
+	Err  *string          `json:"error"`
+}
+
+func genreResponseOk(genre model.Genre) string {
+	data := genreToGenreData(genre)
+	resp := genreResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func genreListResponseOk(genres []model.Genre) string {
+	data := make([]genreData, 0, len(genres))
+	for _, genre := range genres {
+		data = append(data, genreToGenreData(genre))
+	}
+	resp := genreListResponse{
+		Data: data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func genreToGenreData(genre model.Genre) genreData {
+	return genreData{
+		Id:   genre.Id,
+		Name: genre.Name,
+	}
+}
+
+type genreData struct {
+	Id   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+type genreResponse struct {
+	Data *genreData `json:"data"`
+	Err  *string    `json:"error"`
+}
+
+type genreListResponse struct {
+	Data []genreData `json:"data"`
+	Err  *string     `json:"error"`
+}
+
+func reviewResponseOk(review model.Review) string {
+	data := reviewToReviewData(review)
+	resp := reviewResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func reviewListResponseOk(reviews model.ReviewList) string {
+	data := make([]reviewData, 0, len(reviews.Reviews))
+	for _, review := range reviews.Reviews {
+		data = append(data, reviewToReviewData(review))
+	}
+	resp := reviewListResponse{
+		Data:       data,
+		NextCursor: reviews.NextCursor,
+		Total:      reviews.Total,
+		Err:        nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func reviewToReviewData(review model.Review) reviewData {
+	return reviewData{
+		Id:        review.Id,
+		MovieId:   review.MovieId,
+		UserId:    review.UserId,
+		Rating:    review.Rating,
+		Text:      review.Text,
+		CreatedAt: review.CreatedAt.Unix(),
+		UpdatedAt: review.UpdatedAt.Unix(),
+	}
+}
+
+type reviewData struct {
+	Id      uint64 `json:"id"`
+	MovieId uint64 `json:"movie_id"`
+	UserId  uint64 `json:"user_id"`
+	// Оценка от 1 до 10
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+	// Время создания и последнего изменения отзыва
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+type reviewResponse struct {
+	Data *reviewData `json:"data"`
 	Err  *string     `json:"error"`
 }
+
+type reviewListResponse struct {
+	Data       []reviewData `json:"data"`
+	NextCursor string       `json:"next_cursor"`
+	Total      uint64       `json:"total"`
+	Err        *string      `json:"error"`
+}
+
+func collectionResponseOk(collection model.Collection) string {
+	data := collectionToCollectionData(collection)
+	resp := collectionResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func collectionListResponseOk(collections model.CollectionList) string {
+	data := make([]collectionData, 0, len(collections.Collections))
+	for _, collection := range collections.Collections {
+		data = append(data, collectionToCollectionData(collection))
+	}
+	resp := collectionListResponse{
+		Data:       data,
+		NextCursor: collections.NextCursor,
+		Total:      collections.Total,
+		Err:        nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func collectionToCollectionData(collection model.Collection) collectionData {
+	data := collectionData{
+		Id:          collection.Id,
+		OwnerId:     collection.OwnerId,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Visibility:  collection.Visibility,
+		CreatedAt:   collection.CreatedAt.Unix(),
+		SharedWith:  collection.SharedWith,
+	}
+	if collection.Entries != nil {
+		data.Entries = make([]collectionEntryData, 0, len(collection.Entries))
+		for _, entry := range collection.Entries {
+			data.Entries = append(data.Entries, collectionEntryData{
+				Movie: movieToMovieData(entry.Movie),
+				Note:  entry.Note,
+			})
+		}
+	}
+	return data
+}
+
+type collectionData struct {
+	Id      uint64 `json:"id"`
+	OwnerId uint64 `json:"owner_id"`
+	Name    string `json:"name"`
+	// Описание подборки
+	Description string `json:"description"`
+	// Видимость: private или public
+	Visibility model.Visibility `json:"visibility"`
+	CreatedAt  int64            `json:"created_at"`
+	// id пользователей, которым подборка доступна для чтения, возвращаются только владельцу
+	SharedWith []uint64 `json:"shared_with,omitempty"`
+	// Фильмы подборки по порядку, возвращаются только при получении одной подборки
+	Entries []collectionEntryData `json:"entries,omitempty"`
+}
+
+type collectionEntryData struct {
+	Movie movieData `json:"movie"`
+	// Заметка владельца о фильме
+	Note string `json:"note"`
+}
+
+type collectionResponse struct {
+	Data *collectionData `json:"data"`
+	Err  *string         `json:"error"`
+}
+
+type collectionListResponse struct {
+	Data       []collectionData `json:"data"`
+	NextCursor string           `json:"next_cursor"`
+	Total      uint64           `json:"total"`
+	Err        *string          `json:"error"`
+}
+
+func userResponseOk(user model.User) string {
+	data := userToUserData(user)
+	resp := userResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func userListResponseOk(users model.UserList) string {
+	data := make([]userData, 0, len(users.Users))
+	for _, user := range users.Users {
+		data = append(data, userToUserData(user))
+	}
+	resp := userListResponse{
+		Data:       data,
+		NextCursor: users.NextCursor,
+		Total:      users.Total,
+		Err:        nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func userToUserData(user model.User) userData {
+	return userData{
+		Id:        user.Id,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Disabled:  user.Disabled,
+		CreatedAt: user.CreatedAt.Unix(),
+	}
+}
+
+type userData struct {
+	Id       uint64 `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+	// Роль пользователя
+	Role     model.Role `json:"role"`
+	Disabled bool       `json:"disabled"`
+	// Время создания пользователя
+	CreatedAt int64 `json:"created_at"`
+}
+
+type userResponse struct {
+	Data *userData `json:"data"`
+	Err  *string   `json:"error"`
+}
+
+type userListResponse struct {
+	Data       []userData `json:"data"`
+	NextCursor string     `json:"next_cursor"`
+	Total      uint64     `json:"total"`
+	Err        *string    `json:"error"`
+}
+
+func tokensResponseOk(tokens model.Tokens) string {
+	now := time.Now()
+	data := tokensData{
+		AccessToken:      tokens.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(tokens.AccessTokenExpiresAt.Sub(now).Round(time.Second).Seconds()),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresIn: int64(tokens.RefreshTokenExpiresAt.Sub(now).Round(time.Second).Seconds()),
+	}
+	resp := tokensResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+type tokensData struct {
+	// Токен доступа, передаётся в заголовке Authorization: Bearer <токен>
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// Время жизни токена доступа в секундах
+	ExpiresIn int64 `json:"expires_in"`
+	// Одноразовый токен для получения новой пары токенов
+	RefreshToken string `json:"refresh_token"`
+	// Время жизни сессии в секундах, если её не продлевать
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
+}
+
+type tokensResponse struct {
+	Data *tokensData `json:"data"`
+	Err  *string     `json:"error"`
+}
+
+func apiKeyResponseOk(key model.ApiKey) string {
+	data := apiKeyToApiKeyData(key)
+	resp := apiKeyResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func apiKeyListResponseOk(keys []model.ApiKey) string {
+	data := make([]apiKeyData, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, apiKeyToApiKeyData(key))
+	}
+	resp := apiKeyListResponse{
+		Data: data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func apiKeyToApiKeyData(key model.ApiKey) apiKeyData {
+	data := apiKeyData{
+		Id:        key.Id,
+		UserId:    key.UserId,
+		Name:      key.Name,
+		Key:       key.Key,
+		Prefix:    key.Prefix,
+		Scope:     key.Scope,
+		CreatedAt: key.CreatedAt.Unix(),
+	}
+	if key.ExpiresAt != nil {
+		expiresAt := key.ExpiresAt.Unix()
+		data.ExpiresAt = &expiresAt
+	}
+	return data
+}
+
+type apiKeyData struct {
+	Id     uint64 `json:"id"`
+	UserId uint64 `json:"user_id"`
+	Name   string `json:"name"`
+	// Ключ, возвращается только при создании
+	Key string `json:"key,omitempty"`
+	// Начало ключа, по которому его можно узнать
+	Prefix string `json:"prefix"`
+	// Область действия: read или write
+	Scope model.ApiKeyScope `json:"scope"`
+	// Время истечения (timestamp), отсутствует у бессрочных ключей
+	ExpiresAt *int64 `json:"expires_at,omitempty"`
+	// Время создания ключа
+	CreatedAt int64 `json:"created_at"`
+}
+
+type apiKeyResponse struct {
+	Data *apiKeyData `json:"data"`
+	Err  *string     `json:"error"`
+}
+
+type apiKeyListResponse struct {
+	Data []apiKeyData `json:"data"`
+	Err  *string      `json:"error"`
+}
+
+func roleResponseOk(role model.RolePermissions) string {
+	data := roleToRoleData(role)
+	resp := roleResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func roleListResponseOk(roles []model.RolePermissions) string {
+	data := make([]roleData, 0, len(roles))
+	for _, role := range roles {
+		data = append(data, roleToRoleData(role))
+	}
+	resp := roleListResponse{
+		Data: data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+func roleToRoleData(role model.RolePermissions) roleData {
+	return roleData{
+		Name:        role.Role,
+		Permissions: role.Permissions,
+	}
+}
+
+type roleData struct {
+	Name model.Role `json:"name"`
+	// Разрешения роли, упорядоченные по названию
+	Permissions []model.Permission `json:"permissions"`
+}
+
+type roleResponse struct {
+	Data *roleData `json:"data"`
+	Err  *string   `json:"error"`
+}
+
+type roleListResponse struct {
+	Data []roleData `json:"data"`
+	Err  *string    `json:"error"`
+}
+
+func fuzzySearchResponseOk(res model.FuzzySearchResult) string {
+	data := fuzzySearchData{
+		Movies:     moviesToMovieListData(res.Movies),
+		Actors:     actorsToActorListData(res.Actors),
+		DidYouMean: res.DidYouMean,
+	}
+	resp := fuzzySearchResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+type fuzzySearchData struct {
+	Movies []movieData `json:"movies"`
+	Actors []actorData `json:"actors"`
+	// Наиболее похожее название фильма или имя актёра, если полнотекстовый поиск ничего не нашёл
+	DidYouMean string `json:"did_you_mean,omitempty"`
+}
+
+type fuzzySearchResponse struct {
+	Data *fuzzySearchData `json:"data"`
+	Err  *string          `json:"error"`
+}
+
+func suggestResponseOk(suggestions []model.Suggestion) string {
+	data := make([]suggestionData, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		data = append(data, suggestionData{
+			Id:   suggestion.Id,
+			Type: suggestion.Type,
+			Text: suggestion.Text,
+		})
+	}
+	resp := suggestResponse{
+		Data: data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+type suggestionData struct {
+	Id uint64 `json:"id"`
+	// Тип: movie или actor
+	Type model.SuggestionType `json:"type"`
+	// Название фильма или имя и фамилия актёра
+	Text string `json:"text"`
+}
+
+type suggestResponse struct {
+	Data []suggestionData `json:"data"`
+	Err  *string          `json:"error"`
+}
+
+func poolStatsResponseOk(stats model.PoolStats) string {
+	data := poolStatsData{
+		AcquireCount:            stats.AcquireCount,
+		AcquireDurationMs:       stats.AcquireDuration.Milliseconds(),
+		AcquiredConns:           stats.AcquiredConns,
+		CanceledAcquireCount:    stats.CanceledAcquireCount,
+		ConstructingConns:       stats.ConstructingConns,
+		EmptyAcquireCount:       stats.EmptyAcquireCount,
+		IdleConns:               stats.IdleConns,
+		MaxConns:                stats.MaxConns,
+		TotalConns:              stats.TotalConns,
+		NewConnsCount:           stats.NewConnsCount,
+		MaxLifetimeDestroyCount: stats.MaxLifetimeDestroyCount,
+		MaxIdleDestroyCount:     stats.MaxIdleDestroyCount,
+	}
+	resp := poolStatsResponse{
+		Data: &data,
+		Err:  nil,
+	}
+	body, _ := json.Marshal(resp)
+	return string(body)
+}
+
+type poolStatsData struct {
+	AcquireCount            int64 `json:"acquire_count"`
+	AcquireDurationMs       int64 `json:"acquire_duration_ms"`
+	AcquiredConns           int32 `json:"acquired_conns"`
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	ConstructingConns       int32 `json:"constructing_conns"`
+	EmptyAcquireCount       int64 `json:"empty_acquire_count"`
+	IdleConns               int32 `json:"idle_conns"`
+	MaxConns                int32 `json:"max_conns"`
+	TotalConns              int32 `json:"total_conns"`
+	NewConnsCount           int64 `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+}
+
+type poolStatsResponse struct {
+	Data *poolStatsData `json:"data"`
+	Err  *string        `json:"error"`
+}
@@ -0,0 +1,255 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Добавление отзыва
+// @Description	Добавляет оценку фильма от 1 до 10 и текст отзыва от имени пользователя, каждый пользователь может оставить только один отзыв на фильм
+// @Tags			reviews
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		createReviewData	true	"Информация о новом отзыве"
+// @Success		200		{object}	reviewResponse		"Информация об отзыве"
+// @Failure		404		{object}	reviewResponse		"Фильма не существует"
+// @Failure		400		{object}	reviewResponse		"Неверный формат входных данных"
+// @Failure		409		{object}	reviewResponse		"Пользователь уже оставил отзыв на фильм"
+// @Failure		500		{object}	reviewResponse		"Проблемы на стороне сервера"
+// @Failure		401		{object}	reviewResponse		"Ошибка авторизации"
+// @Failure		403		{object}	reviewResponse		"Ошибка авторизации"
+// @Router			/reviews/ [post]
+func createReviewHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data createReviewData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		review, err := a.CreateReview(r.Context(), model.Review{
+			MovieId: data.MovieId,
+			Rating:  data.Rating,
+			Text:    data.Text,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, reviewResponseOk(review))
+		case errors.Is(err, model.ErrMovieNotExists):
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Обновление отзыва
+// @Description	Изменяет оценку и текст отзыва по id, изменить отзыв может только его автор
+// @Tags			reviews
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			review_id	query		string				true	"id отзыва"
+// @Param			input		body		updateReviewData	true	"Новые поля"
+// @Success		200			{object}	reviewResponse		"Информация об отзыве"
+// @Failure		404			{object}	reviewResponse		"Отзыва не существует"
+// @Failure		400			{object}	reviewResponse		"Неверный формат входных данных"
+// @Failure		500			{object}	reviewResponse		"Проблемы на стороне сервера"
+// @Failure		401			{object}	reviewResponse		"Ошибка авторизации"
+// @Failure		403			{object}	reviewResponse		"Ошибка авторизации"
+// @Router			/reviews/ [put]
+func updateReviewHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reviewId, err := strconv.ParseUint(r.URL.Query().Get("review_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data updateReviewData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		review, err := a.UpdateReview(r.Context(), reviewId, model.UpdateReview{
+			Rating: data.Rating,
+			Text:   data.Text,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, reviewResponseOk(review))
+		case errors.Is(err, model.ErrReviewNotExists):
+			writeError(w, model.ErrReviewNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление отзыва
+// @Description	Удаляет отзыв по id, автор может удалить свой отзыв, а пользователь с разрешением reviews:moderate любой
+// @Tags			reviews
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			review_id	query		string			true	"id отзыва"
+// @Success		200			{object}	reviewResponse	"Пустая структура"
+// @Failure		404			{object}	reviewResponse	"Отзыва не существует"
+// @Failure		400			{object}	reviewResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	reviewResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	reviewResponse	"Ошибка авторизации"
+// @Failure		403			{object}	reviewResponse	"Ошибка авторизации"
+// @Router			/reviews/ [delete]
+func deleteReviewHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reviewId, err := strconv.ParseUint(r.URL.Query().Get("review_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.DeleteReview(r.Context(), reviewId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrReviewNotExists):
+			writeError(w, model.ErrReviewNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение отзыва
+// @Description	Возвращает отзыв с указанным id
+// @Tags			reviews
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			review_id	query		string			true	"id отзыва"
+// @Success		200			{object}	reviewResponse	"Информация об отзыве"
+// @Failure		404			{object}	reviewResponse	"Отзыва не существует"
+// @Failure		400			{object}	reviewResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	reviewResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	reviewResponse	"Ошибка авторизации"
+// @Failure		403			{object}	reviewResponse	"Ошибка авторизации"
+// @Router			/reviews/ [get]
+func getReviewHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reviewId, err := strconv.ParseUint(r.URL.Query().Get("review_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var review model.Review
+		review, err = a.GetReview(r.Context(), reviewId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, reviewResponseOk(review))
+		case errors.Is(err, model.ErrReviewNotExists):
+			writeError(w, model.ErrReviewNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка отзывов
+// @Description	Возвращает страницу списка отзывов, начиная с новых, и общее количество подходящих отзывов
+// @Tags			reviews
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			movie_id	query		int					false	"Отзывы на фильм с указанным id"
+// @Param			user_id		query		int					false	"Отзывы пользователя с указанным id"
+// @Param			limit		query		int					false	"Количество отзывов на странице, по умолчанию 50, не больше 500"
+// @Param			cursor		query		string				false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200			{object}	reviewListResponse	"Информация об отзывах"
+// @Failure		400			{object}	reviewListResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	reviewListResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	reviewListResponse	"Ошибка авторизации"
+// @Failure		403			{object}	reviewListResponse	"Ошибка авторизации"
+// @Router			/reviews/list/ [get]
+func getReviewsListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := parsePage(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var filter model.ReviewFilter
+		filter, err = parseReviewFilter(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var reviews model.ReviewList
+		reviews, err = a.GetReviews(r.Context(), filter, page)
+
+		switch {
+		case err == nil:
+			writeResponse(w, reviewListResponseOk(reviews))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrInvalidCursor):
+			writeError(w, model.ErrInvalidCursor, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
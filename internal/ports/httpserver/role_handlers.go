@@ -0,0 +1,236 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+)
+
+// @Summary		Добавление роли
+// @Description	Добавляет новую роль с разрешениями. Управлять ролями могут пользователи с разрешением roles:manage, выдать роли можно только разрешения собственной роли
+// @Tags			roles
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		createRoleData	true	"Название и разрешения роли"
+// @Success		200		{object}	roleResponse	"Информация о роли"
+// @Failure		400		{object}	roleResponse	"Неверный формат входных данных"
+// @Failure		409		{object}	roleResponse	"Роль с таким названием уже существует"
+// @Failure		500		{object}	roleResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	roleResponse	"Ошибка авторизации"
+// @Failure		403		{object}	roleResponse	"Ошибка авторизации"
+// @Router			/roles/ [post]
+func createRoleHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data createRoleData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		role, err := a.CreateRole(r.Context(), model.RolePermissions{
+			Role:        data.Name,
+			Permissions: data.Permissions,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, roleResponseOk(role))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Обновление роли
+// @Description	Заменяет разрешения роли, изменения сразу действуют для всех пользователей с этой ролью. Разрешения администраторов изменить нельзя, выдать роли можно только разрешения собственной роли
+// @Tags			roles
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			role	query		string			true	"Название роли"
+// @Param			input	body		updateRoleData	true	"Новые разрешения"
+// @Success		200		{object}	roleResponse	"Информация о роли"
+// @Failure		404		{object}	roleResponse	"Роли не существует"
+// @Failure		400		{object}	roleResponse	"Неверный формат входных данных"
+// @Failure		409		{object}	roleResponse	"Встроенную роль изменить нельзя"
+// @Failure		500		{object}	roleResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	roleResponse	"Ошибка авторизации"
+// @Failure		403		{object}	roleResponse	"Ошибка авторизации"
+// @Router			/roles/ [put]
+func updateRoleHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := model.Role(r.URL.Query().Get("role"))
+		if name == "" {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data updateRoleData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		role, err := a.UpdateRole(r.Context(), model.RolePermissions{
+			Role:        name,
+			Permissions: data.Permissions,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, roleResponseOk(role))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrRoleNotExists):
+			writeError(w, model.ErrRoleNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrBuiltInRole):
+			writeError(w, model.ErrBuiltInRole, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление роли
+// @Description	Удаляет роль, которая не назначена ни одному пользователю. Роли admin и regular удалить нельзя
+// @Tags			roles
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			role	query		string			true	"Название роли"
+// @Success		200		{object}	roleResponse	"Пустая структура"
+// @Failure		404		{object}	roleResponse	"Роли не существует"
+// @Failure		400		{object}	roleResponse	"Неверный формат входных данных"
+// @Failure		409		{object}	roleResponse	"Роль встроенная или назначена пользователям"
+// @Failure		500		{object}	roleResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	roleResponse	"Ошибка авторизации"
+// @Failure		403		{object}	roleResponse	"Ошибка авторизации"
+// @Router			/roles/ [delete]
+func deleteRoleHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := model.Role(r.URL.Query().Get("role"))
+		if name == "" {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err := a.DeleteRole(r.Context(), name)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrRoleNotExists):
+			writeError(w, model.ErrRoleNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrBuiltInRole):
+			writeError(w, model.ErrBuiltInRole, http.StatusConflict)
+		case errors.Is(err, model.ErrRoleInUse):
+			writeError(w, model.ErrRoleInUse, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение роли
+// @Description	Возвращает роль с её разрешениями
+// @Tags			roles
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			role	query		string			true	"Название роли"
+// @Success		200		{object}	roleResponse	"Информация о роли"
+// @Failure		404		{object}	roleResponse	"Роли не существует"
+// @Failure		400		{object}	roleResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	roleResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	roleResponse	"Ошибка авторизации"
+// @Failure		403		{object}	roleResponse	"Ошибка авторизации"
+// @Router			/roles/ [get]
+func getRoleHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := model.Role(r.URL.Query().Get("role"))
+		if name == "" {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		role, err := a.GetRole(r.Context(), name)
+
+		switch {
+		case err == nil:
+			writeResponse(w, roleResponseOk(role))
+		case errors.Is(err, model.ErrRoleNotExists):
+			writeError(w, model.ErrRoleNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка ролей
+// @Description	Возвращает все роли с их разрешениями, упорядоченные по названию
+// @Tags			roles
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Success		200	{object}	roleListResponse	"Информация о ролях"
+// @Failure		500	{object}	roleListResponse	"Проблемы на стороне сервера"
+// @Failure		401	{object}	roleListResponse	"Ошибка авторизации"
+// @Failure		403	{object}	roleListResponse	"Ошибка авторизации"
+// @Router			/roles/list/ [get]
+func getRolesListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		roles, err := a.GetRoles(r.Context())
+
+		switch {
+		case err == nil:
+			writeResponse(w, roleListResponseOk(roles))
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
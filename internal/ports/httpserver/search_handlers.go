@@ -0,0 +1,99 @@
+package httpserver
+
+import (
+	"errors"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Нечёткий поиск
+// @Description	Возвращает фильмы с похожими на запрос названиями и актёров с похожими именами, упорядоченные по убыванию похожести. Находит названия и имена с опечатками. Если полнотекстовый поиск фильмов по запросу ничего не находит, предлагает наиболее похожее название или имя
+// @Tags			search
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			q		query		string				true	"Запрос"
+// @Param			limit	query		int					false	"Максимальное количество фильмов и актёров, по умолчанию 10, не больше 50"
+// @Success		200		{object}	fuzzySearchResponse	"Найденные фильмы и актёры"
+// @Failure		400		{object}	fuzzySearchResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	fuzzySearchResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	fuzzySearchResponse	"Ошибка авторизации"
+// @Failure		403		{object}	fuzzySearchResponse	"Ошибка авторизации"
+// @Router			/search/fuzzy/ [get]
+func fuzzySearchHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			limit int
+			err   error
+		)
+		if r.URL.Query().Has("limit") {
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil {
+				writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+				return
+			}
+		}
+
+		res, err := a.FuzzySearch(r.Context(), r.URL.Query().Get("q"), limit)
+
+		switch {
+		case err == nil:
+			writeResponse(w, fuzzySearchResponseOk(res))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Подсказки для строки поиска
+// @Description	Возвращает фильмы, названия которых начинаются с введённого текста, и актёров, имя и фамилия или фамилия которых начинаются с него, без учёта регистра. Более короткие названия и имена идут первыми
+// @Tags			search
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			q		query		string			true	"Начало названия или имени, не короче двух символов"
+// @Param			limit	query		int				false	"Количество подсказок, по умолчанию 10, не больше 20"
+// @Success		200		{object}	suggestResponse	"Подсказки"
+// @Failure		400		{object}	suggestResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	suggestResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	suggestResponse	"Ошибка авторизации"
+// @Failure		403		{object}	suggestResponse	"Ошибка авторизации"
+// @Router			/suggest [get]
+func suggestHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			limit int
+			err   error
+		)
+		if r.URL.Query().Has("limit") {
+			limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil {
+				writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+				return
+			}
+		}
+
+		suggestions, err := a.Suggest(r.Context(), r.URL.Query().Get("q"), limit)
+
+		switch {
+		case err == nil:
+			writeResponse(w, suggestResponseOk(suggestions))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
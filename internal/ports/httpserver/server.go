@@ -7,35 +7,202 @@ import (
 	_ "movie-lib/docs"
 	"movie-lib/internal/app"
 	"movie-lib/pkg/logger"
+	"net"
 	"net/http"
 )
 
-func handleMovies(ctx context.Context, a app.App) http.HandlerFunc {
+func handleMovies(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			createMovieHandler(ctx, a)(w, r)
+			createMovieHandler(a)(w, r)
 		case http.MethodPut:
-			updateMovieHandler(ctx, a)(w, r)
+			updateMovieHandler(a)(w, r)
 		case http.MethodDelete:
-			deleteMovieHandler(ctx, a)(w, r)
+			deleteMovieHandler(a)(w, r)
 		case http.MethodGet:
-			getMovieHandler(ctx, a)(w, r)
+			getMovieHandler(a)(w, r)
 		}
 	}
 }
 
-func handleActors(ctx context.Context, a app.App) http.HandlerFunc {
+func handleActors(a app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			createActorHandler(ctx, a)(w, r)
+			createActorHandler(a)(w, r)
 		case http.MethodPut:
-			updateActorHandler(ctx, a)(w, r)
+			updateActorHandler(a)(w, r)
 		case http.MethodDelete:
-			deleteActorHandler(ctx, a)(w, r)
+			deleteActorHandler(a)(w, r)
 		case http.MethodGet:
-			getActorHandler(ctx, a)(w, r)
+			getActorHandler(a)(w, r)
+		}
+	}
+}
+
+func handleGenres(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createGenreHandler(a)(w, r)
+		case http.MethodPut:
+			updateGenreHandler(a)(w, r)
+		case http.MethodDelete:
+			deleteGenreHandler(a)(w, r)
+		case http.MethodGet:
+			getGenreHandler(a)(w, r)
+		}
+	}
+}
+
+func handleRoles(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createRoleHandler(a)(w, r)
+		case http.MethodPut:
+			updateRoleHandler(a)(w, r)
+		case http.MethodDelete:
+			deleteRoleHandler(a)(w, r)
+		case http.MethodGet:
+			getRoleHandler(a)(w, r)
+		}
+	}
+}
+
+func handleReviews(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createReviewHandler(a)(w, r)
+		case http.MethodPut:
+			updateReviewHandler(a)(w, r)
+		case http.MethodDelete:
+			deleteReviewHandler(a)(w, r)
+		case http.MethodGet:
+			getReviewHandler(a)(w, r)
+		}
+	}
+}
+
+func handleWatchlist(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			addToWatchlistHandler(a)(w, r)
+		case http.MethodDelete:
+			removeFromWatchlistHandler(a)(w, r)
+		}
+	}
+}
+
+func handleWatched(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			markWatchedHandler(a)(w, r)
+		case http.MethodDelete:
+			unmarkWatchedHandler(a)(w, r)
+		}
+	}
+}
+
+func handleCollections(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createCollectionHandler(a)(w, r)
+		case http.MethodPut:
+			updateCollectionHandler(a)(w, r)
+		case http.MethodDelete:
+			deleteCollectionHandler(a)(w, r)
+		case http.MethodGet:
+			getCollectionHandler(a)(w, r)
+		}
+	}
+}
+
+func handleCollectionEntries(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			addCollectionEntryHandler(a)(w, r)
+		case http.MethodPut:
+			updateCollectionEntryHandler(a)(w, r)
+		case http.MethodDelete:
+			removeCollectionEntryHandler(a)(w, r)
+		}
+	}
+}
+
+func handleCollectionOrder(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			reorderCollectionHandler(a)(w, r)
+		}
+	}
+}
+
+func handleUsers(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createUserHandler(a)(w, r)
+		case http.MethodPut:
+			updateUserHandler(a)(w, r)
+		case http.MethodDelete:
+			deleteUserHandler(a)(w, r)
+		case http.MethodGet:
+			getUserHandler(a)(w, r)
+		}
+	}
+}
+
+func handleUserPassword(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			setPasswordHandler(a)(w, r)
+		}
+	}
+}
+
+func handleApiKeys(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			createApiKeyHandler(a)(w, r)
+		case http.MethodDelete:
+			revokeApiKeyHandler(a)(w, r)
+		}
+	}
+}
+
+func handleLogin(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			loginHandler(a)(w, r)
+		}
+	}
+}
+
+func handleRefresh(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			refreshHandler(a)(w, r)
+		}
+	}
+}
+
+func handleLogout(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			logoutHandler(a)(w, r)
 		}
 	}
 }
@@ -45,13 +212,55 @@ func New(ctx context.Context, host string, port int, a app.App, logs logger.Logg
 
 	mux.Handle("/swagger/", httpSwagger.Handler(httpSwagger.URL(fmt.Sprintf("http://%s:%d/swagger/doc.json", "localhost", port))))
 
-	mux.Handle("/api/v1/actors/", logMiddleware(handleActors(ctx, a), logs))
-	mux.Handle("/api/v1/actors/list/", logMiddleware(getActorsListHandler(ctx, a), logs))
-	mux.Handle("/api/v1/movies/", logMiddleware(handleMovies(ctx, a), logs))
-	mux.Handle("/api/v1/movies/list/", logMiddleware(getMovieListHandler(ctx, a), logs))
+	// tokens are issued without authorization, all other requests need a
+	// valid access token or API key, credentials are managed only with access
+	// tokens
+	mux.Handle("/api/v1/auth/login/", logMiddleware(handleLogin(a), logs))
+	mux.Handle("/api/v1/auth/refresh/", logMiddleware(handleRefresh(a), logs))
+	protected := func(handler http.Handler) http.Handler {
+		return logMiddleware(authMiddleware(handler, a), logs)
+	}
+	sessionOnly := func(handler http.Handler) http.Handler {
+		return protected(sessionMiddleware(handler))
+	}
+	mux.Handle("/api/v1/auth/logout/", sessionOnly(handleLogout(a)))
+	mux.Handle("/api/v1/api-keys/", sessionOnly(handleApiKeys(a)))
+	mux.Handle("/api/v1/api-keys/list/", sessionOnly(getApiKeysListHandler(a)))
+	mux.Handle("/api/v1/actors/", protected(handleActors(a)))
+	mux.Handle("/api/v1/actors/list/", protected(getActorsListHandler(a)))
+	mux.Handle("/api/v1/movies/", protected(handleMovies(a)))
+	mux.Handle("/api/v1/movies/list/", protected(getMovieListHandler(a)))
+	mux.Handle("/api/v1/persons/filmography/", protected(getFilmographyHandler(a)))
+	mux.Handle("/api/v1/genres/", protected(handleGenres(a)))
+	mux.Handle("/api/v1/genres/list/", protected(getGenresListHandler(a)))
+	mux.Handle("/api/v1/reviews/", protected(handleReviews(a)))
+	mux.Handle("/api/v1/reviews/list/", protected(getReviewsListHandler(a)))
+	mux.Handle("/api/v1/watchlist/", protected(handleWatchlist(a)))
+	mux.Handle("/api/v1/watchlist/list/", protected(getWatchlistHandler(a)))
+	mux.Handle("/api/v1/watched/", protected(handleWatched(a)))
+	mux.Handle("/api/v1/watched/list/", protected(getWatchedListHandler(a)))
+	mux.Handle("/api/v1/collections/", protected(handleCollections(a)))
+	mux.Handle("/api/v1/collections/list/", protected(getCollectionsListHandler(a)))
+	mux.Handle("/api/v1/collections/entries/", protected(handleCollectionEntries(a)))
+	mux.Handle("/api/v1/collections/order/", protected(handleCollectionOrder(a)))
+	mux.Handle("/api/v1/users/", protected(handleUsers(a)))
+	mux.Handle("/api/v1/users/list/", protected(getUsersListHandler(a)))
+	mux.Handle("/api/v1/users/password/", sessionOnly(handleUserPassword(a)))
+	mux.Handle("/api/v1/roles/", protected(handleRoles(a)))
+	mux.Handle("/api/v1/roles/list/", protected(getRolesListHandler(a)))
+	mux.Handle("/api/v1/search/fuzzy/", protected(fuzzySearchHandler(a)))
+	// suggestions are requested on every key press, so the path without the
+	// trailing slash is served without a redirect
+	suggest := protected(suggestHandler(a))
+	mux.Handle("/api/v1/suggest", suggest)
+	mux.Handle("/api/v1/suggest/", suggest)
+	mux.Handle("/api/v1/stats/pool/", protected(getPoolStatsHandler(a)))
 
+	// handlers pass the request context with the principal to the app, the
+	// request contexts are derived from ctx
 	return &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", host, port),
-		Handler: mux,
+		Addr:        fmt.Sprintf("%s:%d", host, port),
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 }
@@ -0,0 +1,38 @@
+package httpserver
+
+import (
+	"errors"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+)
+
+// @Summary		Статистика пула соединений
+// @Description	Возвращает статистику пула соединений с базой данных, доступно пользователям с разрешением stats:read
+// @Tags			stats
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Success		200	{object}	poolStatsResponse	"Статистика пула соединений"
+// @Failure		500	{object}	poolStatsResponse	"Проблемы на стороне сервера"
+// @Failure		401	{object}	poolStatsResponse	"Ошибка авторизации"
+// @Failure		403	{object}	poolStatsResponse	"Ошибка авторизации"
+// @Router			/stats/pool/ [get]
+func getPoolStatsHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := a.GetPoolStats(r.Context())
+
+		switch {
+		case err == nil:
+			writeResponse(w, poolStatsResponseOk(stats))
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
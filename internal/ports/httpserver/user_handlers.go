@@ -0,0 +1,320 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+)
+
+// @Summary		Создание пользователя
+// @Description	Создаёт пользователя с паролем, управлять пользователями могут пользователи с разрешением users:manage, назначая только роли, разрешения которых есть у их собственной роли, а администраторами только администраторы
+// @Tags			users
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			input	body		createUserData	true	"Информация о новом пользователе"
+// @Success		200		{object}	userResponse	"Информация о пользователе"
+// @Failure		400		{object}	userResponse	"Неверный формат входных данных или роли не существует"
+// @Failure		409		{object}	userResponse	"Имя пользователя или адрес почты уже заняты"
+// @Failure		500		{object}	userResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	userResponse	"Ошибка авторизации"
+// @Failure		403		{object}	userResponse	"Ошибка авторизации"
+// @Router			/users/ [post]
+func createUserHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data createUserData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		user, err := a.CreateUser(r.Context(), model.User{
+			Username: data.Username,
+			Email:    data.Email,
+			Role:     data.Role,
+			Disabled: data.Disabled,
+		}, data.Password)
+
+		switch {
+		case err == nil:
+			writeResponse(w, userResponseOk(user))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrRoleNotExists):
+			writeError(w, model.ErrRoleNotExists, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Обновление пользователя
+// @Description	Изменяет имя, адрес почты, роль пользователя и блокирует или разблокирует его. Назначить или изменить администратора может только администратор, остальных пользователей и роли — пользователь, у роли которого есть все их разрешения. Последнего активного администратора нельзя лишить прав или заблокировать
+// @Tags			users
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			user_id	query		string			true	"id пользователя"
+// @Param			input	body		updateUserData	true	"Новые поля"
+// @Success		200		{object}	userResponse	"Информация о пользователе"
+// @Failure		404		{object}	userResponse	"Пользователя не существует"
+// @Failure		400		{object}	userResponse	"Неверный формат входных данных или роли не существует"
+// @Failure		409		{object}	userResponse	"Имя пользователя или адрес почты уже заняты, либо это последний активный администратор"
+// @Failure		500		{object}	userResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	userResponse	"Ошибка авторизации"
+// @Failure		403		{object}	userResponse	"Ошибка авторизации"
+// @Router			/users/ [put]
+func updateUserHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.URL.Query().Get("user_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data updateUserData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		user, err := a.UpdateUser(r.Context(), id, model.UpdateUser{
+			Username: data.Username,
+			Email:    data.Email,
+			Role:     data.Role,
+			Disabled: data.Disabled,
+		})
+
+		switch {
+		case err == nil:
+			writeResponse(w, userResponseOk(user))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrRoleNotExists):
+			writeError(w, model.ErrRoleNotExists, http.StatusBadRequest)
+		case errors.Is(err, model.ErrConflict):
+			writeError(w, model.ErrConflict, http.StatusConflict)
+		case errors.Is(err, model.ErrLastAdmin):
+			writeError(w, model.ErrLastAdmin, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrUserNotExists):
+			writeError(w, model.ErrUserNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление пользователя
+// @Description	Удаляет пользователя вместе с его отзывами, списками и подборками. Администратора может удалить только администратор, остальных пользователей — пользователь, у роли которого есть все разрешения их роли. Последнего активного администратора удалить нельзя
+// @Tags			users
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			user_id	query		string			true	"id пользователя"
+// @Success		200		{object}	userResponse	"Пустая структура"
+// @Failure		404		{object}	userResponse	"Пользователя не существует"
+// @Failure		400		{object}	userResponse	"Неверный формат входных данных"
+// @Failure		409		{object}	userResponse	"Последний активный администратор"
+// @Failure		500		{object}	userResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	userResponse	"Ошибка авторизации"
+// @Failure		403		{object}	userResponse	"Ошибка авторизации"
+// @Router			/users/ [delete]
+func deleteUserHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.URL.Query().Get("user_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.DeleteUser(r.Context(), id)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrLastAdmin):
+			writeError(w, model.ErrLastAdmin, http.StatusConflict)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrUserNotExists):
+			writeError(w, model.ErrUserNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение пользователя
+// @Description	Возвращает пользователя с указанным id, пользователи с разрешением users:manage могут получить любого пользователя, остальные только себя
+// @Tags			users
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			user_id	query		string			true	"id пользователя"
+// @Success		200		{object}	userResponse	"Информация о пользователе"
+// @Failure		404		{object}	userResponse	"Пользователя не существует"
+// @Failure		400		{object}	userResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	userResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	userResponse	"Ошибка авторизации"
+// @Failure		403		{object}	userResponse	"Ошибка авторизации"
+// @Router			/users/ [get]
+func getUserHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.URL.Query().Get("user_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var user model.User
+		user, err = a.GetUser(r.Context(), id)
+
+		switch {
+		case err == nil:
+			writeResponse(w, userResponseOk(user))
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrUserNotExists):
+			writeError(w, model.ErrUserNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка пользователей
+// @Description	Возвращает страницу списка пользователей, упорядоченных по id, и общее количество пользователей
+// @Tags			users
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			limit	query		int					false	"Количество пользователей на странице, по умолчанию 50, не больше 500"
+// @Param			cursor	query		string				false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200		{object}	userListResponse	"Информация о пользователях"
+// @Failure		400		{object}	userListResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	userListResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	userListResponse	"Ошибка авторизации"
+// @Failure		403		{object}	userListResponse	"Ошибка авторизации"
+// @Router			/users/list/ [get]
+func getUsersListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := parsePage(r)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		var users model.UserList
+		users, err = a.GetUsers(r.Context(), page)
+
+		switch {
+		case err == nil:
+			writeResponse(w, userListResponseOk(users))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrInvalidCursor):
+			writeError(w, model.ErrInvalidCursor, http.StatusBadRequest)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Смена пароля
+// @Description	Меняет пароль пользователя и завершает все его сессии. Свой пароль пользователь меняет, подтвердив его текущим паролем, а пользователи с разрешением users:manage могут сменить без него и пароль пользователя, роль которого не даёт разрешений больше, чем их собственная
+// @Tags			users
+// @Security		ApiKeyAuth
+// @Accept			json
+// @Produce		json
+// @Param			user_id	query		string			true	"id пользователя"
+// @Param			input	body		setPasswordData	true	"Новый и текущий пароли"
+// @Success		200		{object}	userResponse	"Пустая структура"
+// @Failure		404		{object}	userResponse	"Пользователя не существует"
+// @Failure		400		{object}	userResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	userResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	userResponse	"Ошибка авторизации"
+// @Failure		403		{object}	userResponse	"Ошибка авторизации или неверный текущий пароль"
+// @Router			/users/password/ [put]
+func setPasswordHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseUint(r.URL.Query().Get("user_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var data setPasswordData
+		if err = json.Unmarshal(body, &data); err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.SetPassword(r.Context(), id, data.Password, data.CurrentPassword)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrInvalidCredentials):
+			writeError(w, model.ErrInvalidCredentials, http.StatusForbidden)
+		case errors.Is(err, model.ErrPermissionDenied):
+			writeError(w, model.ErrPermissionDenied, http.StatusForbidden)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrUserNotExists):
+			writeError(w, model.ErrUserNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package httpserver
+
+import (
+	"errors"
+	"movie-lib/internal/app"
+	"movie-lib/internal/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @Summary		Добавление фильма в список «Буду смотреть»
+// @Description	Добавляет фильм в список «Буду смотреть» пользователя, повторное добавление не считается ошибкой
+// @Tags			watchlist
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			movie_id	query		string			true	"id фильма"
+// @Success		200			{object}	movieResponse	"Информация о фильме со статусом"
+// @Failure		404			{object}	movieResponse	"Фильма не существует"
+// @Failure		400			{object}	movieResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	movieResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	movieResponse	"Ошибка авторизации"
+// @Failure		403			{object}	movieResponse	"Ошибка авторизации"
+// @Router			/watchlist/ [post]
+func addToWatchlistHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		movie, err := a.AddToWatchlist(r.Context(), movieId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, movieResponseOk(movie))
+		case errors.Is(err, model.ErrMovieNotExists):
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление фильма из списка «Буду смотреть»
+// @Description	Удаляет фильм из списка «Буду смотреть» пользователя
+// @Tags			watchlist
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			movie_id	query		string			true	"id фильма"
+// @Success		200			{object}	movieResponse	"Пустая структура"
+// @Failure		404			{object}	movieResponse	"Фильма нет в списке"
+// @Failure		400			{object}	movieResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	movieResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	movieResponse	"Ошибка авторизации"
+// @Failure		403			{object}	movieResponse	"Ошибка авторизации"
+// @Router			/watchlist/ [delete]
+func removeFromWatchlistHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.RemoveFromWatchlist(r.Context(), movieId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrEntryNotExists):
+			writeError(w, model.ErrEntryNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Отметка фильма просмотренным
+// @Description	Добавляет фильм в историю просмотров пользователя с датой просмотра, для уже просмотренного фильма меняет дату
+// @Tags			watchlist
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			movie_id	query		string			true	"id фильма"
+// @Param			watched_at	query		int				false	"Дата просмотра (timestamp), по умолчанию сегодня, не может быть в будущем"
+// @Success		200			{object}	movieResponse	"Информация о фильме со статусом"
+// @Failure		404			{object}	movieResponse	"Фильма не существует"
+// @Failure		400			{object}	movieResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	movieResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	movieResponse	"Ошибка авторизации"
+// @Failure		403			{object}	movieResponse	"Ошибка авторизации"
+// @Router			/watched/ [post]
+func markWatchedHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		var watchedAt time.Time
+		if r.URL.Query().Has("watched_at") {
+			timestamp, err := strconv.ParseInt(r.URL.Query().Get("watched_at"), 10, 64)
+			if err != nil {
+				writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+				return
+			}
+			watchedAt = time.Unix(timestamp, 0).UTC()
+		}
+
+		movie, err := a.MarkWatched(r.Context(), movieId, watchedAt)
+
+		switch {
+		case err == nil:
+			writeResponse(w, movieResponseOk(movie))
+		case errors.Is(err, model.ErrMovieNotExists):
+			writeError(w, model.ErrMovieNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrValidationError):
+			writeError(w, model.ErrValidationError, http.StatusBadRequest)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Удаление фильма из истории просмотров
+// @Description	Снимает с фильма отметку о просмотре
+// @Tags			watchlist
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			movie_id	query		string			true	"id фильма"
+// @Success		200			{object}	movieResponse	"Пустая структура"
+// @Failure		404			{object}	movieResponse	"Фильма нет в истории просмотров"
+// @Failure		400			{object}	movieResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	movieResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	movieResponse	"Ошибка авторизации"
+// @Failure		403			{object}	movieResponse	"Ошибка авторизации"
+// @Router			/watched/ [delete]
+func unmarkWatchedHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		movieId, err := strconv.ParseUint(r.URL.Query().Get("movie_id"), 10, 64)
+		if err != nil {
+			writeError(w, model.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+
+		err = a.UnmarkWatched(r.Context(), movieId)
+
+		switch {
+		case err == nil:
+			writeResponse(w, errorResponse(nil))
+		case errors.Is(err, model.ErrEntryNotExists):
+			writeError(w, model.ErrEntryNotExists, http.StatusNotFound)
+		case errors.Is(err, model.ErrUnauthorized):
+			writeError(w, model.ErrUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, model.ErrDatabaseError):
+			writeError(w, model.ErrDatabaseError, http.StatusInternalServerError)
+		default:
+			writeError(w, model.ErrServiceError, http.StatusInternalServerError)
+		}
+	}
+}
+
+// @Summary		Получение списка «Буду смотреть»
+// @Description	Возвращает страницу фильмов из списка «Буду смотреть» пользователя. Поддерживает те же параметры фильтрации, сортировки и поиска, что и список фильмов, например watched=false оставляет только непросмотренные фильмы
+// @Tags			watchlist
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			pattern	query		string				false	"Полнотекстовый поиск по названию, описанию фильма и именам актёров"
+// @Param			sort_by	query		string				false	"Параметры сортировки как у списка фильмов"
+// @Param			actors	query		string				false	"id актёров через запятую"
+// @Param			genres	query		string				false	"id жанров через запятую"
+// @Param			watched	query		bool				false	"Просмотренные (true) или непросмотренные (false) фильмы"
+// @Param			limit	query		int					false	"Количество фильмов на странице, по умолчанию 50, не больше 500"
+// @Param			cursor	query		string				false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200		{object}	movieListResponse	"Информация о фильмах"
+// @Failure		400		{object}	movieListResponse	"Неверный формат входных данных"
+// @Failure		500		{object}	movieListResponse	"Проблемы на стороне сервера"
+// @Failure		401		{object}	movieListResponse	"Ошибка авторизации"
+// @Failure		403		{object}	movieListResponse	"Ошибка авторизации"
+// @Router			/watchlist/list/ [get]
+func getWatchlistHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Set("watchlist", "true")
+		r.URL.RawQuery = query.Encode()
+		getMovieListHandler(a)(w, r)
+	}
+}
+
+// @Summary		Получение истории просмотров
+// @Description	Возвращает страницу просмотренных пользователем фильмов. Поддерживает те же параметры фильтрации, сортировки и поиска, что и список фильмов
+// @Tags			watchlist
+// @Security		ApiKeyAuth
+// @Security		ServiceKeyAuth
+// @Produce		json
+// @Param			pattern		query		string				false	"Полнотекстовый поиск по названию, описанию фильма и именам актёров"
+// @Param			sort_by		query		string				false	"Параметры сортировки как у списка фильмов"
+// @Param			actors		query		string				false	"id актёров через запятую"
+// @Param			genres		query		string				false	"id жанров через запятую"
+// @Param			watchlist	query		bool				false	"Фильмы, которые есть (true) или которых нет (false) в списке «Буду смотреть»"
+// @Param			limit		query		int					false	"Количество фильмов на странице, по умолчанию 50, не больше 500"
+// @Param			cursor		query		string				false	"Курсор следующей страницы из поля next_cursor предыдущего ответа"
+// @Success		200			{object}	movieListResponse	"Информация о фильмах"
+// @Failure		400			{object}	movieListResponse	"Неверный формат входных данных"
+// @Failure		500			{object}	movieListResponse	"Проблемы на стороне сервера"
+// @Failure		401			{object}	movieListResponse	"Ошибка авторизации"
+// @Failure		403			{object}	movieListResponse	"Ошибка авторизации"
+// @Router			/watched/list/ [get]
+func getWatchedListHandler(a app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Set("watched", "true")
+		r.URL.RawQuery = query.Encode()
+		getMovieListHandler(a)(w, r)
+	}
+}
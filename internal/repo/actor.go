@@ -5,41 +5,50 @@ import (
 	"errors"
 	"github.com/jackc/pgx/v5"
 	"movie-lib/internal/model"
+	"strings"
 )
 
 const (
+	actorColumns = `"actors"."id", "actors"."first_name", "actors"."second_name", "actors"."gender",
+		"actors"."crew_only"`
+
+	// actorNotCrewOnlyCondition excludes persons who are not actors from
+	// lists, search and suggestions of actors
+	actorNotCrewOnlyCondition = `NOT "actors"."crew_only"`
+
 	createActorQuery = `
-		INSERT INTO "actors" ("first_name", "second_name", "gender") 
-		VALUES ($1, $2, $3)
+		INSERT INTO "actors" ("first_name", "second_name", "gender", "crew_only") 
+		VALUES ($1, $2, $3, $4)
 		RETURNING "id";`
 
 	updateActorQuery = `
 		UPDATE "actors"
 		SET "first_name" = $2,
 		    "second_name" = $3,
-		    "gender" = $4
+		    "gender" = $4,
+		    "crew_only" = $5
 		WHERE "id" = $1;`
 
+	// markActorsCastQuery clears the crew only mark of persons cast in a movie
+	markActorsCastQuery = `
+		UPDATE "actors"
+		SET "crew_only" = FALSE
+		WHERE "id" = ANY($1::bigint[]) AND "crew_only";`
+
 	getActorQuery = `
-		SELECT * FROM "actors"
+		SELECT ` + actorColumns + ` FROM "actors"
 		WHERE "id" = $1;`
 
-	getActorsQuery = `
-		SELECT * FROM "actors";`
-
-	getActorMoviesQuery = `
-		SELECT "movies"."id", "movies"."title", "movies"."description", "movies"."release_date", "movies"."rating"
+	// getActorsMoviesQuery loads movies of several actors at once
+	getActorsMoviesQuery = `
+		SELECT "movie-actor"."actor_id", ` + movieColumns + `
 		FROM "movie-actor"
-			INNER JOIN "movies" ON "movie-id" = "movies"."id"
-		WHERE "movie-actor"."actor_id" = $1;`
+			INNER JOIN "movies" ON "movie-actor"."movie-id" = "movies"."id"
+		WHERE "movie-actor"."actor_id" = ANY($1::bigint[]);`
 
 	deleteActorQuery = `
 		DELETE FROM "actors"
 		WHERE "id" = $1;`
-
-	deleteActorFromMoviesQuery = `
-		DELETE FROM "movie-actor"
-		WHERE "actor_id" = $1;`
 )
 
 func (r *repoImpl) CreateActor(ctx context.Context, actor model.Actor) (model.Actor, error) {
@@ -47,103 +56,163 @@ func (r *repoImpl) CreateActor(ctx context.Context, actor model.Actor) (model.Ac
 		actor.FirstName,
 		actor.SecondName,
 		actor.Gender,
+		actor.CrewOnly,
 	).Scan(&actor.Id); err != nil {
-		return model.Actor{}, errors.Join(model.ErrDatabaseError, err)
+		return model.Actor{}, mapError(err)
 	}
 	return actor, nil
 }
 
 func (r *repoImpl) UpdateActor(ctx context.Context, id uint64, upd model.UpdateActor) (model.Actor, error) {
-	if e, err := r.Exec(ctx, updateActorQuery,
-		id,
-		upd.FirstName,
-		upd.SecondName,
-		upd.Gender,
-	); err != nil {
-		return model.Actor{}, errors.Join(model.ErrDatabaseError, err)
-	} else if e.RowsAffected() == 0 {
-		return model.Actor{}, model.ErrActorNotExists
+	var actor model.Actor
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if e, err := tx.Exec(ctx, updateActorQuery,
+			id,
+			upd.FirstName,
+			upd.SecondName,
+			upd.Gender,
+			upd.CrewOnly,
+		); err != nil {
+			return mapError(err)
+		} else if e.RowsAffected() == 0 {
+			return model.ErrActorNotExists
+		}
+
+		var err error
+		actor, err = tx.GetActor(ctx, id)
+		return err
+	})
+	if err != nil {
+		return model.Actor{}, err
 	}
-	return r.GetActor(ctx, id)
+	return actor, nil
 }
 
+// DeleteActor deletes actor, its links to movies as the cast and the crew are
+// deleted by cascade
 func (r *repoImpl) DeleteActor(ctx context.Context, id uint64) error {
 	if e, err := r.Exec(ctx, deleteActorQuery, id); err != nil {
-		return errors.Join(model.ErrDatabaseError, err)
+		return mapError(err)
 	} else if e.RowsAffected() == 0 {
 		return model.ErrActorNotExists
 	}
-
-	if _, err := r.Exec(ctx, deleteActorFromMoviesQuery, id); err != nil {
-		return errors.Join(model.ErrDatabaseError, err)
-	}
 	return nil
 }
 
 func (r *repoImpl) GetActor(ctx context.Context, id uint64) (model.Actor, error) {
-	row := r.QueryRow(ctx, getActorQuery, id)
-	var actor model.Actor
-	if err := row.Scan(
-		&actor.Id,
-		&actor.FirstName,
-		&actor.SecondName,
-		&actor.Gender,
-	); errors.Is(err, pgx.ErrNoRows) {
+	actor, err := scanActor(r.QueryRow(ctx, getActorQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
 		return model.Actor{}, model.ErrActorNotExists
 	} else if err != nil {
 		return model.Actor{}, errors.Join(model.ErrDatabaseError, err)
 	}
 
-	var err error
-	actor.Movies, err = r.getActorMovies(ctx, id)
-	if err != nil {
+	actors := []model.Actor{actor}
+	if err = r.loadActorsMovies(ctx, actors); err != nil {
 		return model.Actor{}, err
 	}
-	return actor, nil
+	return actors[0], nil
+}
+
+func (r *repoImpl) GetActors(ctx context.Context, filter model.ActorFilter,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
+	var b conditionBuilder
+	b.add(actorNotCrewOnlyCondition)
+	b.addActorFilter(filter)
+	return r.queryActorsPage(ctx, &b, sortBy, page)
 }
 
-func (r *repoImpl) GetActors(ctx context.Context) ([]model.Actor, error) {
-	rows, err := r.Query(ctx, getActorsQuery)
+// SearchActors finds actors whose first or second name contains every word
+// of the pattern ignoring case
+func (r *repoImpl) SearchActors(ctx context.Context, pattern string, filter model.ActorFilter,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
+	var b conditionBuilder
+	b.add(actorNotCrewOnlyCondition)
+	for _, word := range strings.Fields(pattern) {
+		b.add(actorNameContainsCondition, likeContains(word))
+	}
+	b.addActorFilter(filter)
+	return r.queryActorsPage(ctx, &b, sortBy, page)
+}
+
+// queryActorsPage returns the page of actors matching the conditions with
+// their movies, movies of all actors are loaded by one additional query
+func (r *repoImpl) queryActorsPage(ctx context.Context, b *conditionBuilder,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
+	keys, err := actorSortKeys(sortBy)
 	if err != nil {
-		return []model.Actor{}, errors.Join(model.ErrDatabaseError, err)
+		return model.ActorList{}, err
 	}
-	defer rows.Close()
 
-	actors := make([]model.Actor, 0)
-	for rows.Next() {
-		var actor model.Actor
-		_ = rows.Scan(
-			&actor.Id,
-			&actor.FirstName,
-			&actor.SecondName,
-			&actor.Gender,
-		)
-		actors = append(actors, actor)
+	condition, args := b.where()
+	actors, hasNext, total, err := selectPage(ctx, r, `"actors"`, actorColumns, keys,
+		condition, args, page, scanActor)
+	if err != nil {
+		return model.ActorList{}, err
 	}
-	for i := range actors {
-		actors[i].Movies, _ = r.getActorMovies(ctx, actors[i].Id)
+
+	if err = r.loadActorsMovies(ctx, actors); err != nil {
+		return model.ActorList{}, err
+	}
+	list := model.ActorList{
+		Actors: actors,
+		Total:  total,
 	}
-	return actors, nil
+	if hasNext {
+		list.NextCursor = encodeCursor(keys, actors[len(actors)-1])
+	}
+	return list, nil
 }
 
-func (r *repoImpl) getActorMovies(ctx context.Context, id uint64) ([]model.Movie, error) {
-	rows, err := r.Query(ctx, getActorMoviesQuery, id)
+// loadActorsMovies sets movies of all given actors using one query
+func (r *repoImpl) loadActorsMovies(ctx context.Context, actors []model.Actor) error {
+	if len(actors) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(actors))
+	byId := make(map[uint64][]model.Movie, len(actors))
+	for _, actor := range actors {
+		ids = append(ids, actor.Id)
+		byId[actor.Id] = make([]model.Movie, 0)
+	}
+
+	rows, err := r.Query(ctx, getActorsMoviesQuery, ids)
 	if err != nil {
-		return []model.Movie{}, errors.Join(model.ErrDatabaseError, err)
-	}
-	defer rows.Close()
-
-	movies := make([]model.Movie, 0)
-	for rows.Next() {
-		var movie model.Movie
-		_ = rows.Scan(
-			&movie.Id,
-			&movie.Title,
-			&movie.Description,
-			&movie.ReleaseDate,
-			&movie.Rating,
-		)
-		movies = append(movies, movie)
-	}
-	return movies, nil
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	var (
+		actorId uint64
+		movie   model.Movie
+	)
+	if _, err = pgx.ForEachRow(rows, []any{
+		&actorId,
+		&movie.Id,
+		&movie.Title,
+		&movie.Description,
+		&movie.ReleaseDate,
+		&movie.Rating,
+	}, func() error {
+		byId[actorId] = append(byId[actorId], movie)
+		return nil
+	}); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+
+	for i := range actors {
+		actors[i].Movies = byId[actors[i].Id]
+	}
+	return nil
+}
+
+func scanActor(row pgx.Row) (model.Actor, error) {
+	var actor model.Actor
+	err := row.Scan(
+		&actor.Id,
+		&actor.FirstName,
+		&actor.SecondName,
+		&actor.Gender,
+		&actor.CrewOnly,
+	)
+	return actor, err
 }
@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+	"time"
+)
+
+const (
+	apiKeyColumns = `"id", "user_id", "name", "prefix", "key_hash", "scope", "expires_at", "created_at"`
+
+	createApiKeyQuery = `
+		INSERT INTO "api_keys" ("user_id", "name", "prefix", "key_hash", "scope", "expires_at")
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns + `;`
+
+	getApiKeyQuery = `
+		SELECT ` + apiKeyColumns + ` FROM "api_keys"
+		WHERE "id" = $1;`
+
+	getApiKeyByHashQuery = `
+		SELECT ` + apiKeyColumns + ` FROM "api_keys"
+		WHERE "key_hash" = $1 AND ("expires_at" IS NULL OR "expires_at" > now());`
+
+	getApiKeysQuery = `
+		SELECT ` + apiKeyColumns + ` FROM "api_keys"
+		WHERE "user_id" = $1
+		ORDER BY "id";`
+
+	deleteApiKeyQuery = `
+		DELETE FROM "api_keys"
+		WHERE "id" = $1;`
+)
+
+func (r *repoImpl) CreateApiKey(ctx context.Context, key model.ApiKey) (model.ApiKey, error) {
+	created, err := scanApiKey(r.QueryRow(ctx, createApiKeyQuery,
+		key.UserId,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		key.Scope,
+		key.ExpiresAt,
+	))
+	if err != nil {
+		return model.ApiKey{}, mapError(err)
+	}
+	return created, nil
+}
+
+func (r *repoImpl) GetApiKey(ctx context.Context, id uint64) (model.ApiKey, error) {
+	key, err := scanApiKey(r.QueryRow(ctx, getApiKeyQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ApiKey{}, model.ErrApiKeyNotExists
+	} else if err != nil {
+		return model.ApiKey{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return key, nil
+}
+
+func (r *repoImpl) GetApiKeyByHash(ctx context.Context, hash string) (model.ApiKey, error) {
+	key, err := scanApiKey(r.QueryRow(ctx, getApiKeyByHashQuery, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ApiKey{}, model.ErrApiKeyNotExists
+	} else if err != nil {
+		return model.ApiKey{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return key, nil
+}
+
+func (r *repoImpl) GetApiKeys(ctx context.Context, userId uint64) ([]model.ApiKey, error) {
+	rows, err := r.Query(ctx, getApiKeysQuery, userId)
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ApiKey, error) {
+		return scanApiKey(row)
+	})
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	return keys, nil
+}
+
+func (r *repoImpl) DeleteApiKey(ctx context.Context, id uint64) error {
+	if e, err := r.Exec(ctx, deleteApiKeyQuery, id); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrApiKeyNotExists
+	}
+	return nil
+}
+
+func scanApiKey(row pgx.Row) (model.ApiKey, error) {
+	var (
+		key       model.ApiKey
+		expiresAt *time.Time
+	)
+	err := row.Scan(
+		&key.Id,
+		&key.UserId,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scope,
+		&expiresAt,
+		&key.CreatedAt,
+	)
+	if expiresAt != nil {
+		utc := expiresAt.UTC()
+		key.ExpiresAt = &utc
+	}
+	key.CreatedAt = key.CreatedAt.UTC()
+	return key, err
+}
@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+	"time"
+)
+
+const (
+	sessionColumns = `"id", "user_id", "refresh_token_hash", "expires_at", "created_at"`
+
+	getUserCredentialsQuery = `
+		SELECT "id", coalesce("password_hash", ''), "disabled" FROM "users"
+		WHERE lower("username") = lower($1);`
+
+	setPasswordHashQuery = `
+		UPDATE "users"
+		SET "password_hash" = nullif($2, '')
+		WHERE "id" = $1;`
+
+	deleteExpiredSessionsQuery = `
+		DELETE FROM "sessions"
+		WHERE "user_id" = $1 AND "expires_at" <= now();`
+
+	createSessionQuery = `
+		INSERT INTO "sessions" ("user_id", "refresh_token_hash", "expires_at")
+		VALUES ($1, $2, $3)
+		RETURNING ` + sessionColumns + `;`
+
+	getSessionQuery = `
+		SELECT ` + sessionColumns + ` FROM "sessions"
+		WHERE "id" = $1 AND "expires_at" > now();`
+
+	// refreshSessionQuery replaces the hash atomically, so of two concurrent
+	// refreshes with the same token only one succeeds
+	refreshSessionQuery = `
+		UPDATE "sessions"
+		SET "refresh_token_hash" = $2,
+		    "expires_at" = $3
+		WHERE "refresh_token_hash" = $1 AND "expires_at" > now()
+		RETURNING ` + sessionColumns + `;`
+
+	deleteSessionQuery = `
+		DELETE FROM "sessions"
+		WHERE "id" = $1;`
+
+	deleteUserSessionsQuery = `
+		DELETE FROM "sessions"
+		WHERE "user_id" = $1;`
+)
+
+func (r *repoImpl) GetUserCredentials(ctx context.Context, username string) (model.Credentials, error) {
+	var creds model.Credentials
+	err := r.QueryRow(ctx, getUserCredentialsQuery, username).Scan(
+		&creds.UserId,
+		&creds.PasswordHash,
+		&creds.Disabled,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Credentials{}, model.ErrUserNotExists
+	} else if err != nil {
+		return model.Credentials{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return creds, nil
+}
+
+func (r *repoImpl) SetPasswordHash(ctx context.Context, userId uint64, hash string) error {
+	if e, err := r.Exec(ctx, setPasswordHashQuery, userId, hash); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrUserNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) CreateSession(ctx context.Context, session model.Session) (model.Session, error) {
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if _, err := tx.Exec(ctx, deleteExpiredSessionsQuery, session.UserId); err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		var err error
+		session, err = scanSession(tx.QueryRow(ctx, createSessionQuery,
+			session.UserId,
+			session.RefreshTokenHash,
+			session.ExpiresAt,
+		))
+		if err != nil {
+			return mapError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Session{}, err
+	}
+	return session, nil
+}
+
+func (r *repoImpl) GetSession(ctx context.Context, id uint64) (model.Session, error) {
+	session, err := scanSession(r.QueryRow(ctx, getSessionQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Session{}, model.ErrSessionNotExists
+	} else if err != nil {
+		return model.Session{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return session, nil
+}
+
+func (r *repoImpl) RefreshSession(ctx context.Context, oldHash string, newHash string,
+	expiresAt time.Time) (model.Session, error) {
+	session, err := scanSession(r.QueryRow(ctx, refreshSessionQuery, oldHash, newHash, expiresAt))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Session{}, model.ErrSessionNotExists
+	} else if err != nil {
+		return model.Session{}, mapError(err)
+	}
+	return session, nil
+}
+
+func (r *repoImpl) DeleteSession(ctx context.Context, id uint64) error {
+	if e, err := r.Exec(ctx, deleteSessionQuery, id); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrSessionNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) DeleteUserSessions(ctx context.Context, userId uint64) error {
+	if _, err := r.Exec(ctx, deleteUserSessionsQuery, userId); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	return nil
+}
+
+func scanSession(row pgx.Row) (model.Session, error) {
+	var session model.Session
+	err := row.Scan(
+		&session.Id,
+		&session.UserId,
+		&session.RefreshTokenHash,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+	)
+	session.ExpiresAt = session.ExpiresAt.UTC()
+	session.CreatedAt = session.CreatedAt.UTC()
+	return session, err
+}
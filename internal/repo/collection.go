@@ -0,0 +1,350 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+)
+
+const (
+	collectionColumns = `"collections"."id", "collections"."owner_id", "collections"."name",
+		"collections"."description", "collections"."visibility", "collections"."created_at"`
+
+	createCollectionQuery = `
+		INSERT INTO "collections" ("owner_id", "name", "description", "visibility")
+		VALUES ($1, $2, $3, $4)
+		RETURNING "id";`
+
+	updateCollectionQuery = `
+		UPDATE "collections"
+		SET "name" = $2,
+		    "description" = $3,
+		    "visibility" = $4
+		WHERE "id" = $1;`
+
+	deleteCollectionQuery = `
+		DELETE FROM "collections"
+		WHERE "id" = $1;`
+
+	getCollectionQuery = `
+		SELECT ` + collectionColumns + ` FROM "collections"
+		WHERE "id" = $1;`
+
+	// lockCollectionQuery locks the collection until the end of the
+	// transaction, so its entries are not changed concurrently
+	lockCollectionQuery = `
+		SELECT "id" FROM "collections"
+		WHERE "id" = $1
+		FOR UPDATE;`
+
+	shareCollectionQuery = `
+		INSERT INTO "collection-user" ("collection_id", "user_id")
+		SELECT $1, unnest($2::bigint[])
+		ON CONFLICT DO NOTHING;`
+
+	unshareCollectionQuery = `
+		DELETE FROM "collection-user"
+		WHERE "collection_id" = $1;`
+
+	getCollectionUsersQuery = `
+		SELECT "user_id" FROM "collection-user"
+		WHERE "collection_id" = $1
+		ORDER BY "user_id";`
+
+	getCollectionEntriesQuery = `
+		SELECT ` + movieColumns + `, "collection-movie"."note"
+		FROM "collection-movie"
+			INNER JOIN "movies" ON "collection-movie"."movie_id" = "movies"."id"
+		WHERE "collection-movie"."collection_id" = $1
+		ORDER BY "collection-movie"."position", "movies"."id";`
+
+	// addCollectionEntryQuery appends the movie to the end of the collection
+	addCollectionEntryQuery = `
+		INSERT INTO "collection-movie" ("collection_id", "movie_id", "position", "note")
+		SELECT $1, $2, coalesce(max("position"), 0) + 1, $3
+		FROM "collection-movie"
+		WHERE "collection_id" = $1;`
+
+	updateCollectionEntryQuery = `
+		UPDATE "collection-movie"
+		SET "note" = $3
+		WHERE "collection_id" = $1 AND "movie_id" = $2;`
+
+	removeCollectionEntryQuery = `
+		DELETE FROM "collection-movie"
+		WHERE "collection_id" = $1 AND "movie_id" = $2;`
+
+	getCollectionMoviesIdsQuery = `
+		SELECT "movie_id" FROM "collection-movie"
+		WHERE "collection_id" = $1;`
+
+	// reorderCollectionQuery sets positions of the entries to positions of
+	// their movies in $2
+	reorderCollectionQuery = `
+		UPDATE "collection-movie"
+		SET "position" = "order"."position"
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS "order" ("movie_id", "position")
+		WHERE "collection-movie"."collection_id" = $1 AND
+			"collection-movie"."movie_id" = "order"."movie_id";`
+
+	// collectionReadableCondition selects collections which the user $1 can
+	// read: own, public and shared with the user
+	collectionReadableCondition = `("collections"."owner_id" = $%[1]d OR
+		"collections"."visibility" = 'public' OR
+		EXISTS (
+			SELECT 1 FROM "collection-user"
+			WHERE "collection-user"."collection_id" = "collections"."id" AND
+				"collection-user"."user_id" = $%[1]d))`
+)
+
+func (r *repoImpl) CreateCollection(ctx context.Context, collection model.Collection) (model.Collection, error) {
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if err := tx.QueryRow(ctx, createCollectionQuery,
+			collection.OwnerId,
+			collection.Name,
+			collection.Description,
+			collection.Visibility,
+		).Scan(&collection.Id); err != nil {
+			return mapError(err)
+		}
+		if err := tx.shareCollection(ctx, collection.Id, collection.SharedWith); err != nil {
+			return err
+		}
+
+		var err error
+		collection, err = tx.GetCollection(ctx, collection.Id)
+		return err
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+	return collection, nil
+}
+
+func (r *repoImpl) UpdateCollection(ctx context.Context, id uint64, upd model.UpdateCollection) (model.Collection, error) {
+	var collection model.Collection
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if e, err := tx.Exec(ctx, updateCollectionQuery,
+			id,
+			upd.Name,
+			upd.Description,
+			upd.Visibility,
+		); err != nil {
+			return mapError(err)
+		} else if e.RowsAffected() == 0 {
+			return model.ErrCollectionNotExists
+		}
+
+		if _, err := tx.Exec(ctx, unshareCollectionQuery, id); err != nil {
+			return mapError(err)
+		}
+		if err := tx.shareCollection(ctx, id, upd.SharedWith); err != nil {
+			return err
+		}
+
+		var err error
+		collection, err = tx.GetCollection(ctx, id)
+		return err
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+	return collection, nil
+}
+
+// DeleteCollection deletes collection, its entries and shares are deleted by
+// cascade
+func (r *repoImpl) DeleteCollection(ctx context.Context, id uint64) error {
+	if e, err := r.Exec(ctx, deleteCollectionQuery, id); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrCollectionNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) GetCollection(ctx context.Context, id uint64) (model.Collection, error) {
+	collection, err := scanCollection(r.QueryRow(ctx, getCollectionQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Collection{}, model.ErrCollectionNotExists
+	} else if err != nil {
+		return model.Collection{}, errors.Join(model.ErrDatabaseError, err)
+	}
+
+	rows, err := r.Query(ctx, getCollectionUsersQuery, id)
+	if err != nil {
+		return model.Collection{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	if collection.SharedWith, err = pgx.CollectRows(rows, pgx.RowTo[uint64]); err != nil {
+		return model.Collection{}, errors.Join(model.ErrDatabaseError, err)
+	}
+
+	rows, err = r.Query(ctx, getCollectionEntriesQuery, id)
+	if err != nil {
+		return model.Collection{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	var (
+		movie model.Movie
+		note  string
+	)
+	movies := make([]model.Movie, 0)
+	notes := make([]string, 0)
+	if _, err = pgx.ForEachRow(rows, []any{
+		&movie.Id,
+		&movie.Title,
+		&movie.Description,
+		&movie.ReleaseDate,
+		&movie.Rating,
+		&note,
+	}, func() error {
+		movies = append(movies, movie)
+		notes = append(notes, note)
+		return nil
+	}); err != nil {
+		return model.Collection{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	if err = r.loadMoviesRelations(ctx, movies); err != nil {
+		return model.Collection{}, err
+	}
+
+	collection.Entries = make([]model.CollectionEntry, 0, len(movies))
+	for i, movie := range movies {
+		collection.Entries = append(collection.Entries, model.CollectionEntry{
+			MovieId: movie.Id,
+			Movie:   movie,
+			Note:    notes[i],
+		})
+	}
+	return collection, nil
+}
+
+func (r *repoImpl) GetCollections(ctx context.Context, filter model.CollectionFilter,
+	page model.Page) (model.CollectionList, error) {
+	var b conditionBuilder
+	b.addCollectionFilter(filter)
+	condition, args := b.where()
+
+	keys := collectionSortKeys()
+	collections, hasNext, total, err := selectPage(ctx, r, `"collections"`, collectionColumns, keys,
+		condition, args, page, scanCollection)
+	if err != nil {
+		return model.CollectionList{}, err
+	}
+	list := model.CollectionList{
+		Collections: collections,
+		Total:       total,
+	}
+	if hasNext {
+		list.NextCursor = encodeCursor(keys, collections[len(collections)-1])
+	}
+	return list, nil
+}
+
+func (r *repoImpl) AddCollectionEntry(ctx context.Context, collectionId uint64, entry model.CollectionEntry) error {
+	return r.inTx(ctx, func(tx *repoImpl) error {
+		if err := tx.lockCollection(ctx, collectionId); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, addCollectionEntryQuery, collectionId, entry.MovieId, entry.Note); err != nil {
+			return mapError(err)
+		}
+		return nil
+	})
+}
+
+func (r *repoImpl) UpdateCollectionEntry(ctx context.Context, collectionId uint64, entry model.CollectionEntry) error {
+	if e, err := r.Exec(ctx, updateCollectionEntryQuery, collectionId, entry.MovieId, entry.Note); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrEntryNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) RemoveCollectionEntry(ctx context.Context, collectionId, movieId uint64) error {
+	if e, err := r.Exec(ctx, removeCollectionEntryQuery, collectionId, movieId); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrEntryNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) ReorderCollection(ctx context.Context, collectionId uint64, moviesId []uint64) error {
+	return r.inTx(ctx, func(tx *repoImpl) error {
+		if err := tx.lockCollection(ctx, collectionId); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, getCollectionMoviesIdsQuery, collectionId)
+		if err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		entries, err := pgx.CollectRows(rows, pgx.RowTo[uint64])
+		if err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		if !isPermutation(entries, moviesId) {
+			return model.ErrValidationError
+		}
+
+		if _, err = tx.Exec(ctx, reorderCollectionQuery, collectionId, moviesId); err != nil {
+			return mapError(err)
+		}
+		return nil
+	})
+}
+
+func (r *repoImpl) lockCollection(ctx context.Context, id uint64) error {
+	var lockedId uint64
+	if err := r.QueryRow(ctx, lockCollectionQuery, id).Scan(&lockedId); errors.Is(err, pgx.ErrNoRows) {
+		return model.ErrCollectionNotExists
+	} else if err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	return nil
+}
+
+func (r *repoImpl) shareCollection(ctx context.Context, id uint64, usersId []uint64) error {
+	if len(usersId) == 0 {
+		return nil
+	}
+	if _, err := r.Exec(ctx, shareCollectionQuery, id, usersId); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
+
+// isPermutation checks whether ids contain every id of entries exactly once
+// and nothing else
+func isPermutation(entries, ids []uint64) bool {
+	if len(entries) != len(ids) {
+		return false
+	}
+	count := make(map[uint64]int, len(entries))
+	for _, id := range entries {
+		count[id]++
+	}
+	for _, id := range ids {
+		if count[id] == 0 {
+			return false
+		}
+		count[id]--
+	}
+	return true
+}
+
+func scanCollection(row pgx.Row) (model.Collection, error) {
+	var collection model.Collection
+	err := row.Scan(
+		&collection.Id,
+		&collection.OwnerId,
+		&collection.Name,
+		&collection.Description,
+		&collection.Visibility,
+		&collection.CreatedAt,
+	)
+	collection.CreatedAt = collection.CreatedAt.UTC()
+	return collection, err
+}
@@ -0,0 +1,193 @@
+package repo
+
+import (
+	"fmt"
+	"movie-lib/internal/model"
+	"strings"
+)
+
+const (
+	// movieHasActorsCondition selects movies with any of the actors $1
+	movieHasActorsCondition = `EXISTS (
+		SELECT 1 FROM "movie-actor"
+		WHERE "movie-actor"."movie-id" = "movies"."id" AND
+			"movie-actor"."actor_id" = ANY($%[1]d::bigint[]))`
+
+	// movieHasAllActorsCondition selects movies with all the actors $1, $2 is
+	// a number of distinct actors in $1
+	movieHasAllActorsCondition = `(
+		SELECT count(*) FROM "movie-actor"
+		WHERE "movie-actor"."movie-id" = "movies"."id" AND
+			"movie-actor"."actor_id" = ANY($%[1]d::bigint[])) = $%[2]d`
+
+	// movieHasGenresCondition selects movies with any of the genres $1
+	movieHasGenresCondition = `EXISTS (
+		SELECT 1 FROM "movie-genre"
+		WHERE "movie-genre"."movie_id" = "movies"."id" AND
+			"movie-genre"."genre_id" = ANY($%[1]d::bigint[]))`
+
+	// movieHasAllGenresCondition selects movies with all the genres $1, $2 is
+	// a number of distinct genres in $1
+	movieHasAllGenresCondition = `(
+		SELECT count(*) FROM "movie-genre"
+		WHERE "movie-genre"."movie_id" = "movies"."id" AND
+			"movie-genre"."genre_id" = ANY($%[1]d::bigint[])) = $%[2]d`
+
+	// actorNameContainsCondition selects actors whose first or second name
+	// contains the word, $1 is a LIKE pattern
+	actorNameContainsCondition = `("actors"."first_name" ILIKE $%[1]d OR "actors"."second_name" ILIKE $%[1]d)`
+
+	// actorInMovieCondition selects actors who appeared in the movie $1
+	actorInMovieCondition = `EXISTS (
+		SELECT 1 FROM "movie-actor"
+		WHERE "movie-actor"."actor_id" = "actors"."id" AND
+			"movie-actor"."movie-id" = $%[1]d)`
+
+	// movieInWatchlistCondition selects movies in the watchlist of the user $1
+	movieInWatchlistCondition = `EXISTS (
+		SELECT 1 FROM "watchlist"
+		WHERE "watchlist"."movie_id" = "movies"."id" AND
+			"watchlist"."user_id" = $%[1]d)`
+
+	// movieWatchedCondition selects movies watched by the user $1
+	movieWatchedCondition = `EXISTS (
+		SELECT 1 FROM "watched"
+		WHERE "watched"."movie_id" = "movies"."id" AND
+			"watched"."user_id" = $%[1]d)`
+
+	// movieHasActorOfGenderCondition selects movies with an actor of gender $1
+	movieHasActorOfGenderCondition = `EXISTS (
+		SELECT 1 FROM "movie-actor"
+			INNER JOIN "actors" ON "actors"."id" = "movie-actor"."actor_id"
+		WHERE "movie-actor"."movie-id" = "movies"."id" AND
+			"actors"."gender" = $%[1]d)`
+)
+
+// conditionBuilder joins SQL conditions with AND and numbers their arguments
+type conditionBuilder struct {
+	conditions []string
+	args       []any
+}
+
+// add adds the condition, the condition refers to its arguments by indexed
+// verbs %[1]d, %[2]d and so on which are replaced by numbers of the arguments
+// in the query
+func (b *conditionBuilder) add(condition string, args ...any) {
+	numbers := make([]any, 0, len(args))
+	for _, arg := range args {
+		b.args = append(b.args, arg)
+		numbers = append(numbers, len(b.args))
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(condition, numbers...))
+}
+
+// addArg adds the argument which is used by several parts of the query and
+// returns its number
+func (b *conditionBuilder) addArg(arg any) int {
+	b.args = append(b.args, arg)
+	return len(b.args)
+}
+
+func (b *conditionBuilder) where() (string, []any) {
+	if len(b.conditions) == 0 {
+		return "TRUE", b.args
+	}
+	return strings.Join(b.conditions, " AND "), b.args
+}
+
+func (b *conditionBuilder) addMovieFilter(filter model.MovieFilter) {
+	if filter.ReleaseDateFrom != nil {
+		b.add(`"movies"."release_date" >= $%[1]d`, *filter.ReleaseDateFrom)
+	}
+	if filter.ReleaseDateTo != nil {
+		b.add(`"movies"."release_date" <= $%[1]d`, *filter.ReleaseDateTo)
+	}
+	if filter.RatingFrom != nil {
+		b.add(`"movies"."rating" >= $%[1]d`, *filter.RatingFrom)
+	}
+	if filter.RatingTo != nil {
+		b.add(`"movies"."rating" <= $%[1]d`, *filter.RatingTo)
+	}
+	if len(filter.ActorsId) != 0 {
+		actorsId := uniqueIds(filter.ActorsId)
+		if filter.ActorsMatch == model.MatchAll {
+			b.add(movieHasAllActorsCondition, actorsId, len(actorsId))
+		} else {
+			b.add(movieHasActorsCondition, actorsId)
+		}
+	}
+	if filter.ActorGender != model.Unknown {
+		b.add(movieHasActorOfGenderCondition, string(filter.ActorGender))
+	}
+	if len(filter.GenresId) != 0 {
+		genresId := uniqueIds(filter.GenresId)
+		if filter.GenresMatch == model.MatchAll {
+			b.add(movieHasAllGenresCondition, genresId, len(genresId))
+		} else {
+			b.add(movieHasGenresCondition, genresId)
+		}
+	}
+	if filter.InWatchlist != nil {
+		b.add(negateIf(!*filter.InWatchlist, movieInWatchlistCondition), filter.UserId)
+	}
+	if filter.Watched != nil {
+		b.add(negateIf(!*filter.Watched, movieWatchedCondition), filter.UserId)
+	}
+}
+
+func (b *conditionBuilder) addCollectionFilter(filter model.CollectionFilter) {
+	if filter.OwnerId != 0 {
+		b.add(`"collections"."owner_id" = $%[1]d`, filter.OwnerId)
+	}
+	if filter.ReaderId != 0 {
+		b.add(collectionReadableCondition, filter.ReaderId)
+	}
+}
+
+// negateIf returns the negation of the condition if negate is true
+func negateIf(negate bool, condition string) string {
+	if negate {
+		return "NOT " + condition
+	}
+	return condition
+}
+
+func (b *conditionBuilder) addActorFilter(filter model.ActorFilter) {
+	if filter.Gender != model.Unknown {
+		b.add(`"actors"."gender" = $%[1]d`, string(filter.Gender))
+	}
+	if filter.MovieId != 0 {
+		b.add(actorInMovieCondition, filter.MovieId)
+	}
+}
+
+func (b *conditionBuilder) addReviewFilter(filter model.ReviewFilter) {
+	if filter.MovieId != 0 {
+		b.add(`"reviews"."movie_id" = $%[1]d`, filter.MovieId)
+	}
+	if filter.UserId != 0 {
+		b.add(`"reviews"."user_id" = $%[1]d`, filter.UserId)
+	}
+}
+
+// likeContains returns the LIKE pattern matching strings which contain s
+func likeContains(s string) string {
+	return "%" + escapeLike(s) + "%"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// uniqueIds returns ids without duplicates keeping their order
+func uniqueIds(ids []uint64) []uint64 {
+	seen := make(map[uint64]struct{}, len(ids))
+	res := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			res = append(res, id)
+		}
+	}
+	return res
+}
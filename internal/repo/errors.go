@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"movie-lib/internal/model"
+)
+
+// codes of PostgreSQL errors
+const (
+	notNullViolation    = "23502"
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	checkViolation      = "23514"
+	stringTooLong       = "22001"
+)
+
+// constraintErrors maps names of constraints to errors returned on their
+// violation
+var constraintErrors = map[string]error{
+	"movie-actor_movie-id_fkey": model.ErrMovieNotExists,
+	"movie-actor_actor_id_fkey": model.ErrActorNotExists,
+	"movie-genre_movie_id_fkey": model.ErrMovieNotExists,
+	"movie-genre_genre_id_fkey": model.ErrGenreNotExists,
+	"movie-crew_movie_id_fkey":  model.ErrMovieNotExists,
+	"movie-crew_person_id_fkey": model.ErrPersonNotExists,
+	"reviews_movie_id_fkey":     model.ErrMovieNotExists,
+	"reviews_user_id_fkey":      model.ErrUserNotExists,
+	"watchlist_user_id_fkey":    model.ErrUserNotExists,
+	"watchlist_movie_id_fkey":   model.ErrMovieNotExists,
+	"watched_user_id_fkey":      model.ErrUserNotExists,
+	"watched_movie_id_fkey":     model.ErrMovieNotExists,
+
+	"collections_owner_id_fkey":           model.ErrUserNotExists,
+	"collection-movie_collection_id_fkey": model.ErrCollectionNotExists,
+	"collection-movie_movie_id_fkey":      model.ErrMovieNotExists,
+	"collection-user_collection_id_fkey":  model.ErrCollectionNotExists,
+	// the collection is shared with a user who does not exist
+	"collection-user_user_id_fkey": model.ErrValidationError,
+
+	"sessions_user_id_fkey": model.ErrUserNotExists,
+	"api_keys_user_id_fkey": model.ErrUserNotExists,
+	"users_role_fkey":       model.ErrRoleNotExists,
+}
+
+// mapError converts PostgreSQL constraint violations to model errors, all
+// other errors are joined with model.ErrDatabaseError
+func mapError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+
+	if e, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return errors.Join(e, err)
+	}
+	switch pgErr.Code {
+	case foreignKeyViolation, uniqueViolation:
+		return errors.Join(model.ErrConflict, err)
+	case notNullViolation, checkViolation, stringTooLong:
+		return errors.Join(model.ErrValidationError, err)
+	default:
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+}
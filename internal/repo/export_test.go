@@ -0,0 +1,8 @@
+package repo
+
+// SuggestQuery and SuggestCandidatesLimit are used to check the plan of the
+// suggestions in tests
+const (
+	SuggestQuery           = suggestQuery
+	SuggestCandidatesLimit = suggestCandidatesLimit
+)
@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+)
+
+const (
+	genreColumns = `"genres"."id", "genres"."name"`
+
+	createGenreQuery = `
+		INSERT INTO "genres" ("name")
+		VALUES ($1)
+		RETURNING "id";`
+
+	updateGenreQuery = `
+		UPDATE "genres"
+		SET "name" = $2
+		WHERE "id" = $1;`
+
+	deleteGenreQuery = `
+		DELETE FROM "genres"
+		WHERE "id" = $1;`
+
+	getGenreQuery = `
+		SELECT ` + genreColumns + ` FROM "genres"
+		WHERE "id" = $1;`
+
+	// genres are ordered by byte values of names, so the order does not
+	// depend on the collation of the database
+	getGenresQuery = `
+		SELECT ` + genreColumns + ` FROM "genres"
+		ORDER BY "genres"."name" COLLATE "C", "genres"."id";`
+
+	addGenresToMovieQuery = `
+		INSERT INTO "movie-genre" ("movie_id", "genre_id")
+		SELECT $1, unnest($2::bigint[])
+		ON CONFLICT DO NOTHING;`
+
+	deleteMovieFromGenresQuery = `
+		DELETE FROM "movie-genre"
+		WHERE "movie_id" = $1;`
+
+	// getMoviesGenresQuery loads genres of several movies at once
+	getMoviesGenresQuery = `
+		SELECT "movie-genre"."movie_id", ` + genreColumns + `
+		FROM "movie-genre"
+			INNER JOIN "genres" ON "movie-genre"."genre_id" = "genres"."id"
+		WHERE "movie-genre"."movie_id" = ANY($1::bigint[])
+		ORDER BY "genres"."name" COLLATE "C", "genres"."id";`
+)
+
+func (r *repoImpl) CreateGenre(ctx context.Context, genre model.Genre) (model.Genre, error) {
+	if err := r.QueryRow(ctx, createGenreQuery, genre.Name).Scan(&genre.Id); err != nil {
+		return model.Genre{}, mapError(err)
+	}
+	return genre, nil
+}
+
+func (r *repoImpl) UpdateGenre(ctx context.Context, id uint64, upd model.UpdateGenre) (model.Genre, error) {
+	if e, err := r.Exec(ctx, updateGenreQuery, id, upd.Name); err != nil {
+		return model.Genre{}, mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.Genre{}, model.ErrGenreNotExists
+	}
+	return model.Genre{
+		Id:   id,
+		Name: upd.Name,
+	}, nil
+}
+
+// DeleteGenre deletes genre, its links to movies are deleted by cascade
+func (r *repoImpl) DeleteGenre(ctx context.Context, id uint64) error {
+	if e, err := r.Exec(ctx, deleteGenreQuery, id); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrGenreNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) GetGenre(ctx context.Context, id uint64) (model.Genre, error) {
+	genre, err := scanGenre(r.QueryRow(ctx, getGenreQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Genre{}, model.ErrGenreNotExists
+	} else if err != nil {
+		return model.Genre{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return genre, nil
+}
+
+func (r *repoImpl) GetGenres(ctx context.Context) ([]model.Genre, error) {
+	rows, err := r.Query(ctx, getGenresQuery)
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	genres, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Genre, error) {
+		return scanGenre(row)
+	})
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	return genres, nil
+}
+
+// loadMoviesGenres sets genres of all given movies using one query
+func (r *repoImpl) loadMoviesGenres(ctx context.Context, movies []model.Movie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(movies))
+	byId := make(map[uint64][]model.Genre, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+		byId[movie.Id] = make([]model.Genre, 0)
+	}
+
+	rows, err := r.Query(ctx, getMoviesGenresQuery, ids)
+	if err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	var (
+		movieId uint64
+		genre   model.Genre
+	)
+	if _, err = pgx.ForEachRow(rows, []any{
+		&movieId,
+		&genre.Id,
+		&genre.Name,
+	}, func() error {
+		byId[movieId] = append(byId[movieId], genre)
+		return nil
+	}); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+
+	for i := range movies {
+		movies[i].Genres = byId[movies[i].Id]
+	}
+	return nil
+}
+
+func (r *repoImpl) addMovieGenres(ctx context.Context, movieId uint64, genresId []uint64) error {
+	if len(genresId) == 0 {
+		return nil
+	}
+	if _, err := r.Exec(ctx, addGenresToMovieQuery, movieId, genresId); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
+
+func scanGenre(row pgx.Row) (model.Genre, error) {
+	var genre model.Genre
+	err := row.Scan(
+		&genre.Id,
+		&genre.Name,
+	)
+	return genre, err
+}
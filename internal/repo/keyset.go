@@ -0,0 +1,240 @@
+package repo
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortKey is one column of the order used by keyset pagination. Every order
+// ends with the id key, so rows are never tied and pages are stable.
+type sortKey[T any] struct {
+	name   string // name of the key stored in the cursor
+	column string // SQL expression of the key
+	desc   bool
+
+	value  func(item T) any                       // value of the key for the item
+	decode func(raw json.RawMessage) (any, error) // decodes the value from the cursor
+}
+
+// cursor is a position after the last row of the page
+type cursor struct {
+	Order  string            `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// orderName identifies the order, so a cursor of one order cannot be used
+// with another one
+func orderName[T any](keys []sortKey[T]) string {
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			names = append(names, "-"+key.name)
+		} else {
+			names = append(names, key.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func encodeCursor[T any](keys []sortKey[T], item T) string {
+	c := cursor{
+		Order:  orderName(keys),
+		Values: make([]json.RawMessage, 0, len(keys)),
+	}
+	for _, key := range keys {
+		raw, _ := json.Marshal(key.value(item))
+		c.Values = append(c.Values, raw)
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns values of the keys stored in the cursor or nil if the
+// cursor is empty
+func decodeCursor[T any](keys []sortKey[T], s string) ([]any, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Join(model.ErrInvalidCursor, err)
+	}
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Join(model.ErrInvalidCursor, err)
+	}
+	if c.Order != orderName(keys) || len(c.Values) != len(keys) {
+		return nil, model.ErrInvalidCursor
+	}
+
+	values := make([]any, 0, len(keys))
+	for i, key := range keys {
+		value, err := key.decode(c.Values[i])
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidCursor, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func decodeAs[V any](raw json.RawMessage) (any, error) {
+	var v V
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// orderByClause returns the ORDER BY list for the keys
+func orderByClause[T any](keys []sortKey[T]) string {
+	columns := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.desc {
+			columns = append(columns, key.column+" DESC")
+		} else {
+			columns = append(columns, key.column)
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// afterCursorCondition returns SQL condition selecting rows placed after the
+// cursor values. Arguments are numbered from firstArg and appended to args.
+//
+// For keys (a, b DESC, id) the condition is
+// a > $1 OR (a = $1 AND b < $2) OR (a = $1 AND b = $2 AND id > $3).
+func afterCursorCondition[T any](keys []sortKey[T], values []any, firstArg int, args []any) (string, []any) {
+	alternatives := make([]string, 0, len(keys))
+	for i, key := range keys {
+		conjuncts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conjuncts = append(conjuncts, fmt.Sprintf("%s = $%d", keys[j].column, firstArg+j))
+		}
+		op := ">"
+		if key.desc {
+			op = "<"
+		}
+		conjuncts = append(conjuncts, fmt.Sprintf("%s %s $%d", key.column, op, firstArg+i))
+		alternatives = append(alternatives, "("+strings.Join(conjuncts, " AND ")+")")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", append(args, values...)
+}
+
+// selectPage selects the page of rows of the table matching the condition in
+// the order of the keys and counts all matching rows. One more row than the
+// limit is selected to find out whether the next page exists, the cursor of
+// the next page should be encoded by the caller after relations used by keys
+// are loaded.
+func selectPage[T any](ctx context.Context, r *repoImpl, table, columns string, keys []sortKey[T],
+	condition string, args []any, page model.Page, scan func(row pgx.Row) (T, error)) ([]T, bool, uint64, error) {
+	values, err := decodeCursor(keys, page.Cursor)
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	var total uint64
+	countQuery := fmt.Sprintf(`SELECT count(*) FROM %s WHERE %s;`, table, condition)
+	if err = r.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, false, 0, errors.Join(model.ErrDatabaseError, err)
+	}
+
+	pageArgs := append(make([]any, 0, len(args)+len(keys)+1), args...)
+	if values != nil {
+		var after string
+		after, pageArgs = afterCursorCondition(keys, values, len(pageArgs)+1, pageArgs)
+		condition = "(" + condition + ") AND " + after
+	}
+	pageArgs = append(pageArgs, page.Limit+1)
+	pageQuery := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT $%d;`,
+		columns, table, condition, orderByClause(keys), len(pageArgs))
+
+	rows, err := r.Query(ctx, pageQuery, pageArgs...)
+	if err != nil {
+		return nil, false, 0, errors.Join(model.ErrDatabaseError, err)
+	}
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (T, error) {
+		return scan(row)
+	})
+	if err != nil {
+		return nil, false, 0, errors.Join(model.ErrDatabaseError, err)
+	}
+
+	if len(items) <= page.Limit {
+		return items, false, total, nil
+	}
+	return items[:page.Limit], true, total, nil
+}
+
+// compareByKeys compares key values of two rows in the order of the keys
+func compareByKeys[T any](keys []sortKey[T], a, b []any) int {
+	for i, key := range keys {
+		c := compareValues(a[i], b[i])
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func keyValues[T any](keys []sortKey[T], item T) []any {
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, key.value(item))
+	}
+	return values
+}
+
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case uint64:
+		return cmp.Compare(a, b.(uint64))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	panic(fmt.Sprintf("unsupported sort key type %T", a))
+}
+
+// sortByKeys sorts items in the order of the keys
+func sortByKeys[T any](keys []sortKey[T], items []T) {
+	sort.Slice(items, func(i, j int) bool {
+		return compareByKeys(keys, keyValues(keys, items[i]), keyValues(keys, items[j])) < 0
+	})
+}
+
+// paginate returns the page of sorted items placed after the cursor and the
+// cursor of the next page, it is used by the in-memory repository
+func paginate[T any](keys []sortKey[T], items []T, page model.Page) ([]T, string, error) {
+	values, err := decodeCursor(keys, page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	start := 0
+	if values != nil {
+		for start < len(items) && compareByKeys(keys, keyValues(keys, items[start]), values) <= 0 {
+			start++
+		}
+	}
+	items = items[start:]
+
+	if len(items) <= page.Limit {
+		return items, "", nil
+	}
+	return items[:page.Limit], encodeCursor(keys, items[page.Limit-1]), nil
+}
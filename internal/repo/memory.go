@@ -0,0 +1,362 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"slices"
+	"sync"
+	"time"
+)
+
+// movieActorLink is a row of the "movie-actor" table
+type movieActorLink struct {
+	movieId    uint64
+	actorId    uint64
+	characters []string
+	billing    int
+}
+
+// movieGenreLink is a row of the "movie-genre" table
+type movieGenreLink struct {
+	movieId uint64
+	genreId uint64
+}
+
+// movieCrewLink is a row of the "movie-crew" table
+type movieCrewLink struct {
+	movieId  uint64
+	personId uint64
+	role     model.CreditRole
+}
+
+// userMovieKey is a primary key of the "watchlist" and "watched" tables
+type userMovieKey struct {
+	userId  uint64
+	movieId uint64
+}
+
+// collectionMovieLink is a row of the "collection-movie" table
+type collectionMovieLink struct {
+	collectionId uint64
+	movieId      uint64
+	position     int
+	note         string
+}
+
+// collectionUserKey is a primary key of the "collection-user" table
+type collectionUserKey struct {
+	collectionId uint64
+	userId       uint64
+}
+
+// memoryStore keeps all tables of the in-memory repository
+type memoryStore struct {
+	movies      map[uint64]model.Movie
+	actors      map[uint64]model.Actor
+	movieActors []movieActorLink
+	genres      map[uint64]model.Genre
+	movieGenres []movieGenreLink
+	movieCrew   []movieCrewLink
+	reviews     map[uint64]model.Review
+	watchlist   map[userMovieKey]struct{}
+	watched     map[userMovieKey]time.Time
+	users       map[uint64]model.User
+	passwords   map[uint64]string
+	sessions    map[uint64]model.Session
+	apiKeys     map[uint64]model.ApiKey
+	roles       map[model.Role][]model.Permission
+
+	collections      map[uint64]model.Collection
+	collectionMovies []collectionMovieLink
+	collectionUsers  map[collectionUserKey]struct{}
+
+	lastMovieId      uint64
+	lastActorId      uint64
+	lastGenreId      uint64
+	lastReviewId     uint64
+	lastCollectionId uint64
+	lastUserId       uint64
+	lastSessionId    uint64
+	lastApiKeyId     uint64
+}
+
+// memoryRepo is a thread-safe implementation of Repo which keeps all data
+// in memory, so it can be used for tests and demo mode without a database
+type memoryRepo struct {
+	mu sync.RWMutex
+	s  *memoryStore
+}
+
+// NewMemory creates in-memory Repo with the same default users as the
+// database migrations: admin user1 with id 1 and regular user2 with id 2
+func NewMemory() Repo {
+	createdAt := now()
+	return &memoryRepo{
+		s: &memoryStore{
+			movies:      make(map[uint64]model.Movie),
+			actors:      make(map[uint64]model.Actor),
+			movieActors: make([]movieActorLink, 0),
+			genres:      make(map[uint64]model.Genre),
+			movieGenres: make([]movieGenreLink, 0),
+			movieCrew:   make([]movieCrewLink, 0),
+			reviews:     make(map[uint64]model.Review),
+			watchlist:   make(map[userMovieKey]struct{}),
+			watched:     make(map[userMovieKey]time.Time),
+			users: map[uint64]model.User{
+				1: {Id: 1, Username: "user1", Role: model.Admin, CreatedAt: createdAt},
+				2: {Id: 2, Username: "user2", Role: model.Regular, CreatedAt: createdAt},
+			},
+			passwords:        make(map[uint64]string),
+			sessions:         make(map[uint64]model.Session),
+			apiKeys:          make(map[uint64]model.ApiKey),
+			roles:            defaultRoles(),
+			collections:      make(map[uint64]model.Collection),
+			collectionMovies: make([]collectionMovieLink, 0),
+			collectionUsers:  make(map[collectionUserKey]struct{}),
+			lastUserId:       2,
+		},
+	}
+}
+
+func (r *memoryRepo) WithTx(_ context.Context, fn func(r Repo) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// transaction works with the copy of the data which replaces the original
+	// data on commit, so concurrent transactions are serialized by the lock
+	tx := &memoryRepo{s: r.s.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.s = tx.s
+	return nil
+}
+
+// clone returns deep copy of the store
+func (s *memoryStore) clone() *memoryStore {
+	c := &memoryStore{
+		movies:       make(map[uint64]model.Movie, len(s.movies)),
+		actors:       make(map[uint64]model.Actor, len(s.actors)),
+		movieActors:  make([]movieActorLink, len(s.movieActors)),
+		genres:       make(map[uint64]model.Genre, len(s.genres)),
+		movieGenres:  make([]movieGenreLink, len(s.movieGenres)),
+		movieCrew:    make([]movieCrewLink, len(s.movieCrew)),
+		reviews:      make(map[uint64]model.Review, len(s.reviews)),
+		watchlist:    make(map[userMovieKey]struct{}, len(s.watchlist)),
+		watched:      make(map[userMovieKey]time.Time, len(s.watched)),
+		users:        make(map[uint64]model.User, len(s.users)),
+		passwords:    make(map[uint64]string, len(s.passwords)),
+		sessions:     make(map[uint64]model.Session, len(s.sessions)),
+		apiKeys:      make(map[uint64]model.ApiKey, len(s.apiKeys)),
+		roles:        make(map[model.Role][]model.Permission, len(s.roles)),
+		lastMovieId:  s.lastMovieId,
+		lastActorId:  s.lastActorId,
+		lastGenreId:  s.lastGenreId,
+		lastReviewId: s.lastReviewId,
+
+		collections:      make(map[uint64]model.Collection, len(s.collections)),
+		collectionMovies: make([]collectionMovieLink, len(s.collectionMovies)),
+		collectionUsers:  make(map[collectionUserKey]struct{}, len(s.collectionUsers)),
+		lastCollectionId: s.lastCollectionId,
+		lastUserId:       s.lastUserId,
+		lastSessionId:    s.lastSessionId,
+		lastApiKeyId:     s.lastApiKeyId,
+	}
+	for id, movie := range s.movies {
+		c.movies[id] = movie
+	}
+	for id, actor := range s.actors {
+		c.actors[id] = actor
+	}
+	copy(c.movieActors, s.movieActors)
+	for id, genre := range s.genres {
+		c.genres[id] = genre
+	}
+	copy(c.movieGenres, s.movieGenres)
+	copy(c.movieCrew, s.movieCrew)
+	for id, review := range s.reviews {
+		c.reviews[id] = review
+	}
+	for key := range s.watchlist {
+		c.watchlist[key] = struct{}{}
+	}
+	for key, watchedAt := range s.watched {
+		c.watched[key] = watchedAt
+	}
+	for id, user := range s.users {
+		c.users[id] = user
+	}
+	for id, hash := range s.passwords {
+		c.passwords[id] = hash
+	}
+	for id, session := range s.sessions {
+		c.sessions[id] = session
+	}
+	for id, key := range s.apiKeys {
+		c.apiKeys[id] = key
+	}
+	for name, permissions := range s.roles {
+		c.roles[name] = append([]model.Permission(nil), permissions...)
+	}
+	for id, collection := range s.collections {
+		c.collections[id] = collection
+	}
+	copy(c.collectionMovies, s.collectionMovies)
+	for key := range s.collectionUsers {
+		c.collectionUsers[key] = struct{}{}
+	}
+	return c
+}
+
+// checkMovie mirrors constraints of the "movies" table
+func checkMovie(title, description string, rating float64) error {
+	if title == "" || len([]rune(title)) > 150 ||
+		len([]rune(description)) > 1000 ||
+		!(rating >= 0 && rating <= 10) {
+		return model.ErrValidationError
+	}
+	return nil
+}
+
+// checkActor mirrors constraints of the "actors" table
+func checkActor(firstName, secondName string, gender model.Gender) error {
+	if len([]rune(firstName)) > 100 || len([]rune(secondName)) > 100 {
+		return model.ErrValidationError
+	}
+	switch gender {
+	case model.Unknown, model.Male, model.Female:
+		return nil
+	default:
+		return model.ErrValidationError
+	}
+}
+
+// checkGenre mirrors constraints of the "genres" table
+func checkGenre(name string) error {
+	if name == "" || len([]rune(name)) > 50 {
+		return model.ErrValidationError
+	}
+	return nil
+}
+
+// checkReview mirrors constraints of the "reviews" table
+func checkReview(rating int, text string) error {
+	if rating < model.MinReviewRating || rating > model.MaxReviewRating || len([]rune(text)) > 5000 {
+		return model.ErrValidationError
+	}
+	return nil
+}
+
+// checkUser mirrors constraints of the "users" table
+func (s *memoryStore) checkUser(username, email string, role model.Role) error {
+	if username == "" || len([]rune(username)) > 50 || len([]rune(email)) > 254 {
+		return model.ErrValidationError
+	}
+	if _, ok := s.roles[role]; !ok {
+		return model.ErrRoleNotExists
+	}
+	return nil
+}
+
+// checkRole mirrors constraints of the "roles" and "role_permissions" tables
+func checkRole(name model.Role, permissions []model.Permission) error {
+	if name == "" || len([]rune(name)) > 20 {
+		return model.ErrValidationError
+	}
+	for _, p := range permissions {
+		if !slices.Contains(model.Permissions, p) {
+			return model.ErrValidationError
+		}
+	}
+	return nil
+}
+
+// checkApiKey mirrors constraints of the "api_keys" table
+func checkApiKey(name, prefix, hash string, scope model.ApiKeyScope) error {
+	if name == "" || len([]rune(name)) > 100 || len([]rune(prefix)) > 20 ||
+		hash == "" || len(hash) > 64 {
+		return model.ErrValidationError
+	}
+	switch scope {
+	case model.ReadScope, model.WriteScope:
+		return nil
+	default:
+		return model.ErrValidationError
+	}
+}
+
+// checkCollection mirrors constraints of the "collections" table
+func checkCollection(name, description string, visibility model.Visibility) error {
+	if name == "" || len([]rune(name)) > 100 || len([]rune(description)) > 1000 {
+		return model.ErrValidationError
+	}
+	switch visibility {
+	case model.Private, model.Public:
+		return nil
+	default:
+		return model.ErrValidationError
+	}
+}
+
+// checkCollectionEntry mirrors constraints of the "collection-movie" table
+func checkCollectionEntry(note string) error {
+	if len([]rune(note)) > 500 {
+		return model.ErrValidationError
+	}
+	return nil
+}
+
+// checkCast mirrors constraints of the "movie-actor" table
+func (s *memoryStore) checkCast(cast []model.CastCredit) error {
+	for _, credit := range cast {
+		if credit.Billing < 0 {
+			return model.ErrValidationError
+		}
+		for _, character := range credit.Characters {
+			if character == "" {
+				return model.ErrValidationError
+			}
+		}
+	}
+	for _, credit := range cast {
+		if _, ok := s.actors[credit.ActorId]; !ok {
+			return model.ErrActorNotExists
+		}
+	}
+	return nil
+}
+
+// checkCrew mirrors constraints of the "movie-crew" table
+func (s *memoryStore) checkCrew(crew []model.CrewCredit) error {
+	for _, credit := range crew {
+		if !isCrewRole(credit.Role) {
+			return model.ErrValidationError
+		}
+	}
+	for _, credit := range crew {
+		if _, ok := s.actors[credit.PersonId]; !ok {
+			return model.ErrPersonNotExists
+		}
+	}
+	return nil
+}
+
+func isCrewRole(role model.CreditRole) bool {
+	for _, r := range model.CrewRoles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// toDate truncates t to the date the same way as the DATE column does
+func toDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PoolStats returns empty statistics because in-memory repository has no
+// connection pool
+func (r *memoryRepo) PoolStats(_ context.Context) model.PoolStats {
+	return model.PoolStats{}
+}
@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"strings"
+)
+
+func (r *memoryRepo) CreateActor(_ context.Context, actor model.Actor) (model.Actor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkActor(actor.FirstName, actor.SecondName, actor.Gender); err != nil {
+		return model.Actor{}, err
+	}
+	r.s.lastActorId++
+	actor.Id = r.s.lastActorId
+	r.s.actors[actor.Id] = model.Actor{
+		Id:         actor.Id,
+		FirstName:  actor.FirstName,
+		SecondName: actor.SecondName,
+		Gender:     actor.Gender,
+		CrewOnly:   actor.CrewOnly,
+	}
+	return actor, nil
+}
+
+func (r *memoryRepo) UpdateActor(_ context.Context, id uint64, upd model.UpdateActor) (model.Actor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkActor(upd.FirstName, upd.SecondName, upd.Gender); err != nil {
+		return model.Actor{}, err
+	}
+	if _, ok := r.s.actors[id]; !ok {
+		return model.Actor{}, model.ErrActorNotExists
+	}
+	r.s.actors[id] = model.Actor{
+		Id:         id,
+		FirstName:  upd.FirstName,
+		SecondName: upd.SecondName,
+		Gender:     upd.Gender,
+		CrewOnly:   upd.CrewOnly,
+	}
+	return r.s.getActor(id)
+}
+
+func (r *memoryRepo) DeleteActor(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.actors[id]; !ok {
+		return model.ErrActorNotExists
+	}
+	delete(r.s.actors, id)
+	r.s.deleteMovieActors(func(l movieActorLink) bool { return l.actorId == id })
+	r.s.deleteMovieCrew(func(l movieCrewLink) bool { return l.personId == id })
+	return nil
+}
+
+func (r *memoryRepo) GetActor(_ context.Context, id uint64) (model.Actor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.s.getActor(id)
+}
+
+func (r *memoryRepo) GetActors(ctx context.Context, filter model.ActorFilter,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
+	return r.SearchActors(ctx, "", filter, sortBy, page)
+}
+
+func (r *memoryRepo) SearchActors(_ context.Context, pattern string, filter model.ActorFilter,
+	sortBy model.Sort, page model.Page) (model.ActorList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys, err := actorSortKeys(sortBy)
+	if err != nil {
+		return model.ActorList{}, err
+	}
+
+	// movies are loaded before sorting because actors can be sorted by
+	// the number of movies
+	words := strings.Fields(strings.ToLower(pattern))
+	actors := make([]model.Actor, 0, len(r.s.actors))
+	for _, actor := range r.s.actors {
+		if actor.CrewOnly {
+			continue
+		}
+		actor.Movies = r.s.getActorMovies(actor.Id)
+		if matchActorName(actor, words) && matchActorFilter(actor, filter) {
+			actors = append(actors, actor)
+		}
+	}
+	sortByKeys(keys, actors)
+	actorsPage, nextCursor, err := paginate(keys, actors, page)
+	if err != nil {
+		return model.ActorList{}, err
+	}
+
+	return model.ActorList{
+		Actors:     actorsPage,
+		NextCursor: nextCursor,
+		Total:      uint64(len(actors)),
+	}, nil
+}
+
+// matchActorName checks whether the first or second name of the actor
+// contains every lower cased word
+func matchActorName(actor model.Actor, words []string) bool {
+	firstName, secondName := strings.ToLower(actor.FirstName), strings.ToLower(actor.SecondName)
+	for _, word := range words {
+		if !strings.Contains(firstName, word) && !strings.Contains(secondName, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchActorFilter checks whether the actor with loaded movies satisfies the
+// filter
+func matchActorFilter(actor model.Actor, filter model.ActorFilter) bool {
+	if filter.Gender != model.Unknown && actor.Gender != filter.Gender {
+		return false
+	}
+	if filter.MovieId != 0 {
+		for _, movie := range actor.Movies {
+			if movie.Id == filter.MovieId {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// getActor returns actor with its movies, should be called under lock
+func (s *memoryStore) getActor(id uint64) (model.Actor, error) {
+	actor, ok := s.actors[id]
+	if !ok {
+		return model.Actor{}, model.ErrActorNotExists
+	}
+	actor.Movies = s.getActorMovies(id)
+	return actor, nil
+}
+
+func (s *memoryStore) getActorMovies(id uint64) []model.Movie {
+	movies := make([]model.Movie, 0)
+	for _, l := range s.movieActors {
+		if l.actorId == id {
+			movies = append(movies, s.movies[l.movieId])
+		}
+	}
+	return movies
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"sort"
+	"time"
+)
+
+func (r *memoryRepo) CreateApiKey(_ context.Context, key model.ApiKey) (model.ApiKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkApiKey(key.Name, key.Prefix, key.KeyHash, key.Scope); err != nil {
+		return model.ApiKey{}, err
+	}
+	if _, ok := r.s.users[key.UserId]; !ok {
+		return model.ApiKey{}, model.ErrUserNotExists
+	}
+	for _, k := range r.s.apiKeys {
+		if k.KeyHash == key.KeyHash {
+			return model.ApiKey{}, model.ErrConflict
+		}
+	}
+
+	r.s.lastApiKeyId++
+	key.Id = r.s.lastApiKeyId
+	key.Key = ""
+	if key.ExpiresAt != nil {
+		expiresAt := key.ExpiresAt.UTC().Truncate(time.Microsecond)
+		key.ExpiresAt = &expiresAt
+	}
+	key.CreatedAt = now()
+	r.s.apiKeys[key.Id] = key
+	return key, nil
+}
+
+func (r *memoryRepo) GetApiKey(_ context.Context, id uint64) (model.ApiKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.s.apiKeys[id]
+	if !ok {
+		return model.ApiKey{}, model.ErrApiKeyNotExists
+	}
+	return key, nil
+}
+
+func (r *memoryRepo) GetApiKeyByHash(_ context.Context, hash string) (model.ApiKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	current := now()
+	for _, key := range r.s.apiKeys {
+		if key.KeyHash == hash && (key.ExpiresAt == nil || key.ExpiresAt.After(current)) {
+			return key, nil
+		}
+	}
+	return model.ApiKey{}, model.ErrApiKeyNotExists
+}
+
+func (r *memoryRepo) GetApiKeys(_ context.Context, userId uint64) ([]model.ApiKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]model.ApiKey, 0)
+	for _, key := range r.s.apiKeys {
+		if key.UserId == userId {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Id < keys[j].Id })
+	return keys, nil
+}
+
+func (r *memoryRepo) DeleteApiKey(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.apiKeys[id]; !ok {
+		return model.ErrApiKeyNotExists
+	}
+	delete(r.s.apiKeys, id)
+	return nil
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+func (r *memoryRepo) GetUserCredentials(_ context.Context, username string) (model.Credentials, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if strings.EqualFold(user.Username, username) {
+			return model.Credentials{
+				UserId:       user.Id,
+				PasswordHash: r.s.passwords[user.Id],
+				Disabled:     user.Disabled,
+			}, nil
+		}
+	}
+	return model.Credentials{}, model.ErrUserNotExists
+}
+
+func (r *memoryRepo) SetPasswordHash(_ context.Context, userId uint64, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.users[userId]; !ok {
+		return model.ErrUserNotExists
+	}
+	if len(hash) > 100 {
+		return model.ErrValidationError
+	}
+	if hash == "" {
+		delete(r.s.passwords, userId)
+	} else {
+		r.s.passwords[userId] = hash
+	}
+	return nil
+}
+
+func (r *memoryRepo) CreateSession(_ context.Context, session model.Session) (model.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.users[session.UserId]; !ok {
+		return model.Session{}, model.ErrUserNotExists
+	}
+	if err := r.s.checkSession(0, session.RefreshTokenHash); err != nil {
+		return model.Session{}, err
+	}
+
+	current := now()
+	for id, s := range r.s.sessions {
+		if s.UserId == session.UserId && !s.ExpiresAt.After(current) {
+			delete(r.s.sessions, id)
+		}
+	}
+
+	r.s.lastSessionId++
+	session.Id = r.s.lastSessionId
+	session.ExpiresAt = session.ExpiresAt.UTC().Truncate(time.Microsecond)
+	session.CreatedAt = current
+	r.s.sessions[session.Id] = session
+	return session, nil
+}
+
+func (r *memoryRepo) GetSession(_ context.Context, id uint64) (model.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.s.sessions[id]
+	if !ok || !session.ExpiresAt.After(now()) {
+		return model.Session{}, model.ErrSessionNotExists
+	}
+	return session, nil
+}
+
+func (r *memoryRepo) RefreshSession(_ context.Context, oldHash string, newHash string,
+	expiresAt time.Time) (model.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := now()
+	for id, session := range r.s.sessions {
+		if session.RefreshTokenHash != oldHash || !session.ExpiresAt.After(current) {
+			continue
+		}
+		if err := r.s.checkSession(id, newHash); err != nil {
+			return model.Session{}, err
+		}
+		session.RefreshTokenHash = newHash
+		session.ExpiresAt = expiresAt.UTC().Truncate(time.Microsecond)
+		r.s.sessions[id] = session
+		return session, nil
+	}
+	return model.Session{}, model.ErrSessionNotExists
+}
+
+func (r *memoryRepo) DeleteSession(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.sessions[id]; !ok {
+		return model.ErrSessionNotExists
+	}
+	delete(r.s.sessions, id)
+	return nil
+}
+
+func (r *memoryRepo) DeleteUserSessions(_ context.Context, userId uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.s.deleteUserSessions(userId)
+	return nil
+}
+
+// checkSession mirrors constraints of the "sessions" table: the refresh
+// token hash is required and unique
+func (s *memoryStore) checkSession(id uint64, hash string) error {
+	if hash == "" || len(hash) > 64 {
+		return model.ErrValidationError
+	}
+	for _, session := range s.sessions {
+		if session.Id != id && session.RefreshTokenHash == hash {
+			return model.ErrConflict
+		}
+	}
+	return nil
+}
+
+// deleteUserSessions deletes all sessions of the user, should be called under
+// lock
+func (s *memoryStore) deleteUserSessions(userId uint64) {
+	for id, session := range s.sessions {
+		if session.UserId == userId {
+			delete(s.sessions, id)
+		}
+	}
+}
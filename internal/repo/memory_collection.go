@@ -0,0 +1,285 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"sort"
+)
+
+func (r *memoryRepo) CreateCollection(_ context.Context, collection model.Collection) (model.Collection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkCollection(collection.Name, collection.Description, collection.Visibility); err != nil {
+		return model.Collection{}, err
+	}
+	if _, ok := r.s.users[collection.OwnerId]; !ok {
+		return model.Collection{}, model.ErrUserNotExists
+	}
+	if err := r.s.checkCollectionUsers(collection.SharedWith); err != nil {
+		return model.Collection{}, err
+	}
+
+	r.s.lastCollectionId++
+	collection.Id = r.s.lastCollectionId
+	r.s.collections[collection.Id] = model.Collection{
+		Id:          collection.Id,
+		OwnerId:     collection.OwnerId,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Visibility:  collection.Visibility,
+		CreatedAt:   now(),
+	}
+	r.s.shareCollection(collection.Id, collection.SharedWith)
+	return r.s.getCollection(collection.Id)
+}
+
+func (r *memoryRepo) UpdateCollection(_ context.Context, id uint64, upd model.UpdateCollection) (model.Collection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkCollection(upd.Name, upd.Description, upd.Visibility); err != nil {
+		return model.Collection{}, err
+	}
+	collection, ok := r.s.collections[id]
+	if !ok {
+		return model.Collection{}, model.ErrCollectionNotExists
+	}
+	if err := r.s.checkCollectionUsers(upd.SharedWith); err != nil {
+		return model.Collection{}, err
+	}
+
+	collection.Name = upd.Name
+	collection.Description = upd.Description
+	collection.Visibility = upd.Visibility
+	r.s.collections[id] = collection
+	for key := range r.s.collectionUsers {
+		if key.collectionId == id {
+			delete(r.s.collectionUsers, key)
+		}
+	}
+	r.s.shareCollection(id, upd.SharedWith)
+	return r.s.getCollection(id)
+}
+
+func (r *memoryRepo) DeleteCollection(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.collections[id]; !ok {
+		return model.ErrCollectionNotExists
+	}
+	delete(r.s.collections, id)
+	r.s.deleteCollectionMovies(func(l collectionMovieLink) bool { return l.collectionId == id })
+	for key := range r.s.collectionUsers {
+		if key.collectionId == id {
+			delete(r.s.collectionUsers, key)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) GetCollection(_ context.Context, id uint64) (model.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.s.getCollection(id)
+}
+
+func (r *memoryRepo) GetCollections(_ context.Context, filter model.CollectionFilter,
+	page model.Page) (model.CollectionList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collections := make([]model.Collection, 0)
+	for _, collection := range r.s.collections {
+		if (filter.OwnerId == 0 || collection.OwnerId == filter.OwnerId) &&
+			(filter.ReaderId == 0 || r.s.canReadCollection(collection, filter.ReaderId)) {
+			collections = append(collections, collection)
+		}
+	}
+
+	keys := collectionSortKeys()
+	sortByKeys(keys, collections)
+	collectionsPage, nextCursor, err := paginate(keys, collections, page)
+	if err != nil {
+		return model.CollectionList{}, err
+	}
+	return model.CollectionList{
+		Collections: collectionsPage,
+		NextCursor:  nextCursor,
+		Total:       uint64(len(collections)),
+	}, nil
+}
+
+func (r *memoryRepo) AddCollectionEntry(_ context.Context, collectionId uint64, entry model.CollectionEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkCollectionEntry(entry.Note); err != nil {
+		return err
+	}
+	if _, ok := r.s.collections[collectionId]; !ok {
+		return model.ErrCollectionNotExists
+	}
+	if _, ok := r.s.movies[entry.MovieId]; !ok {
+		return model.ErrMovieNotExists
+	}
+
+	position := 0
+	for _, l := range r.s.collectionMovies {
+		if l.collectionId != collectionId {
+			continue
+		}
+		if l.movieId == entry.MovieId {
+			return model.ErrConflict
+		}
+		position = max(position, l.position)
+	}
+	r.s.collectionMovies = append(r.s.collectionMovies, collectionMovieLink{
+		collectionId: collectionId,
+		movieId:      entry.MovieId,
+		position:     position + 1,
+		note:         entry.Note,
+	})
+	return nil
+}
+
+func (r *memoryRepo) UpdateCollectionEntry(_ context.Context, collectionId uint64, entry model.CollectionEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkCollectionEntry(entry.Note); err != nil {
+		return err
+	}
+	for i, l := range r.s.collectionMovies {
+		if l.collectionId == collectionId && l.movieId == entry.MovieId {
+			r.s.collectionMovies[i].note = entry.Note
+			return nil
+		}
+	}
+	return model.ErrEntryNotExists
+}
+
+func (r *memoryRepo) RemoveCollectionEntry(_ context.Context, collectionId, movieId uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := func(l collectionMovieLink) bool { return l.collectionId == collectionId && l.movieId == movieId }
+	for _, l := range r.s.collectionMovies {
+		if match(l) {
+			r.s.deleteCollectionMovies(match)
+			return nil
+		}
+	}
+	return model.ErrEntryNotExists
+}
+
+func (r *memoryRepo) ReorderCollection(_ context.Context, collectionId uint64, moviesId []uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.collections[collectionId]; !ok {
+		return model.ErrCollectionNotExists
+	}
+	entries := make([]uint64, 0)
+	for _, l := range r.s.collectionMovies {
+		if l.collectionId == collectionId {
+			entries = append(entries, l.movieId)
+		}
+	}
+	if !isPermutation(entries, moviesId) {
+		return model.ErrValidationError
+	}
+
+	positions := make(map[uint64]int, len(moviesId))
+	for i, id := range moviesId {
+		positions[id] = i + 1
+	}
+	for i, l := range r.s.collectionMovies {
+		if l.collectionId == collectionId {
+			r.s.collectionMovies[i].position = positions[l.movieId]
+		}
+	}
+	return nil
+}
+
+// getCollection returns the collection with its entries and users it is
+// shared with, should be called under lock
+func (s *memoryStore) getCollection(id uint64) (model.Collection, error) {
+	collection, ok := s.collections[id]
+	if !ok {
+		return model.Collection{}, model.ErrCollectionNotExists
+	}
+
+	collection.SharedWith = make([]uint64, 0)
+	for key := range s.collectionUsers {
+		if key.collectionId == id {
+			collection.SharedWith = append(collection.SharedWith, key.userId)
+		}
+	}
+	sort.Slice(collection.SharedWith, func(i, j int) bool { return collection.SharedWith[i] < collection.SharedWith[j] })
+
+	links := make([]collectionMovieLink, 0)
+	for _, l := range s.collectionMovies {
+		if l.collectionId == id {
+			links = append(links, l)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].position != links[j].position {
+			return links[i].position < links[j].position
+		}
+		return links[i].movieId < links[j].movieId
+	})
+	collection.Entries = make([]model.CollectionEntry, 0, len(links))
+	for _, l := range links {
+		movie := s.movies[l.movieId]
+		s.loadMovieRelations(&movie)
+		collection.Entries = append(collection.Entries, model.CollectionEntry{
+			MovieId: l.movieId,
+			Movie:   movie,
+			Note:    l.note,
+		})
+	}
+	return collection, nil
+}
+
+// canReadCollection checks whether the collection is own, public or shared
+// with the user, should be called under lock
+func (s *memoryStore) canReadCollection(collection model.Collection, userId uint64) bool {
+	if collection.OwnerId == userId || collection.Visibility == model.Public {
+		return true
+	}
+	_, ok := s.collectionUsers[collectionUserKey{collection.Id, userId}]
+	return ok
+}
+
+// checkCollectionUsers mirrors the foreign key of the "collection-user" table
+func (s *memoryStore) checkCollectionUsers(usersId []uint64) error {
+	for _, id := range usersId {
+		if _, ok := s.users[id]; !ok {
+			return model.ErrValidationError
+		}
+	}
+	return nil
+}
+
+// shareCollection links users to the collection skipping already existing
+// links
+func (s *memoryStore) shareCollection(id uint64, usersId []uint64) {
+	for _, userId := range usersId {
+		s.collectionUsers[collectionUserKey{id, userId}] = struct{}{}
+	}
+}
+
+// deleteCollectionMovies removes all links matching the condition
+func (s *memoryStore) deleteCollectionMovies(match func(l collectionMovieLink) bool) {
+	links := make([]collectionMovieLink, 0, len(s.collectionMovies))
+	for _, l := range s.collectionMovies {
+		if !match(l) {
+			links = append(links, l)
+		}
+	}
+	s.collectionMovies = links
+}
@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"sort"
+	"strings"
+)
+
+func (r *memoryRepo) CreateGenre(_ context.Context, genre model.Genre) (model.Genre, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkGenre(genre.Name); err != nil {
+		return model.Genre{}, err
+	}
+	if r.s.genreNameExists(0, genre.Name) {
+		return model.Genre{}, model.ErrConflict
+	}
+	r.s.lastGenreId++
+	genre.Id = r.s.lastGenreId
+	r.s.genres[genre.Id] = genre
+	return genre, nil
+}
+
+func (r *memoryRepo) UpdateGenre(_ context.Context, id uint64, upd model.UpdateGenre) (model.Genre, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkGenre(upd.Name); err != nil {
+		return model.Genre{}, err
+	}
+	if _, ok := r.s.genres[id]; !ok {
+		return model.Genre{}, model.ErrGenreNotExists
+	}
+	if r.s.genreNameExists(id, upd.Name) {
+		return model.Genre{}, model.ErrConflict
+	}
+	r.s.genres[id] = model.Genre{
+		Id:   id,
+		Name: upd.Name,
+	}
+	return r.s.genres[id], nil
+}
+
+func (r *memoryRepo) DeleteGenre(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.genres[id]; !ok {
+		return model.ErrGenreNotExists
+	}
+	delete(r.s.genres, id)
+	r.s.deleteMovieGenres(func(l movieGenreLink) bool { return l.genreId == id })
+	return nil
+}
+
+func (r *memoryRepo) GetGenre(_ context.Context, id uint64) (model.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	genre, ok := r.s.genres[id]
+	if !ok {
+		return model.Genre{}, model.ErrGenreNotExists
+	}
+	return genre, nil
+}
+
+func (r *memoryRepo) GetGenres(_ context.Context) ([]model.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	genres := make([]model.Genre, 0, len(r.s.genres))
+	for _, genre := range r.s.genres {
+		genres = append(genres, genre)
+	}
+	sortGenres(genres)
+	return genres, nil
+}
+
+// genreNameExists checks whether a genre other than the one with the id has
+// the same name ignoring case, should be called under lock
+func (s *memoryStore) genreNameExists(id uint64, name string) bool {
+	for _, genre := range s.genres {
+		if genre.Id != id && strings.ToLower(genre.Name) == strings.ToLower(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getMovieGenres returns genres of the movie ordered by name, should be
+// called under lock
+func (s *memoryStore) getMovieGenres(id uint64) []model.Genre {
+	genres := make([]model.Genre, 0)
+	for _, l := range s.movieGenres {
+		if l.movieId == id {
+			genres = append(genres, s.genres[l.genreId])
+		}
+	}
+	sortGenres(genres)
+	return genres
+}
+
+// addMovieGenres links genres to the movie skipping already existing links
+func (s *memoryStore) addMovieGenres(movieId uint64, genresId []uint64) {
+	for _, genreId := range genresId {
+		exists := false
+		for _, l := range s.movieGenres {
+			if l.movieId == movieId && l.genreId == genreId {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			s.movieGenres = append(s.movieGenres, movieGenreLink{
+				movieId: movieId,
+				genreId: genreId,
+			})
+		}
+	}
+}
+
+// deleteMovieGenres removes all links matching the condition
+func (s *memoryStore) deleteMovieGenres(match func(l movieGenreLink) bool) {
+	links := make([]movieGenreLink, 0, len(s.movieGenres))
+	for _, l := range s.movieGenres {
+		if !match(l) {
+			links = append(links, l)
+		}
+	}
+	s.movieGenres = links
+}
+
+// sortGenres sorts genres by byte values of names the same way as the "C"
+// collation does
+func sortGenres(genres []model.Genre) {
+	sort.Slice(genres, func(i, j int) bool {
+		if genres[i].Name != genres[j].Name {
+			return genres[i].Name < genres[j].Name
+		}
+		return genres[i].Id < genres[j].Id
+	})
+}
@@ -0,0 +1,340 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"sort"
+)
+
+func (r *memoryRepo) CreateMovie(_ context.Context, movie model.Movie) (model.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkMovie(movie.Title, movie.Description, movie.Rating); err != nil {
+		return model.Movie{}, err
+	}
+	cast := model.MovieCast(movie.Cast, movie.ActorsId)
+	if err := r.s.checkCast(cast); err != nil {
+		return model.Movie{}, err
+	}
+	for _, id := range movie.GenresId {
+		if _, ok := r.s.genres[id]; !ok {
+			return model.Movie{}, model.ErrGenreNotExists
+		}
+	}
+	if err := r.s.checkCrew(movie.CrewCredits); err != nil {
+		return model.Movie{}, err
+	}
+
+	r.s.lastMovieId++
+	movie.Id = r.s.lastMovieId
+	r.s.movies[movie.Id] = model.Movie{
+		Id:          movie.Id,
+		Title:       movie.Title,
+		Description: movie.Description,
+		ReleaseDate: toDate(movie.ReleaseDate),
+		Rating:      movie.Rating,
+	}
+	r.s.addMovieCast(movie.Id, cast)
+	r.s.addMovieGenres(movie.Id, movie.GenresId)
+	r.s.addMovieCrew(movie.Id, movie.CrewCredits)
+
+	return r.s.getMovie(movie.Id)
+}
+
+func (r *memoryRepo) UpdateMovie(_ context.Context, id uint64, upd model.UpdateMovie) (model.Movie, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkMovie(upd.Title, upd.Description, upd.Rating); err != nil {
+		return model.Movie{}, err
+	}
+	if _, ok := r.s.movies[id]; !ok {
+		return model.Movie{}, model.ErrMovieNotExists
+	}
+	cast := model.MovieCast(upd.Cast, upd.Actors)
+	if err := r.s.checkCast(cast); err != nil {
+		return model.Movie{}, err
+	}
+	for _, genreId := range upd.Genres {
+		if _, ok := r.s.genres[genreId]; !ok {
+			return model.Movie{}, model.ErrGenreNotExists
+		}
+	}
+	if err := r.s.checkCrew(upd.Crew); err != nil {
+		return model.Movie{}, err
+	}
+	r.s.movies[id] = model.Movie{
+		Id:          id,
+		Title:       upd.Title,
+		Description: upd.Description,
+		ReleaseDate: toDate(upd.ReleaseDate),
+		Rating:      upd.Rating,
+	}
+
+	r.s.deleteMovieActors(func(l movieActorLink) bool { return l.movieId == id })
+	r.s.addMovieCast(id, cast)
+	r.s.deleteMovieGenres(func(l movieGenreLink) bool { return l.movieId == id })
+	r.s.addMovieGenres(id, upd.Genres)
+	r.s.deleteMovieCrew(func(l movieCrewLink) bool { return l.movieId == id })
+	r.s.addMovieCrew(id, upd.Crew)
+
+	return r.s.getMovie(id)
+}
+
+func (r *memoryRepo) DeleteMovie(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.movies[id]; !ok {
+		return model.ErrMovieNotExists
+	}
+	delete(r.s.movies, id)
+	r.s.deleteMovieActors(func(l movieActorLink) bool { return l.movieId == id })
+	r.s.deleteMovieGenres(func(l movieGenreLink) bool { return l.movieId == id })
+	r.s.deleteMovieCrew(func(l movieCrewLink) bool { return l.movieId == id })
+	for reviewId, review := range r.s.reviews {
+		if review.MovieId == id {
+			delete(r.s.reviews, reviewId)
+		}
+	}
+	for key := range r.s.watchlist {
+		if key.movieId == id {
+			delete(r.s.watchlist, key)
+		}
+	}
+	for key := range r.s.watched {
+		if key.movieId == id {
+			delete(r.s.watched, key)
+		}
+	}
+	r.s.deleteCollectionMovies(func(l collectionMovieLink) bool { return l.movieId == id })
+	return nil
+}
+
+func (r *memoryRepo) GetMovie(_ context.Context, id uint64) (model.Movie, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.s.getMovie(id)
+}
+
+func (r *memoryRepo) GetMovies(_ context.Context, filter model.MovieFilter,
+	sortBy model.Sort, page model.Page) (model.MovieList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys, err := movieSortKeys(sortBy)
+	if err != nil {
+		return model.MovieList{}, err
+	}
+
+	movies := make([]model.Movie, 0)
+	for _, movie := range r.s.sortedMovies() {
+		if r.s.matchMovieFilter(movie, filter) {
+			movies = append(movies, movie)
+		}
+	}
+	return r.s.moviesPage(movies, keys, page)
+}
+
+func (r *memoryRepo) SearchMovies(_ context.Context, query string, filter model.MovieFilter,
+	sortBy model.Sort, page model.Page) (model.MovieList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys, err := searchMovieSortKeys(sortBy)
+	if err != nil {
+		return model.MovieList{}, err
+	}
+
+	q := parseSearchQuery(query)
+	movies := make([]model.Movie, 0)
+	for _, movie := range r.s.sortedMovies() {
+		if !r.s.matchMovieFilter(movie, filter) {
+			continue
+		}
+		relevance, ok := q.rank(r.s.movieSearchDocument(movie))
+		if !ok {
+			continue
+		}
+		movie.Relevance = relevance
+		movie.Snippet = q.headline(movie.Description)
+		movies = append(movies, movie)
+	}
+	return r.s.moviesPage(movies, keys, page)
+}
+
+// matchMovieFilter checks whether the movie satisfies the filter, should be
+// called under lock
+func (s *memoryStore) matchMovieFilter(movie model.Movie, filter model.MovieFilter) bool {
+	if filter.ReleaseDateFrom != nil && movie.ReleaseDate.Before(toDate(*filter.ReleaseDateFrom)) ||
+		filter.ReleaseDateTo != nil && movie.ReleaseDate.After(toDate(*filter.ReleaseDateTo)) ||
+		filter.RatingFrom != nil && movie.Rating < *filter.RatingFrom ||
+		filter.RatingTo != nil && movie.Rating > *filter.RatingTo {
+		return false
+	}
+
+	movieActors := s.getMovieActors(movie.Id)
+	if len(filter.ActorsId) != 0 {
+		actorsId := uniqueIds(filter.ActorsId)
+		found := 0
+		for _, id := range actorsId {
+			for _, actor := range movieActors {
+				if actor.Id == id {
+					found++
+					break
+				}
+			}
+		}
+		if found == 0 || filter.ActorsMatch == model.MatchAll && found != len(actorsId) {
+			return false
+		}
+	}
+	if filter.ActorGender != model.Unknown {
+		found := false
+		for _, actor := range movieActors {
+			if actor.Gender == filter.ActorGender {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.InWatchlist != nil {
+		if _, ok := s.watchlist[userMovieKey{filter.UserId, movie.Id}]; ok != *filter.InWatchlist {
+			return false
+		}
+	}
+	if filter.Watched != nil {
+		if _, ok := s.watched[userMovieKey{filter.UserId, movie.Id}]; ok != *filter.Watched {
+			return false
+		}
+	}
+	return s.matchMovieGenres(movie.Id, filter)
+}
+
+// matchMovieGenres checks whether the movie has any or all genres of the
+// filter, should be called under lock
+func (s *memoryStore) matchMovieGenres(movieId uint64, filter model.MovieFilter) bool {
+	if len(filter.GenresId) == 0 {
+		return true
+	}
+	genresId := uniqueIds(filter.GenresId)
+	movieGenres := s.getMovieGenres(movieId)
+	found := 0
+	for _, id := range genresId {
+		for _, genre := range movieGenres {
+			if genre.Id == id {
+				found++
+				break
+			}
+		}
+	}
+	return found != 0 && (filter.GenresMatch != model.MatchAll || found == len(genresId))
+}
+
+// moviesPage sorts movies and returns the requested page with relations
+func (s *memoryStore) moviesPage(movies []model.Movie, keys []sortKey[model.Movie], page model.Page) (model.MovieList, error) {
+	sortByKeys(keys, movies)
+	moviesPage, nextCursor, err := paginate(keys, movies, page)
+	if err != nil {
+		return model.MovieList{}, err
+	}
+
+	for i := range moviesPage {
+		s.loadMovieRelations(&moviesPage[i])
+	}
+	return model.MovieList{
+		Movies:     moviesPage,
+		NextCursor: nextCursor,
+		Total:      uint64(len(movies)),
+	}, nil
+}
+
+// getMovie returns movie with its relations, should be called under lock
+func (s *memoryStore) getMovie(id uint64) (model.Movie, error) {
+	movie, ok := s.movies[id]
+	if !ok {
+		return model.Movie{}, model.ErrMovieNotExists
+	}
+	s.loadMovieRelations(&movie)
+	return movie, nil
+}
+
+// loadMovieRelations sets actors, genres, crew and the community rating of
+// the movie, should be called under lock
+func (s *memoryStore) loadMovieRelations(movie *model.Movie) {
+	movie.Actors = s.getMovieActors(movie.Id)
+	movie.Genres = s.getMovieGenres(movie.Id)
+	movie.Crew = s.getMovieCrew(movie.Id)
+	movie.CommunityRating, movie.VotesCount = s.getMovieVotes(movie.Id)
+}
+
+// sortedMovies returns all movies without relations sorted by id
+func (s *memoryStore) sortedMovies() []model.Movie {
+	movies := make([]model.Movie, 0, len(s.movies))
+	for _, movie := range s.movies {
+		movies = append(movies, movie)
+	}
+	sort.Slice(movies, func(i, j int) bool { return movies[i].Id < movies[j].Id })
+	return movies
+}
+
+func (s *memoryStore) getMovieActors(id uint64) []model.Actor {
+	actors := make([]model.Actor, 0)
+	for _, l := range s.movieActors {
+		if l.movieId == id {
+			actor := s.actors[l.actorId]
+			actor.Characters = l.characters
+			actor.Billing = l.billing
+			actors = append(actors, actor)
+		}
+	}
+	sort.Slice(actors, func(i, j int) bool {
+		if actors[i].Billing != actors[j].Billing {
+			return actors[i].Billing < actors[j].Billing
+		}
+		return actors[i].Id < actors[j].Id
+	})
+	return actors
+}
+
+// addMovieCast links actors to the movie skipping already existing links,
+// persons cast in the movie are not crew only anymore
+func (s *memoryStore) addMovieCast(movieId uint64, cast []model.CastCredit) {
+	for _, credit := range cast {
+		if actor := s.actors[credit.ActorId]; actor.CrewOnly {
+			actor.CrewOnly = false
+			s.actors[credit.ActorId] = actor
+		}
+		exists := false
+		for _, l := range s.movieActors {
+			if l.movieId == movieId && l.actorId == credit.ActorId {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			s.movieActors = append(s.movieActors, movieActorLink{
+				movieId:    movieId,
+				actorId:    credit.ActorId,
+				characters: credit.Characters,
+				billing:    credit.Billing,
+			})
+		}
+	}
+}
+
+// deleteMovieActors removes all links matching the condition
+func (s *memoryStore) deleteMovieActors(match func(l movieActorLink) bool) {
+	links := make([]movieActorLink, 0, len(s.movieActors))
+	for _, l := range s.movieActors {
+		if !match(l) {
+			links = append(links, l)
+		}
+	}
+	s.movieActors = links
+}
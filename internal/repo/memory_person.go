@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"sort"
+)
+
+func (r *memoryRepo) GetFilmography(_ context.Context, personId uint64) (model.Filmography, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	person, ok := r.s.actors[personId]
+	if !ok {
+		return model.Filmography{}, model.ErrPersonNotExists
+	}
+
+	credits := make(map[model.CreditRole][]model.Movie)
+	for _, l := range r.s.movieActors {
+		if l.actorId == personId {
+			credits[model.ActorRole] = append(credits[model.ActorRole], r.s.movies[l.movieId])
+		}
+	}
+	for _, l := range r.s.movieCrew {
+		if l.personId == personId {
+			credits[l.role] = append(credits[l.role], r.s.movies[l.movieId])
+		}
+	}
+	for _, movies := range credits {
+		sort.Slice(movies, func(i, j int) bool {
+			if !movies[i].ReleaseDate.Equal(movies[j].ReleaseDate) {
+				return movies[i].ReleaseDate.Before(movies[j].ReleaseDate)
+			}
+			return movies[i].Id < movies[j].Id
+		})
+	}
+
+	return model.Filmography{
+		Person:  person,
+		Credits: credits,
+	}, nil
+}
+
+// getMovieCrew returns crew of the movie ordered by roles and ids, should be
+// called under lock
+func (s *memoryStore) getMovieCrew(id uint64) []model.CrewMember {
+	crew := make([]model.CrewMember, 0)
+	for _, l := range s.movieCrew {
+		if l.movieId == id {
+			crew = append(crew, model.CrewMember{
+				Person: s.actors[l.personId],
+				Role:   l.role,
+			})
+		}
+	}
+	position := make(map[model.CreditRole]int, len(model.CrewRoles))
+	for i, role := range model.CrewRoles {
+		position[role] = i
+	}
+	sort.Slice(crew, func(i, j int) bool {
+		if crew[i].Role != crew[j].Role {
+			return position[crew[i].Role] < position[crew[j].Role]
+		}
+		return crew[i].Id < crew[j].Id
+	})
+	return crew
+}
+
+// addMovieCrew links persons to the movie in the roles skipping already
+// existing links
+func (s *memoryStore) addMovieCrew(movieId uint64, crew []model.CrewCredit) {
+	for _, credit := range crew {
+		link := movieCrewLink{
+			movieId:  movieId,
+			personId: credit.PersonId,
+			role:     credit.Role,
+		}
+		exists := false
+		for _, l := range s.movieCrew {
+			if l == link {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			s.movieCrew = append(s.movieCrew, link)
+		}
+	}
+}
+
+// deleteMovieCrew removes all links matching the condition
+func (s *memoryStore) deleteMovieCrew(match func(l movieCrewLink) bool) {
+	links := make([]movieCrewLink, 0, len(s.movieCrew))
+	for _, l := range s.movieCrew {
+		if !match(l) {
+			links = append(links, l)
+		}
+	}
+	s.movieCrew = links
+}
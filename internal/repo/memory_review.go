@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (r *memoryRepo) CreateReview(_ context.Context, review model.Review) (model.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkReview(review.Rating, review.Text); err != nil {
+		return model.Review{}, err
+	}
+	if _, ok := r.s.movies[review.MovieId]; !ok {
+		return model.Review{}, model.ErrMovieNotExists
+	}
+	if _, ok := r.s.users[review.UserId]; !ok {
+		return model.Review{}, model.ErrUserNotExists
+	}
+	for _, other := range r.s.reviews {
+		if other.MovieId == review.MovieId && other.UserId == review.UserId {
+			return model.Review{}, model.ErrConflict
+		}
+	}
+
+	r.s.lastReviewId++
+	review.Id = r.s.lastReviewId
+	review.CreatedAt = now()
+	review.UpdatedAt = review.CreatedAt
+	r.s.reviews[review.Id] = review
+	return review, nil
+}
+
+func (r *memoryRepo) UpdateReview(_ context.Context, id uint64, upd model.UpdateReview) (model.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkReview(upd.Rating, upd.Text); err != nil {
+		return model.Review{}, err
+	}
+	review, ok := r.s.reviews[id]
+	if !ok {
+		return model.Review{}, model.ErrReviewNotExists
+	}
+	review.Rating = upd.Rating
+	review.Text = upd.Text
+	review.UpdatedAt = now()
+	r.s.reviews[id] = review
+	return review, nil
+}
+
+func (r *memoryRepo) DeleteReview(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.reviews[id]; !ok {
+		return model.ErrReviewNotExists
+	}
+	delete(r.s.reviews, id)
+	return nil
+}
+
+func (r *memoryRepo) GetReview(_ context.Context, id uint64) (model.Review, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	review, ok := r.s.reviews[id]
+	if !ok {
+		return model.Review{}, model.ErrReviewNotExists
+	}
+	return review, nil
+}
+
+func (r *memoryRepo) GetReviews(_ context.Context, filter model.ReviewFilter, page model.Page) (model.ReviewList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reviews := make([]model.Review, 0)
+	for _, review := range r.s.reviews {
+		if (filter.MovieId == 0 || review.MovieId == filter.MovieId) &&
+			(filter.UserId == 0 || review.UserId == filter.UserId) {
+			reviews = append(reviews, review)
+		}
+	}
+
+	keys := reviewSortKeys()
+	sortByKeys(keys, reviews)
+	reviewsPage, nextCursor, err := paginate(keys, reviews, page)
+	if err != nil {
+		return model.ReviewList{}, err
+	}
+	return model.ReviewList{
+		Reviews:    reviewsPage,
+		NextCursor: nextCursor,
+		Total:      uint64(len(reviews)),
+	}, nil
+}
+
+// getMovieVotes returns the average rating of the reviews of the movie and
+// the number of the reviews, should be called under lock
+func (s *memoryStore) getMovieVotes(id uint64) (float64, uint64) {
+	var sum, count uint64
+	for _, review := range s.reviews {
+		if review.MovieId == id {
+			sum += uint64(review.Rating)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return float64(sum) / float64(count), count
+}
+
+// now returns the current time with the precision of the TIMESTAMPTZ column
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"sort"
+)
+
+func (r *memoryRepo) CreateRole(_ context.Context, role model.RolePermissions) (model.RolePermissions, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := checkRole(role.Role, role.Permissions); err != nil {
+		return model.RolePermissions{}, err
+	}
+	if _, ok := r.s.roles[role.Role]; ok {
+		return model.RolePermissions{}, model.ErrConflict
+	}
+
+	r.s.roles[role.Role] = uniquePermissions(role.Permissions)
+	return r.s.role(role.Role), nil
+}
+
+func (r *memoryRepo) UpdateRole(_ context.Context, role model.RolePermissions) (model.RolePermissions, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.roles[role.Role]; !ok {
+		return model.RolePermissions{}, model.ErrRoleNotExists
+	}
+	if err := checkRole(role.Role, role.Permissions); err != nil {
+		return model.RolePermissions{}, err
+	}
+
+	r.s.roles[role.Role] = uniquePermissions(role.Permissions)
+	return r.s.role(role.Role), nil
+}
+
+func (r *memoryRepo) DeleteRole(_ context.Context, name model.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.roles[name]; !ok {
+		return model.ErrRoleNotExists
+	}
+	for _, user := range r.s.users {
+		if user.Role == name {
+			return model.ErrRoleInUse
+		}
+	}
+
+	delete(r.s.roles, name)
+	return nil
+}
+
+func (r *memoryRepo) GetRole(_ context.Context, name model.Role) (model.RolePermissions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.s.roles[name]; !ok {
+		return model.RolePermissions{}, model.ErrRoleNotExists
+	}
+	return r.s.role(name), nil
+}
+
+func (r *memoryRepo) GetRoles(_ context.Context) ([]model.RolePermissions, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make([]model.RolePermissions, 0, len(r.s.roles))
+	for name := range r.s.roles {
+		roles = append(roles, r.s.role(name))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Role < roles[j].Role })
+	return roles, nil
+}
+
+// role returns the copy of the role with permissions ordered by name, should
+// be called under lock
+func (s *memoryStore) role(name model.Role) model.RolePermissions {
+	permissions := make([]model.Permission, len(s.roles[name]))
+	copy(permissions, s.roles[name])
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return model.RolePermissions{
+		Role:        name,
+		Permissions: permissions,
+	}
+}
+
+// uniquePermissions returns permissions without repeats like the primary key
+// of the "role_permissions" table does
+func uniquePermissions(permissions []model.Permission) []model.Permission {
+	unique := make([]model.Permission, 0, len(permissions))
+	seen := make(map[model.Permission]struct{}, len(permissions))
+	for _, p := range permissions {
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
+
+// defaultRoles returns roles created by the migrations
+func defaultRoles() map[model.Role][]model.Permission {
+	return map[model.Role][]model.Permission{
+		model.Admin:   append([]model.Permission(nil), model.Permissions...),
+		model.Regular: {},
+		"editor":      {model.MoviesWrite, model.ActorsWrite, model.GenresWrite},
+		"moderator":   {model.ReviewsModerate, model.CollectionsModerate},
+	}
+}
@@ -0,0 +1,341 @@
+package repo
+
+import (
+	"context"
+	"github.com/blevesearch/snowballstem"
+	"github.com/blevesearch/snowballstem/english"
+	"github.com/blevesearch/snowballstem/russian"
+	"movie-lib/internal/model"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Weights of parts of the search document, they are the default weights of
+// ts_rank for labels A, B and C
+const (
+	titleWeight       = 1.0
+	actorsWeight      = 0.4
+	descriptionWeight = 0.2
+)
+
+// similarityThreshold is the default similarity threshold of pg_trgm
+const similarityThreshold = 0.3
+
+// headlineMaxWords is a maximum number of words in the snippet, it is the
+// default MaxWords of ts_headline
+const headlineMaxWords = 35
+
+// searchQuery emulates websearch_to_tsquery for the in-memory repository.
+// The query matches a document if any of its alternatives separated by "or"
+// matches. An alternative matches if the document contains all its words and
+// none of the words prefixed by "-". Quoted phrases are treated as separate
+// words and stop words are not removed.
+type searchQuery struct {
+	alternatives []searchAlternative
+}
+
+type searchAlternative struct {
+	include []string
+	exclude []string
+}
+
+func parseSearchQuery(s string) searchQuery {
+	var (
+		q   searchQuery
+		alt searchAlternative
+	)
+	for _, field := range strings.Fields(strings.ReplaceAll(s, `"`, " ")) {
+		if strings.EqualFold(field, "or") {
+			if len(alt.include) != 0 {
+				q.alternatives = append(q.alternatives, alt)
+			}
+			alt = searchAlternative{}
+			continue
+		}
+		exclude := strings.HasPrefix(field, "-")
+		for _, word := range splitWords(field) {
+			if exclude {
+				alt.exclude = append(alt.exclude, lexeme(field[word[0]:word[1]]))
+			} else {
+				alt.include = append(alt.include, lexeme(field[word[0]:word[1]]))
+			}
+		}
+	}
+	if len(alt.include) != 0 {
+		q.alternatives = append(q.alternatives, alt)
+	}
+	return q
+}
+
+// searchDocument maps lexemes of the document to their greatest weights
+type searchDocument map[string]float64
+
+func (d searchDocument) add(text string, weight float64) {
+	for _, word := range splitWords(text) {
+		l := lexeme(text[word[0]:word[1]])
+		d[l] = max(d[l], weight)
+	}
+}
+
+// movieSearchDocument returns the search document of the movie, should be
+// called under lock
+func (s *memoryStore) movieSearchDocument(movie model.Movie) searchDocument {
+	doc := make(searchDocument)
+	doc.add(movie.Title, titleWeight)
+	for _, actor := range s.getMovieActors(movie.Id) {
+		doc.add(actor.FirstName+" "+actor.SecondName, actorsWeight)
+	}
+	doc.add(movie.Description, descriptionWeight)
+	return doc
+}
+
+// rank returns relevance of the document to the query and whether the
+// document matches the query. The relevance is a sum of weights of the
+// matched words of the best alternative.
+func (q searchQuery) rank(doc searchDocument) (float64, bool) {
+	var (
+		best  float64
+		found bool
+	)
+	for _, alt := range q.alternatives {
+		rank, ok := alt.rank(doc)
+		if ok && (!found || rank > best) {
+			best, found = rank, true
+		}
+	}
+	return best, found
+}
+
+func (alt searchAlternative) rank(doc searchDocument) (float64, bool) {
+	for _, l := range alt.exclude {
+		if _, ok := doc[l]; ok {
+			return 0, false
+		}
+	}
+	var rank float64
+	for _, l := range alt.include {
+		weight, ok := doc[l]
+		if !ok {
+			return 0, false
+		}
+		rank += weight
+	}
+	return rank, true
+}
+
+// headline emulates ts_headline: it returns the text cut to the fragment
+// starting at the first match with matched words wrapped in <b></b>
+func (q searchQuery) headline(text string) string {
+	lexemes := make(map[string]struct{})
+	for _, alt := range q.alternatives {
+		for _, l := range alt.include {
+			lexemes[l] = struct{}{}
+		}
+	}
+
+	words := splitWords(text)
+	if len(words) == 0 {
+		return ""
+	}
+	matched := make([]bool, len(words))
+	start := -1
+	for i, word := range words {
+		if _, ok := lexemes[lexeme(text[word[0]:word[1]])]; ok {
+			matched[i] = true
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	// the fragment is moved back if the text ends earlier than the fragment
+	if start < 0 || len(words) <= headlineMaxWords {
+		start = 0
+	} else if len(words)-start < headlineMaxWords {
+		start = len(words) - headlineMaxWords
+	}
+	end := min(start+headlineMaxWords, len(words))
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		if i > start {
+			b.WriteString(text[words[i-1][1]:words[i][0]])
+		}
+		if matched[i] {
+			b.WriteString("<b>" + text[words[i][0]:words[i][1]] + "</b>")
+		} else {
+			b.WriteString(text[words[i][0]:words[i][1]])
+		}
+	}
+	return b.String()
+}
+
+// splitWords returns byte offsets of the words of the text, words are
+// sequences of letters and digits
+func splitWords(text string) [][2]int {
+	words := make([][2]int, 0)
+	start := -1
+	for i, c := range text {
+		isWordChar := unicode.IsLetter(c) || unicode.IsDigit(c)
+		if isWordChar && start < 0 {
+			start = i
+		} else if !isWordChar && start >= 0 {
+			words = append(words, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, [2]int{start, len(text)})
+	}
+	return words
+}
+
+// lexeme normalizes the word the same way as the russian text search
+// configuration does: latin words are stemmed by the english stemmer, other
+// words of letters by the russian one and words with digits are only lower
+// cased
+func lexeme(word string) string {
+	word = strings.ToLower(word)
+	ascii, letters := true, true
+	for _, c := range word {
+		ascii = ascii && c < unicode.MaxASCII
+		letters = letters && unicode.IsLetter(c)
+	}
+	if !letters {
+		return word
+	}
+
+	env := snowballstem.NewEnv(word)
+	if ascii {
+		english.Stem(env)
+	} else {
+		russian.Stem(env)
+	}
+	return env.Current()
+}
+
+func (r *memoryRepo) FuzzySearch(_ context.Context, query string, limit int) (model.FuzzySearchResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	queryTrigrams := trigrams(query)
+
+	movies := make([]model.Movie, 0)
+	for _, movie := range r.s.movies {
+		movie.Relevance = similarity(trigrams(movie.Title), queryTrigrams)
+		if movie.Relevance >= similarityThreshold {
+			movies = append(movies, movie)
+		}
+	}
+	sort.Slice(movies, func(i, j int) bool {
+		if movies[i].Relevance != movies[j].Relevance {
+			return movies[i].Relevance > movies[j].Relevance
+		}
+		return movies[i].Id < movies[j].Id
+	})
+	movies = movies[:min(limit, len(movies))]
+	for i := range movies {
+		r.s.loadMovieRelations(&movies[i])
+	}
+
+	actors := make([]model.Actor, 0)
+	for _, actor := range r.s.actors {
+		if actor.CrewOnly {
+			continue
+		}
+		actor.Relevance = similarity(trigrams(actor.FirstName+" "+actor.SecondName), queryTrigrams)
+		if actor.Relevance >= similarityThreshold {
+			actors = append(actors, actor)
+		}
+	}
+	sort.Slice(actors, func(i, j int) bool {
+		if actors[i].Relevance != actors[j].Relevance {
+			return actors[i].Relevance > actors[j].Relevance
+		}
+		return actors[i].Id < actors[j].Id
+	})
+	actors = actors[:min(limit, len(actors))]
+	for i := range actors {
+		actors[i].Movies = r.s.getActorMovies(actors[i].Id)
+	}
+
+	return model.FuzzySearchResult{
+		Movies: movies,
+		Actors: actors,
+	}, nil
+}
+
+func (r *memoryRepo) Suggest(_ context.Context, prefix string, limit int) ([]model.Suggestion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefix = strings.ToLower(prefix)
+	suggestions := make([]model.Suggestion, 0)
+	for _, movie := range r.s.movies {
+		if strings.HasPrefix(strings.ToLower(movie.Title), prefix) {
+			suggestions = append(suggestions, model.Suggestion{
+				Id:   movie.Id,
+				Type: model.MovieSuggestion,
+				Text: movie.Title,
+			})
+		}
+	}
+	for _, actor := range r.s.actors {
+		name := actor.FirstName + " " + actor.SecondName
+		if actor.CrewOnly {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(name), prefix) ||
+			strings.HasPrefix(strings.ToLower(actor.SecondName), prefix) {
+			suggestions = append(suggestions, model.Suggestion{
+				Id:   actor.Id,
+				Type: model.ActorSuggestion,
+				Text: name,
+			})
+		}
+	}
+
+	// movies go before actors with names of the same length
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if la, lb := utf8.RuneCountInString(a.Text), utf8.RuneCountInString(b.Text); la != lb {
+			return la < lb
+		}
+		if a.Type != b.Type {
+			return a.Type == model.MovieSuggestion
+		}
+		return a.Id < b.Id
+	})
+	return suggestions[:min(limit, len(suggestions))], nil
+}
+
+// trigrams returns the set of trigrams of the text the same way as pg_trgm
+// does: every lower cased word is padded by two spaces before and one space
+// after it
+func trigrams(text string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range splitWords(text) {
+		padded := []rune("  " + strings.ToLower(text[word[0]:word[1]]) + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// similarity returns the ratio of the number of shared trigrams to the number
+// of all trigrams, it is computed with float32 precision like the similarity
+// function of pg_trgm
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			shared++
+		}
+	}
+	return float64(float32(shared) / float32(len(a)+len(b)-shared))
+}
@@ -0,0 +1,16 @@
+package repo_test
+
+import (
+	"github.com/stretchr/testify/suite"
+	"movie-lib/internal/repo"
+	"movie-lib/internal/repo/repotest"
+	"testing"
+)
+
+func TestMemoryRepo(t *testing.T) {
+	suite.Run(t, &repotest.Suite{
+		NewRepo: func() repo.Repo {
+			return repo.NewMemory()
+		},
+	})
+}
@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"strings"
+)
+
+func (r *memoryRepo) GetUserRole(_ context.Context, id uint64) (model.Role, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.s.users[id]
+	if !ok || user.Disabled {
+		return "", model.ErrUserNotExists
+	}
+	return user.Role, nil
+}
+
+func (r *memoryRepo) CreateUser(_ context.Context, user model.User) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.s.checkUser(user.Username, user.Email, user.Role); err != nil {
+		return model.User{}, err
+	}
+	if r.s.hasUsername(0, user.Username, user.Email) {
+		return model.User{}, model.ErrConflict
+	}
+
+	r.s.lastUserId++
+	user.Id = r.s.lastUserId
+	user.CreatedAt = now()
+	r.s.users[user.Id] = user
+	return user, nil
+}
+
+func (r *memoryRepo) UpdateUser(_ context.Context, id uint64, upd model.UpdateUser) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.s.checkUser(upd.Username, upd.Email, upd.Role); err != nil {
+		return model.User{}, err
+	}
+	user, ok := r.s.users[id]
+	if !ok {
+		return model.User{}, model.ErrUserNotExists
+	}
+	if r.s.hasUsername(id, upd.Username, upd.Email) {
+		return model.User{}, model.ErrConflict
+	}
+	if (upd.Role != model.Admin || upd.Disabled) && !r.s.hasOtherActiveAdmin(id) {
+		return model.User{}, model.ErrLastAdmin
+	}
+
+	user.Username = upd.Username
+	user.Email = upd.Email
+	user.Role = upd.Role
+	user.Disabled = upd.Disabled
+	r.s.users[id] = user
+	return user, nil
+}
+
+func (r *memoryRepo) DeleteUser(_ context.Context, id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.s.users[id]; !ok {
+		return model.ErrUserNotExists
+	}
+	if !r.s.hasOtherActiveAdmin(id) {
+		return model.ErrLastAdmin
+	}
+
+	delete(r.s.users, id)
+	delete(r.s.passwords, id)
+	r.s.deleteUserSessions(id)
+	for keyId, key := range r.s.apiKeys {
+		if key.UserId == id {
+			delete(r.s.apiKeys, keyId)
+		}
+	}
+	for reviewId, review := range r.s.reviews {
+		if review.UserId == id {
+			delete(r.s.reviews, reviewId)
+		}
+	}
+	for key := range r.s.watchlist {
+		if key.userId == id {
+			delete(r.s.watchlist, key)
+		}
+	}
+	for key := range r.s.watched {
+		if key.userId == id {
+			delete(r.s.watched, key)
+		}
+	}
+	for collectionId, collection := range r.s.collections {
+		if collection.OwnerId == id {
+			delete(r.s.collections, collectionId)
+			r.s.deleteCollectionMovies(func(l collectionMovieLink) bool { return l.collectionId == collectionId })
+		}
+	}
+	for key := range r.s.collectionUsers {
+		if _, ok := r.s.collections[key.collectionId]; !ok || key.userId == id {
+			delete(r.s.collectionUsers, key)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) GetUser(_ context.Context, id uint64) (model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return model.User{}, model.ErrUserNotExists
+	}
+	return user, nil
+}
+
+func (r *memoryRepo) GetUsers(_ context.Context, page model.Page) (model.UserList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]model.User, 0, len(r.s.users))
+	for _, user := range r.s.users {
+		users = append(users, user)
+	}
+
+	keys := userSortKeys()
+	sortByKeys(keys, users)
+	usersPage, nextCursor, err := paginate(keys, users, page)
+	if err != nil {
+		return model.UserList{}, err
+	}
+	return model.UserList{
+		Users:      usersPage,
+		NextCursor: nextCursor,
+		Total:      uint64(len(users)),
+	}, nil
+}
+
+// hasUsername mirrors unique indexes of the "users" table: it checks whether
+// another user has the same username or email regardless of case
+func (s *memoryStore) hasUsername(id uint64, username, email string) bool {
+	for _, user := range s.users {
+		if user.Id == id {
+			continue
+		}
+		if strings.EqualFold(user.Username, username) ||
+			email != "" && strings.EqualFold(user.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOtherActiveAdmin checks whether any active admin except the user exists,
+// should be called under lock
+func (s *memoryStore) hasOtherActiveAdmin(id uint64) bool {
+	for _, user := range s.users {
+		if user.Role == model.Admin && !user.Disabled && user.Id != id {
+			return true
+		}
+	}
+	return false
+}
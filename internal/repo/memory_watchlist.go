@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (r *memoryRepo) AddToWatchlist(_ context.Context, userId, movieId uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.s.checkUserMovie(userId, movieId); err != nil {
+		return err
+	}
+	r.s.watchlist[userMovieKey{userId, movieId}] = struct{}{}
+	return nil
+}
+
+func (r *memoryRepo) RemoveFromWatchlist(_ context.Context, userId, movieId uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := userMovieKey{userId, movieId}
+	if _, ok := r.s.watchlist[key]; !ok {
+		return model.ErrEntryNotExists
+	}
+	delete(r.s.watchlist, key)
+	return nil
+}
+
+func (r *memoryRepo) MarkWatched(_ context.Context, userId, movieId uint64, watchedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.s.checkUserMovie(userId, movieId); err != nil {
+		return err
+	}
+	r.s.watched[userMovieKey{userId, movieId}] = toDate(watchedAt)
+	return nil
+}
+
+func (r *memoryRepo) UnmarkWatched(_ context.Context, userId, movieId uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := userMovieKey{userId, movieId}
+	if _, ok := r.s.watched[key]; !ok {
+		return model.ErrEntryNotExists
+	}
+	delete(r.s.watched, key)
+	return nil
+}
+
+func (r *memoryRepo) GetMoviesStatus(_ context.Context, userId uint64, moviesId []uint64) (map[uint64]model.MovieStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make(map[uint64]model.MovieStatus, len(moviesId))
+	for _, movieId := range moviesId {
+		key := userMovieKey{userId, movieId}
+		_, inWatchlist := r.s.watchlist[key]
+		watchedAt, watched := r.s.watched[key]
+		if inWatchlist || watched {
+			statuses[movieId] = model.MovieStatus{
+				InWatchlist: inWatchlist,
+				Watched:     watched,
+				WatchedAt:   watchedAt,
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// checkUserMovie mirrors foreign keys of the "watchlist" and "watched"
+// tables
+func (s *memoryStore) checkUserMovie(userId, movieId uint64) error {
+	if _, ok := s.users[userId]; !ok {
+		return model.ErrUserNotExists
+	}
+	if _, ok := s.movies[movieId]; !ok {
+		return model.ErrMovieNotExists
+	}
+	return nil
+}
@@ -2,20 +2,28 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgx/v5"
 	"movie-lib/internal/model"
 )
 
 const (
+	movieColumns = `"movies"."id", "movies"."title", "movies"."description", "movies"."release_date", "movies"."rating"`
+
 	createMovieQuery = `
 		INSERT INTO "movies" ("title", "description", "release_date", "rating")
 		VALUES ($1, $2, $3, $4)
 		RETURNING "id";`
 
-	addActorToMovieQuery = `
-		INSERT INTO "movie-actor" ("movie-id", actor_id) 
-		VALUES ($1, $2);`
+	// addCastToMovieQuery links actors to the movie, $2 is a JSON array of
+	// castRow, only the first credit of the actor is added
+	addCastToMovieQuery = `
+		INSERT INTO "movie-actor" ("movie-id", "actor_id", "characters", "billing")
+		SELECT $1, "cast"."actor_id", "cast"."characters", "cast"."billing"
+		FROM jsonb_to_recordset($2::jsonb) AS "cast" ("actor_id" bigint, "characters" text[], "billing" integer)
+		ON CONFLICT DO NOTHING;`
 
 	updateMovieQuery = `
 		UPDATE "movies"
@@ -34,207 +42,311 @@ const (
 		WHERE "id" = $1;`
 
 	getMovieQuery = `
-		SELECT * FROM "movies"
+		SELECT ` + movieColumns + ` FROM "movies"
 		WHERE "id" = $1;`
 
-	getMoviesSortByDefaultQuery = `
-		SELECT * FROM "movies"
-		ORDER BY "rating" DESC;`
-
-	getMoviesSortByTitleQuery = `
-		SELECT * FROM "movies"
-		ORDER BY "title";`
-
-	getMoviesSortByRatingQuery = `
-		SELECT * FROM "movies"
-		ORDER BY "rating";`
-
-	getMoviesSortByReleaseDateQuery = `
-		SELECT * FROM "movies"
-		ORDER BY "release_date";`
-
-	getMoviesByPatternQuery = `
-		SELECT "movies"."id", "movies"."title", "movies"."description", "movies"."release_date", "movies"."rating" FROM "movie-actor"
-		INNER JOIN "movies" ON "movies"."id" = "movie-actor"."movie-id"
-		INNER JOIN "actors" ON "actors"."id" = "movie-actor"."actor_id"
-		WHERE "movies"."title" LIKE $1 OR
-			  "actors"."first_name" LIKE $1 OR
-			  "actors"."second_name" LIKE $1
-		GROUP BY "movies"."id";`
-
-	getMovieActorsQuery = `
-		SELECT "actors"."id", "actors"."first_name", "actors"."second_name", "actors"."gender"
+	// movieMatchesQueryCondition selects movies matching the full-text query
+	// $1 written in the syntax of web search engines
+	movieMatchesQueryCondition = `"movies"."search_vector" @@ websearch_to_tsquery('russian', $%[1]d)`
+
+	// searchMoviesTable adds relevance of movies to the full-text query
+	// $%[1]d to use it as a sort key
+	searchMoviesTable = `(
+		SELECT "movies".*,
+			ts_rank("movies"."search_vector", websearch_to_tsquery('russian', $%[1]d))::float8 AS "relevance"
+		FROM "movies") AS "movies"`
+
+	// searchMovieColumns are columns of movies found by the full-text query
+	// $%[1]d: movie columns, the relevance and the snippet of the description
+	// with highlighted matches
+	searchMovieColumns = movieColumns + `, "movies"."relevance",
+		ts_headline('russian', "movies"."description", websearch_to_tsquery('russian', $%[1]d))`
+
+	// getMoviesActorsQuery loads the cast of several movies at once ordered
+	// by billing
+	getMoviesActorsQuery = `
+		SELECT "movie-actor"."movie-id", ` + actorColumns + `,
+			"movie-actor"."characters", "movie-actor"."billing"
 		FROM "movie-actor"
 			INNER JOIN "actors" ON "movie-actor"."actor_id" = "actors"."id"
-		WHERE "movie-actor"."movie-id" = $1;`
+		WHERE "movie-actor"."movie-id" = ANY($1::bigint[])
+		ORDER BY "movie-actor"."billing", "actors"."id";`
 )
 
 func (r *repoImpl) CreateMovie(ctx context.Context, movie model.Movie) (model.Movie, error) {
-	for _, id := range movie.ActorsId {
-		if _, err := r.GetActor(ctx, id); err != nil {
-			return model.Movie{}, err
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if err := tx.QueryRow(ctx, createMovieQuery,
+			movie.Title,
+			movie.Description,
+			movie.ReleaseDate,
+			movie.Rating,
+		).Scan(&movie.Id); err != nil {
+			return mapError(err)
 		}
-	}
 
-	if err := r.QueryRow(ctx, createMovieQuery,
-		movie.Title,
-		movie.Description,
-		movie.ReleaseDate,
-		movie.Rating,
-	).Scan(&movie.Id); err != nil {
-		return model.Movie{}, errors.Join(model.ErrDatabaseError, err)
-	}
+		if err := tx.addMovieCast(ctx, movie.Id, model.MovieCast(movie.Cast, movie.ActorsId)); err != nil {
+			return err
+		}
+		if err := tx.addMovieGenres(ctx, movie.Id, movie.GenresId); err != nil {
+			return err
+		}
+		if err := tx.addMovieCrew(ctx, movie.Id, movie.CrewCredits); err != nil {
+			return err
+		}
 
-	for _, id := range movie.ActorsId {
-		_, _ = r.Exec(ctx, addActorToMovieQuery, movie.Id, id)
+		var err error
+		movie, err = tx.GetMovie(ctx, movie.Id)
+		return err
+	})
+	if err != nil {
+		return model.Movie{}, err
 	}
-
-	return r.GetMovie(ctx, movie.Id)
+	return movie, nil
 }
 
 func (r *repoImpl) UpdateMovie(ctx context.Context, id uint64, upd model.UpdateMovie) (model.Movie, error) {
-	for _, actorId := range upd.Actors {
-		if _, err := r.GetActor(ctx, actorId); err != nil {
-			return model.Movie{}, err
+	var movie model.Movie
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if e, err := tx.Exec(ctx, updateMovieQuery,
+			id,
+			upd.Title,
+			upd.Description,
+			upd.ReleaseDate,
+			upd.Rating,
+		); err != nil {
+			return mapError(err)
+		} else if e.RowsAffected() == 0 {
+			return model.ErrMovieNotExists
 		}
-	}
 
-	if e, err := r.Exec(ctx, updateMovieQuery,
-		id,
-		upd.Title,
-		upd.Description,
-		upd.ReleaseDate,
-		upd.Rating,
-	); err != nil {
-		return model.Movie{}, errors.Join(model.ErrDatabaseError, err)
-	} else if e.RowsAffected() == 0 {
-		return model.Movie{}, model.ErrMovieNotExists
-	}
+		if _, err := tx.Exec(ctx, deleteMovieFromActorsQuery, id); err != nil {
+			return mapError(err)
+		}
 
-	_, _ = r.Exec(ctx, deleteMovieFromActorsQuery, id)
+		if err := tx.addMovieCast(ctx, id, model.MovieCast(upd.Cast, upd.Actors)); err != nil {
+			return err
+		}
 
-	for _, actorId := range upd.Actors {
-		_, _ = r.Exec(ctx, addActorToMovieQuery, id, actorId)
-	}
+		if _, err := tx.Exec(ctx, deleteMovieFromGenresQuery, id); err != nil {
+			return mapError(err)
+		}
+		if err := tx.addMovieGenres(ctx, id, upd.Genres); err != nil {
+			return err
+		}
 
-	return r.GetMovie(ctx, id)
+		if _, err := tx.Exec(ctx, deleteMovieFromCrewQuery, id); err != nil {
+			return mapError(err)
+		}
+		if err := tx.addMovieCrew(ctx, id, upd.Crew); err != nil {
+			return err
+		}
+
+		var err error
+		movie, err = tx.GetMovie(ctx, id)
+		return err
+	})
+	if err != nil {
+		return model.Movie{}, err
+	}
+	return movie, nil
 }
 
+// DeleteMovie deletes movie, its links to actors, genres and crew and its
+// reviews are deleted by cascade
 func (r *repoImpl) DeleteMovie(ctx context.Context, id uint64) error {
 	if e, err := r.Exec(ctx, deleteMovieQuery, id); err != nil {
-		return errors.Join(model.ErrDatabaseError, err)
+		return mapError(err)
 	} else if e.RowsAffected() == 0 {
 		return model.ErrMovieNotExists
 	}
-
-	if _, err := r.Exec(ctx, deleteMovieFromActorsQuery, id); err != nil {
-		return errors.Join(model.ErrDatabaseError, err)
-	}
 	return nil
 }
 
 func (r *repoImpl) GetMovie(ctx context.Context, id uint64) (model.Movie, error) {
-	row := r.QueryRow(ctx, getMovieQuery, id)
-	var movie model.Movie
-	if err := row.Scan(
-		&movie.Id,
-		&movie.Title,
-		&movie.Description,
-		&movie.ReleaseDate,
-		&movie.Rating,
-	); errors.Is(err, pgx.ErrNoRows) {
+	movie, err := scanMovie(r.QueryRow(ctx, getMovieQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
 		return model.Movie{}, model.ErrMovieNotExists
 	} else if err != nil {
 		return model.Movie{}, errors.Join(model.ErrDatabaseError, err)
 	}
 
-	var err error
-	movie.Actors, err = r.getMovieActors(ctx, id)
+	movies := []model.Movie{movie}
+	if err = r.loadMoviesRelations(ctx, movies); err != nil {
+		return model.Movie{}, err
+	}
+	return movies[0], nil
+}
+
+func (r *repoImpl) GetMovies(ctx context.Context, filter model.MovieFilter,
+	sortBy model.Sort, page model.Page) (model.MovieList, error) {
+	keys, err := movieSortKeys(sortBy)
 	if err != nil {
-		return model.Movie{}, errors.Join(model.ErrDatabaseError, err)
+		return model.MovieList{}, err
 	}
-	return movie, nil
+
+	var b conditionBuilder
+	b.addMovieFilter(filter)
+	return r.queryMoviesPage(ctx, `"movies"`, movieColumns, &b, keys, page, scanMovie)
 }
 
-func (r *repoImpl) GetMovies(ctx context.Context, sortBy model.SortParam) ([]model.Movie, error) {
-	var query string
-	switch sortBy {
-	case model.Title:
-		query = getMoviesSortByTitleQuery
-	case model.Rating:
-		query = getMoviesSortByRatingQuery
-	case model.ReleaseDate:
-		query = getMoviesSortByReleaseDateQuery
-	default:
-		query = getMoviesSortByDefaultQuery
+// SearchMovies finds movies by title, names of actors and description using
+// full-text search, words of the query are stemmed, so the search is case
+// insensitive and matches other forms of the words
+func (r *repoImpl) SearchMovies(ctx context.Context, query string, filter model.MovieFilter,
+	sortBy model.Sort, page model.Page) (model.MovieList, error) {
+	keys, err := searchMovieSortKeys(sortBy)
+	if err != nil {
+		return model.MovieList{}, err
 	}
 
-	rows, err := r.Query(ctx, query)
+	var b conditionBuilder
+	queryArg := b.addArg(query)
+	b.add(fmt.Sprintf(movieMatchesQueryCondition, queryArg))
+	b.addMovieFilter(filter)
+	return r.queryMoviesPage(ctx, fmt.Sprintf(searchMoviesTable, queryArg),
+		fmt.Sprintf(searchMovieColumns, queryArg), &b, keys, page, scanFoundMovie)
+}
+
+// queryMoviesPage returns the page of movies matching the conditions with
+// their relations, every relation of all movies is loaded by one additional
+// query
+func (r *repoImpl) queryMoviesPage(ctx context.Context, table, columns string, b *conditionBuilder,
+	keys []sortKey[model.Movie], page model.Page, scan func(row pgx.Row) (model.Movie, error)) (model.MovieList, error) {
+	condition, args := b.where()
+	movies, hasNext, total, err := selectPage(ctx, r, table, columns, keys,
+		condition, args, page, scan)
 	if err != nil {
-		return []model.Movie{}, errors.Join(model.ErrDatabaseError, err)
-	}
-	defer rows.Close()
-
-	movies := make([]model.Movie, 0)
-	for rows.Next() {
-		var movie model.Movie
-		_ = rows.Scan(
-			&movie.Id,
-			&movie.Title,
-			&movie.Description,
-			&movie.ReleaseDate,
-			&movie.Rating,
-		)
-		movies = append(movies, movie)
+		return model.MovieList{}, err
 	}
-	for i := range movies {
-		movies[i].Actors, _ = r.getMovieActors(ctx, movies[i].Id)
+
+	if err = r.loadMoviesRelations(ctx, movies); err != nil {
+		return model.MovieList{}, err
+	}
+	list := model.MovieList{
+		Movies: movies,
+		Total:  total,
+	}
+	if hasNext {
+		list.NextCursor = encodeCursor(keys, movies[len(movies)-1])
+	}
+	return list, nil
+}
+
+// loadMoviesRelations sets actors, genres, crew and the community rating of
+// all given movies
+func (r *repoImpl) loadMoviesRelations(ctx context.Context, movies []model.Movie) error {
+	if err := r.loadMoviesActors(ctx, movies); err != nil {
+		return err
+	}
+	if err := r.loadMoviesGenres(ctx, movies); err != nil {
+		return err
 	}
-	return movies, nil
+	if err := r.loadMoviesCrew(ctx, movies); err != nil {
+		return err
+	}
+	return r.loadMoviesVotes(ctx, movies)
 }
 
-func (r *repoImpl) SearchMovies(ctx context.Context, pattern string) ([]model.Movie, error) {
-	rows, err := r.Query(ctx, getMoviesByPatternQuery, "%"+pattern+"%")
+// loadMoviesActors sets the cast of all given movies using one query
+func (r *repoImpl) loadMoviesActors(ctx context.Context, movies []model.Movie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(movies))
+	byId := make(map[uint64][]model.Actor, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+		byId[movie.Id] = make([]model.Actor, 0)
+	}
+
+	rows, err := r.Query(ctx, getMoviesActorsQuery, ids)
 	if err != nil {
-		return []model.Movie{}, errors.Join(model.ErrDatabaseError, err)
-	}
-	defer rows.Close()
-
-	movies := make([]model.Movie, 0)
-	for rows.Next() {
-		var movie model.Movie
-		_ = rows.Scan(
-			&movie.Id,
-			&movie.Title,
-			&movie.Description,
-			&movie.ReleaseDate,
-			&movie.Rating,
-		)
-		movies = append(movies, movie)
+		return errors.Join(model.ErrDatabaseError, err)
 	}
+	var (
+		movieId uint64
+		actor   model.Actor
+	)
+	if _, err = pgx.ForEachRow(rows, []any{
+		&movieId,
+		&actor.Id,
+		&actor.FirstName,
+		&actor.SecondName,
+		&actor.Gender,
+		&actor.CrewOnly,
+		&actor.Characters,
+		&actor.Billing,
+	}, func() error {
+		byId[movieId] = append(byId[movieId], actor)
+		return nil
+	}); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+
 	for i := range movies {
-		movies[i].Actors, _ = r.getMovieActors(ctx, movies[i].Id)
+		movies[i].Actors = byId[movies[i].Id]
 	}
-	return movies, nil
+	return nil
+}
+
+// castRow is a row of the "movie-actor" table passed to addCastToMovieQuery
+type castRow struct {
+	ActorId    uint64   `json:"actor_id"`
+	Characters []string `json:"characters"`
+	Billing    int      `json:"billing"`
 }
 
-func (r *repoImpl) getMovieActors(ctx context.Context, id uint64) ([]model.Actor, error) {
-	rows, err := r.Query(ctx, getMovieActorsQuery, id)
+func (r *repoImpl) addMovieCast(ctx context.Context, movieId uint64, cast []model.CastCredit) error {
+	if len(cast) == 0 {
+		return nil
+	}
+	rows := make([]castRow, 0, len(cast))
+	actorsId := make([]uint64, 0, len(cast))
+	for _, credit := range cast {
+		actorsId = append(actorsId, credit.ActorId)
+		rows = append(rows, castRow{
+			ActorId:    credit.ActorId,
+			Characters: credit.Characters,
+			Billing:    credit.Billing,
+		})
+	}
+	data, err := json.Marshal(rows)
 	if err != nil {
-		return []model.Actor{}, errors.Join(model.ErrDatabaseError, err)
-	}
-	defer rows.Close()
-
-	actors := make([]model.Actor, 0)
-	for rows.Next() {
-		var actor model.Actor
-		_ = rows.Scan(
-			&actor.Id,
-			&actor.FirstName,
-			&actor.SecondName,
-			&actor.Gender,
-		)
-		actors = append(actors, actor)
-	}
-	return actors, nil
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	if _, err = r.Exec(ctx, addCastToMovieQuery, movieId, string(data)); err != nil {
+		return mapError(err)
+	}
+	if _, err = r.Exec(ctx, markActorsCastQuery, actorsId); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	return nil
+}
+
+func scanMovie(row pgx.Row) (model.Movie, error) {
+	var movie model.Movie
+	err := row.Scan(
+		&movie.Id,
+		&movie.Title,
+		&movie.Description,
+		&movie.ReleaseDate,
+		&movie.Rating,
+	)
+	return movie, err
+}
+
+// scanFoundMovie scans the row of searchMovieColumns
+func scanFoundMovie(row pgx.Row) (model.Movie, error) {
+	var movie model.Movie
+	err := row.Scan(
+		&movie.Id,
+		&movie.Title,
+		&movie.Description,
+		&movie.ReleaseDate,
+		&movie.Rating,
+		&movie.Relevance,
+		&movie.Snippet,
+	)
+	return movie, err
 }
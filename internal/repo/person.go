@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+)
+
+const (
+	addCrewToMovieQuery = `
+		INSERT INTO "movie-crew" ("movie_id", "person_id", "role")
+		SELECT $1, "credits"."person_id", "credits"."role"
+		FROM unnest($2::bigint[], $3::text[]) AS "credits" ("person_id", "role")
+		ON CONFLICT DO NOTHING;`
+
+	deleteMovieFromCrewQuery = `
+		DELETE FROM "movie-crew"
+		WHERE "movie_id" = $1;`
+
+	// getMoviesCrewQuery loads crew of several movies at once, the crew is
+	// ordered by the position of the role in $2 and then by id
+	getMoviesCrewQuery = `
+		SELECT "movie-crew"."movie_id", "movie-crew"."role", ` + actorColumns + `
+		FROM "movie-crew"
+			INNER JOIN "actors" ON "movie-crew"."person_id" = "actors"."id"
+		WHERE "movie-crew"."movie_id" = ANY($1::bigint[])
+		ORDER BY array_position($2::text[], "movie-crew"."role"::text), "actors"."id";`
+
+	// getPersonCreditsQuery returns movies of the person with roles, the cast
+	// has the actor role
+	getPersonCreditsQuery = `
+		SELECT 'actor' AS "role", ` + movieColumns + `
+		FROM "movie-actor"
+			INNER JOIN "movies" ON "movie-actor"."movie-id" = "movies"."id"
+		WHERE "movie-actor"."actor_id" = $1
+		UNION ALL
+		SELECT "movie-crew"."role", ` + movieColumns + `
+		FROM "movie-crew"
+			INNER JOIN "movies" ON "movie-crew"."movie_id" = "movies"."id"
+		WHERE "movie-crew"."person_id" = $1
+		ORDER BY "release_date", "id";`
+)
+
+// GetFilmography returns all movies of the person grouped by roles
+func (r *repoImpl) GetFilmography(ctx context.Context, personId uint64) (model.Filmography, error) {
+	person, err := scanActor(r.QueryRow(ctx, getActorQuery, personId))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Filmography{}, model.ErrPersonNotExists
+	} else if err != nil {
+		return model.Filmography{}, errors.Join(model.ErrDatabaseError, err)
+	}
+
+	rows, err := r.Query(ctx, getPersonCreditsQuery, personId)
+	if err != nil {
+		return model.Filmography{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	credits := make(map[model.CreditRole][]model.Movie)
+	var (
+		role  model.CreditRole
+		movie model.Movie
+	)
+	if _, err = pgx.ForEachRow(rows, []any{
+		&role,
+		&movie.Id,
+		&movie.Title,
+		&movie.Description,
+		&movie.ReleaseDate,
+		&movie.Rating,
+	}, func() error {
+		credits[role] = append(credits[role], movie)
+		return nil
+	}); err != nil {
+		return model.Filmography{}, errors.Join(model.ErrDatabaseError, err)
+	}
+
+	return model.Filmography{
+		Person:  person,
+		Credits: credits,
+	}, nil
+}
+
+// loadMoviesCrew sets crew of all given movies using one query
+func (r *repoImpl) loadMoviesCrew(ctx context.Context, movies []model.Movie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(movies))
+	byId := make(map[uint64][]model.CrewMember, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+		byId[movie.Id] = make([]model.CrewMember, 0)
+	}
+
+	roles := make([]string, 0, len(model.CrewRoles))
+	for _, role := range model.CrewRoles {
+		roles = append(roles, string(role))
+	}
+	rows, err := r.Query(ctx, getMoviesCrewQuery, ids, roles)
+	if err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	var (
+		movieId uint64
+		member  model.CrewMember
+	)
+	if _, err = pgx.ForEachRow(rows, []any{
+		&movieId,
+		&member.Role,
+		&member.Id,
+		&member.FirstName,
+		&member.SecondName,
+		&member.Gender,
+		&member.CrewOnly,
+	}, func() error {
+		byId[movieId] = append(byId[movieId], member)
+		return nil
+	}); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+
+	for i := range movies {
+		movies[i].Crew = byId[movies[i].Id]
+	}
+	return nil
+}
+
+func (r *repoImpl) addMovieCrew(ctx context.Context, movieId uint64, crew []model.CrewCredit) error {
+	if len(crew) == 0 {
+		return nil
+	}
+	personsId := make([]uint64, 0, len(crew))
+	roles := make([]string, 0, len(crew))
+	for _, credit := range crew {
+		personsId = append(personsId, credit.PersonId)
+		roles = append(roles, string(credit.Role))
+	}
+	if _, err := r.Exec(ctx, addCrewToMovieQuery, movieId, personsId, roles); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
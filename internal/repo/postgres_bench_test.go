@@ -0,0 +1,205 @@
+package repo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+	"movie-lib/internal/repo"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// queryCounter is a tracer counting queries sent to the database
+type queryCounter struct {
+	n atomic.Int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	c.n.Add(1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+// count returns number of queries executed by fn
+func (c *queryCounter) count(fn func()) int64 {
+	before := c.n.Load()
+	fn()
+	return c.n.Load() - before
+}
+
+// seedCatalogue creates n movies with two actors each and deletes them
+// when the test is finished
+func seedCatalogue(tb testing.TB, r repo.Repo, n int) {
+	ctx := context.Background()
+	prefix := fmt.Sprintf("bench-%d-", time.Now().UnixNano())
+	movies := make([]uint64, 0, n)
+	actors := make([]uint64, 0, 2*n)
+	tb.Cleanup(func() {
+		for _, id := range movies {
+			_ = r.DeleteMovie(ctx, id)
+		}
+		for _, id := range actors {
+			_ = r.DeleteActor(ctx, id)
+		}
+	})
+
+	for i := 0; i < n; i++ {
+		actorsId := make([]uint64, 0, 2)
+		for j := 0; j < 2; j++ {
+			actor, err := r.CreateActor(ctx, model.Actor{
+				FirstName:  fmt.Sprintf("%sActor%d-%d", prefix, i, j),
+				SecondName: prefix + "Surname",
+				Gender:     model.Male,
+			})
+			if err != nil {
+				tb.Fatalf("unable to create actor: %s", err.Error())
+			}
+			actors = append(actors, actor.Id)
+			actorsId = append(actorsId, actor.Id)
+		}
+		movie, err := r.CreateMovie(ctx, model.Movie{
+			Title:       fmt.Sprintf("%sMovie%d", prefix, i),
+			ReleaseDate: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Rating:      float64(i % 11),
+			ActorsId:    actorsId,
+		})
+		if err != nil {
+			tb.Fatalf("unable to create movie: %s", err.Error())
+		}
+		movies = append(movies, movie.Id)
+	}
+}
+
+// listQueries are read operations which load relations of every returned
+// entity and should use the same number of queries for any catalogue size
+var listQueries = map[string]func(ctx context.Context, r repo.Repo) error{
+	"GetMovies": func(ctx context.Context, r repo.Repo) error {
+		_, err := r.GetMovies(ctx, model.MovieFilter{}, model.ParseSort("title"), model.Page{Limit: model.MaxPageLimit})
+		return err
+	},
+	"SearchMovies": func(ctx context.Context, r repo.Repo) error {
+		_, err := r.SearchMovies(ctx, "Surname", model.MovieFilter{}, model.ParseSort("title"), model.Page{Limit: model.MaxPageLimit})
+		return err
+	},
+	"SearchActors": func(ctx context.Context, r repo.Repo) error {
+		_, err := r.SearchActors(ctx, "surname", model.ActorFilter{}, model.ParseSort("name"), model.Page{Limit: model.MaxPageLimit})
+		return err
+	},
+	"GetActors": func(ctx context.Context, r repo.Repo) error {
+		_, err := r.GetActors(ctx, model.ActorFilter{}, model.ParseSort("-movies_count"), model.Page{Limit: model.MaxPageLimit})
+		return err
+	},
+}
+
+func TestPostgresListQueriesCount(t *testing.T) {
+	counter := &queryCounter{}
+	r := repo.New(connectToTestPostgres(t, counter))
+	ctx := context.Background()
+
+	for name, query := range listQueries {
+		seedCatalogue(t, r, 1)
+		small := counter.count(func() {
+			if err := query(ctx, r); err != nil {
+				t.Fatalf("%s: %s", name, err.Error())
+			}
+		})
+
+		seedCatalogue(t, r, 20)
+		large := counter.count(func() {
+			if err := query(ctx, r); err != nil {
+				t.Fatalf("%s: %s", name, err.Error())
+			}
+		})
+
+		if small != large {
+			t.Errorf("%s: %d queries with small catalogue, %d with large one", name, small, large)
+		}
+	}
+}
+
+func BenchmarkPostgresListQueries(b *testing.B) {
+	counter := &queryCounter{}
+	r := repo.New(connectToTestPostgres(b, counter))
+	ctx := context.Background()
+
+	for _, size := range []int{10, 100} {
+		seedCatalogue(b, r, size)
+		for name, query := range listQueries {
+			b.Run(fmt.Sprintf("%s/%d", name, size), func(b *testing.B) {
+				var queries int64
+				for i := 0; i < b.N; i++ {
+					queries += counter.count(func() {
+						if err := query(ctx, r); err != nil {
+							b.Fatalf("%s: %s", name, err.Error())
+						}
+					})
+				}
+				b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+			})
+		}
+	}
+}
+
+// TestPostgresSuggestPlan checks that every part of the suggestions is read
+// by the prefix index in the generic plan, which is used for prepared
+// statements after several executions
+func TestPostgresSuggestPlan(t *testing.T) {
+	pool := connectToTestPostgres(t, nil)
+	ctx := context.Background()
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("unable to acquire connection: %s", err.Error())
+	}
+	// the settings are not returned to the pool
+	defer conn.Hijack().Close(ctx)
+
+	for _, query := range []string{
+		"SET plan_cache_mode = force_generic_plan",
+		"SET enable_seqscan = off",
+		"PREPARE suggest_plan(text, bigint, bigint) AS " + strings.TrimSuffix(repo.SuggestQuery, ";"),
+	} {
+		if _, err = conn.Exec(ctx, query); err != nil {
+			t.Fatalf("unable to prepare the plan: %s", err.Error())
+		}
+	}
+	var plan string
+	err = conn.QueryRow(ctx, fmt.Sprintf("EXPLAIN (FORMAT JSON) EXECUTE suggest_plan('ma', 10, %d)",
+		repo.SuggestCandidatesLimit)).Scan(&plan)
+	if err != nil {
+		t.Fatalf("unable to explain the plan: %s", err.Error())
+	}
+
+	for _, node := range []string{`"Seq Scan"`, `"Bitmap Heap Scan"`} {
+		if strings.Contains(plan, node) {
+			t.Errorf("suggestions are read without the prefix index: %s", plan)
+		}
+	}
+	if n := strings.Count(plan, `"Index Scan"`); n != 3 {
+		t.Errorf("%d index scans instead of 3: %s", n, plan)
+	}
+}
+
+// BenchmarkPostgresSuggest measures suggestions for the short prefix shared
+// by the whole catalogue under concurrent requests, the time should stay flat
+// as the catalogue grows
+func BenchmarkPostgresSuggest(b *testing.B) {
+	r := repo.New(connectToTestPostgres(b, nil))
+	ctx := context.Background()
+
+	for _, size := range []int{100, 1000} {
+		seedCatalogue(b, r, size)
+		b.Run(fmt.Sprintf("Suggest/%d", size), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := r.Suggest(ctx, "be", model.DefaultSuggestLimit); err != nil {
+						b.Fatalf("Suggest: %s", err.Error())
+					}
+				}
+			})
+		})
+	}
+}
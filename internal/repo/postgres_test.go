@@ -0,0 +1,76 @@
+package repo_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+	"movie-lib/internal/repo"
+	"movie-lib/internal/repo/repotest"
+	"movie-lib/migrations"
+	"movie-lib/pkg/migrator"
+	"testing"
+	"time"
+)
+
+// connectToTestPostgres connects to the database from the local config and
+// applies migrations or skips the test if the database is not available.
+// Tracer is optional and is set to all connections of the pool.
+func connectToTestPostgres(t testing.TB, tracer pgx.QueryTracer) *pgxpool.Pool {
+	v := viper.New()
+	v.SetConfigFile("../../config/config-local.yml")
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("unable to read configs: %s", err.Error())
+	}
+	url := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		v.GetString("postgres-movie-lib.username"),
+		v.GetString("postgres-movie-lib.password"),
+		v.GetString("postgres-movie-lib.host"),
+		v.GetInt("postgres-movie-lib.port"),
+		v.GetString("postgres-movie-lib.dbname"),
+		v.GetString("postgres-movie-lib.sslmode"),
+	)
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("unable to parse postgres config: %s", err.Error())
+	}
+	config.ConnConfig.Tracer = tracer
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		t.Fatalf("unable to create postgres pool: %s", err.Error())
+	}
+	for i := 0; i < 3; i++ { // 3 attempts to connect to postgres
+		if err = pool.Ping(context.Background()); err == nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		pool.Close()
+		t.Skipf("postgres is not available: %s", err.Error())
+	}
+	t.Cleanup(pool.Close)
+
+	m, err := migrator.New(pool, migrations.FS)
+	if err != nil {
+		t.Fatalf("unable to load migrations: %s", err.Error())
+	}
+	if _, err = m.Up(context.Background()); err != nil {
+		t.Fatalf("unable to apply migrations: %s", err.Error())
+	}
+	return pool
+}
+
+func TestPostgresRepo(t *testing.T) {
+	pool := connectToTestPostgres(t, nil)
+	suite.Run(t, &repotest.Suite{
+		NewRepo: func() repo.Repo {
+			return repo.New(pool)
+		},
+	})
+}
@@ -1,9 +1,51 @@
 package repo
 
 import (
+	"context"
+	"errors"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"movie-lib/internal/model"
 )
 
+// database is implemented both by *pgxpool.Pool and pgx.Tx, so the same
+// queries can be executed inside and outside of transaction
+type database interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type repoImpl struct {
-	*pgx.Conn
+	database
+	pool *pgxpool.Pool
+}
+
+func (r *repoImpl) WithTx(ctx context.Context, fn func(r Repo) error) error {
+	return r.inTx(ctx, func(tx *repoImpl) error {
+		return fn(tx)
+	})
+}
+
+// inTx executes fn in transaction which is committed if fn returns nil and
+// rolled back otherwise. Nested calls use savepoints.
+func (r *repoImpl) inTx(ctx context.Context, fn func(tx *repoImpl) error) error {
+	tx, err := r.Begin(ctx)
+	if err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // does nothing if transaction is committed
+	}()
+
+	if err = fn(&repoImpl{database: tx, pool: r.pool}); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	return nil
 }
@@ -2,8 +2,9 @@ package repo
 
 import (
 	"context"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"movie-lib/internal/model"
+	"time"
 )
 
 type Repo interface {
@@ -11,20 +12,131 @@ type Repo interface {
 	UpdateMovie(ctx context.Context, id uint64, upd model.UpdateMovie) (model.Movie, error)
 	DeleteMovie(ctx context.Context, id uint64) error
 	GetMovie(ctx context.Context, id uint64) (model.Movie, error)
-	GetMovies(ctx context.Context, sortBy model.SortParam) ([]model.Movie, error)
-	SearchMovies(ctx context.Context, pattern string) ([]model.Movie, error)
+	GetMovies(ctx context.Context, filter model.MovieFilter, sortBy model.Sort, page model.Page) (model.MovieList, error)
+	SearchMovies(ctx context.Context, query string, filter model.MovieFilter, sortBy model.Sort, page model.Page) (model.MovieList, error)
 
 	CreateActor(ctx context.Context, actor model.Actor) (model.Actor, error)
 	UpdateActor(ctx context.Context, id uint64, upd model.UpdateActor) (model.Actor, error)
 	DeleteActor(ctx context.Context, id uint64) error
 	GetActor(ctx context.Context, id uint64) (model.Actor, error)
-	GetActors(ctx context.Context) ([]model.Actor, error)
+	GetActors(ctx context.Context, filter model.ActorFilter, sortBy model.Sort, page model.Page) (model.ActorList, error)
+	SearchActors(ctx context.Context, pattern string, filter model.ActorFilter, sortBy model.Sort, page model.Page) (model.ActorList, error)
+	// GetFilmography returns all movies of the person grouped by roles
+	GetFilmography(ctx context.Context, personId uint64) (model.Filmography, error)
 
+	CreateGenre(ctx context.Context, genre model.Genre) (model.Genre, error)
+	UpdateGenre(ctx context.Context, id uint64, upd model.UpdateGenre) (model.Genre, error)
+	DeleteGenre(ctx context.Context, id uint64) error
+	GetGenre(ctx context.Context, id uint64) (model.Genre, error)
+	// GetGenres returns all genres ordered by name
+	GetGenres(ctx context.Context) ([]model.Genre, error)
+
+	// CreateReview adds the review of the user, the user can review the movie
+	// only once
+	CreateReview(ctx context.Context, review model.Review) (model.Review, error)
+	UpdateReview(ctx context.Context, id uint64, upd model.UpdateReview) (model.Review, error)
+	DeleteReview(ctx context.Context, id uint64) error
+	GetReview(ctx context.Context, id uint64) (model.Review, error)
+	// GetReviews returns the page of reviews matching the filter, the newest
+	// reviews go first
+	GetReviews(ctx context.Context, filter model.ReviewFilter, page model.Page) (model.ReviewList, error)
+
+	// AddToWatchlist adds the movie to the watchlist of the user, adding of
+	// the movie which is already there is not an error
+	AddToWatchlist(ctx context.Context, userId, movieId uint64) error
+	RemoveFromWatchlist(ctx context.Context, userId, movieId uint64) error
+	// MarkWatched adds the movie to the watched history of the user or
+	// changes the date of watching if the movie is already there
+	MarkWatched(ctx context.Context, userId, movieId uint64, watchedAt time.Time) error
+	UnmarkWatched(ctx context.Context, userId, movieId uint64) error
+	// GetMoviesStatus returns statuses of the movies in the lists of the
+	// user, movies which are not in the lists are omitted
+	GetMoviesStatus(ctx context.Context, userId uint64, moviesId []uint64) (map[uint64]model.MovieStatus, error)
+
+	CreateCollection(ctx context.Context, collection model.Collection) (model.Collection, error)
+	// UpdateCollection changes the collection and replaces users it is
+	// shared with, entries are not changed
+	UpdateCollection(ctx context.Context, id uint64, upd model.UpdateCollection) (model.Collection, error)
+	DeleteCollection(ctx context.Context, id uint64) error
+	// GetCollection returns the collection with its entries and users it is
+	// shared with
+	GetCollection(ctx context.Context, id uint64) (model.Collection, error)
+	// GetCollections returns the page of collections matching the filter
+	// without entries and users they are shared with
+	GetCollections(ctx context.Context, filter model.CollectionFilter, page model.Page) (model.CollectionList, error)
+	// AddCollectionEntry appends the movie to the end of the collection
+	AddCollectionEntry(ctx context.Context, collectionId uint64, entry model.CollectionEntry) error
+	// UpdateCollectionEntry changes the note of the movie in the collection
+	UpdateCollectionEntry(ctx context.Context, collectionId uint64, entry model.CollectionEntry) error
+	RemoveCollectionEntry(ctx context.Context, collectionId, movieId uint64) error
+	// ReorderCollection orders entries of the collection as the movies,
+	// moviesId should contain every movie of the collection exactly once
+	ReorderCollection(ctx context.Context, collectionId uint64, moviesId []uint64) error
+
+	// FuzzySearch finds movies and actors with titles and names similar to
+	// the query ordered by similarity
+	FuzzySearch(ctx context.Context, query string, limit int) (model.FuzzySearchResult, error)
+
+	// Suggest returns movies and actors whose titles or names start with the
+	// prefix ignoring case, shorter titles and names go first
+	Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error)
+
+	// GetUserRole returns the role of the active user, disabled users are
+	// reported as not existing
 	GetUserRole(ctx context.Context, id uint64) (model.Role, error)
+	CreateUser(ctx context.Context, user model.User) (model.User, error)
+	// UpdateUser changes the user and returns model.ErrLastAdmin if no active
+	// admins would be left
+	UpdateUser(ctx context.Context, id uint64, upd model.UpdateUser) (model.User, error)
+	// DeleteUser deletes the user with all their data and returns
+	// model.ErrLastAdmin if no active admins would be left
+	DeleteUser(ctx context.Context, id uint64) error
+	GetUser(ctx context.Context, id uint64) (model.User, error)
+	GetUsers(ctx context.Context, page model.Page) (model.UserList, error)
+
+	// GetUserCredentials returns the password hash of the user with the
+	// username regardless of case
+	GetUserCredentials(ctx context.Context, username string) (model.Credentials, error)
+	SetPasswordHash(ctx context.Context, userId uint64, hash string) error
+	// CreateSession creates the session and deletes expired sessions of the
+	// user
+	CreateSession(ctx context.Context, session model.Session) (model.Session, error)
+	GetSession(ctx context.Context, id uint64) (model.Session, error)
+	// RefreshSession replaces the refresh token hash of the unexpired session
+	// and prolongs it, the old hash can not be used again
+	RefreshSession(ctx context.Context, oldHash string, newHash string, expiresAt time.Time) (model.Session, error)
+	DeleteSession(ctx context.Context, id uint64) error
+	DeleteUserSessions(ctx context.Context, userId uint64) error
+
+	CreateApiKey(ctx context.Context, key model.ApiKey) (model.ApiKey, error)
+	GetApiKey(ctx context.Context, id uint64) (model.ApiKey, error)
+	// GetApiKeyByHash returns the unexpired API key with the hash
+	GetApiKeyByHash(ctx context.Context, hash string) (model.ApiKey, error)
+	// GetApiKeys returns all API keys of the user including expired ones
+	// ordered by id
+	GetApiKeys(ctx context.Context, userId uint64) ([]model.ApiKey, error)
+	DeleteApiKey(ctx context.Context, id uint64) error
+
+	CreateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error)
+	// UpdateRole replaces permissions of the role
+	UpdateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error)
+	// DeleteRole deletes the role, roles assigned to users can not be deleted
+	DeleteRole(ctx context.Context, name model.Role) error
+	GetRole(ctx context.Context, name model.Role) (model.RolePermissions, error)
+	// GetRoles returns all roles ordered by name
+	GetRoles(ctx context.Context) ([]model.RolePermissions, error)
+
+	// PoolStats returns statistics of the database connection pool
+	PoolStats(ctx context.Context) model.PoolStats
+
+	// WithTx executes fn in transaction, all operations made through the Repo
+	// passed to fn are committed if fn returns nil and rolled back otherwise
+	WithTx(ctx context.Context, fn func(r Repo) error) error
 }
 
-func New(conn *pgx.Conn) Repo {
+func New(pool *pgxpool.Pool) Repo {
 	return &repoImpl{
-		Conn: conn,
+		database: pool,
+		pool:     pool,
 	}
 }
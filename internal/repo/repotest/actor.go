@@ -0,0 +1,236 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (s *Suite) TestCreateActor() {
+	actor := s.createActor("Actor", model.Female)
+	s.Equal(s.prefix+"Actor", actor.FirstName)
+	s.Equal(s.prefix+"Surname", actor.SecondName)
+	s.Equal(model.Female, actor.Gender)
+
+	got, err := s.r.GetActor(s.ctx, actor.Id)
+	s.Require().NoError(err)
+	s.Equal(actor.Id, got.Id)
+	s.Equal(actor.FirstName, got.FirstName)
+	s.Equal(actor.SecondName, got.SecondName)
+	s.Equal(actor.Gender, got.Gender)
+	s.NotNil(got.Movies)
+	s.Empty(got.Movies)
+}
+
+func (s *Suite) TestCreateActorWithInvalidGender() {
+	actor, err := s.r.CreateActor(s.ctx, model.Actor{
+		FirstName: s.prefix + "Actor",
+		Gender:    "unknown",
+	})
+	if err == nil {
+		s.actorsIdsToDelete = append(s.actorsIdsToDelete, actor.Id)
+	}
+	s.ErrorIs(err, model.ErrValidationError)
+
+	actor = s.createActor("Actor", model.Male)
+	_, err = s.r.UpdateActor(s.ctx, actor.Id, model.UpdateActor{
+		FirstName: s.prefix + "Actor",
+		Gender:    "unknown",
+	})
+	s.ErrorIs(err, model.ErrValidationError)
+}
+
+func (s *Suite) TestUpdateActor() {
+	actor := s.createActor("Actor", model.Male)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor.Id)
+
+	updated, err := s.r.UpdateActor(s.ctx, actor.Id, model.UpdateActor{
+		FirstName:  s.prefix + "NewFirstName",
+		SecondName: s.prefix + "NewSecondName",
+		Gender:     model.Female,
+	})
+	s.Require().NoError(err)
+	s.Equal(actor.Id, updated.Id)
+	s.Equal(s.prefix+"NewFirstName", updated.FirstName)
+	s.Equal(s.prefix+"NewSecondName", updated.SecondName)
+	s.Equal(model.Female, updated.Gender)
+	s.Equal([]uint64{movie.Id}, moviesIds(updated.Movies))
+
+	_, err = s.r.UpdateActor(s.ctx, 0, model.UpdateActor{FirstName: s.prefix + "Name"})
+	s.ErrorIs(err, model.ErrActorNotExists)
+}
+
+func (s *Suite) TestDeleteActor() {
+	actor1 := s.createActor("Actor1", model.Male)
+	actor2 := s.createActor("Actor2", model.Male)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor1.Id, actor2.Id)
+
+	s.Require().NoError(s.r.DeleteActor(s.ctx, actor1.Id))
+	s.ErrorIs(s.r.DeleteActor(s.ctx, actor1.Id), model.ErrActorNotExists)
+
+	_, err := s.r.GetActor(s.ctx, actor1.Id)
+	s.ErrorIs(err, model.ErrActorNotExists)
+
+	// deleted actor is removed from the cast of the movie
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal([]uint64{actor2.Id}, actorsIds(got.Actors))
+}
+
+func (s *Suite) TestGetActorMovies() {
+	actor := s.createActor("Actor", model.Male)
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1), actor.Id)
+	m2 := s.createMovie("Movie2", 6, date(2021, time.January, 1), actor.Id)
+
+	got, err := s.r.GetActor(s.ctx, actor.Id)
+	s.Require().NoError(err)
+	s.ElementsMatch([]uint64{m1.Id, m2.Id}, moviesIds(got.Movies))
+	for _, movie := range got.Movies {
+		if movie.Id == m1.Id {
+			s.Equal(m1.Title, movie.Title)
+			s.Equal(m1.Rating, movie.Rating)
+			s.True(m1.ReleaseDate.Equal(movie.ReleaseDate))
+		}
+	}
+}
+
+func (s *Suite) TestGetActorNotExists() {
+	_, err := s.r.GetActor(s.ctx, 0)
+	s.ErrorIs(err, model.ErrActorNotExists)
+}
+
+func (s *Suite) TestGetActors() {
+	actor1 := s.createActor("Actor1", model.Male)
+	actor2 := s.createActor("Actor2", model.Female)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor1.Id)
+
+	got := s.allActors("")
+	s.ElementsMatch([]uint64{actor1.Id, actor2.Id}, filterIds(actorsIds(got), actor1.Id, actor2.Id))
+	for _, actor := range got {
+		switch actor.Id {
+		case actor1.Id:
+			s.Equal([]uint64{movie.Id}, moviesIds(actor.Movies))
+		case actor2.Id:
+			s.Empty(actor.Movies)
+		}
+	}
+}
+
+func (s *Suite) TestGetActorsSortOrders() {
+	a1 := s.createActor("B", model.Male)
+	a2 := s.createActor("A", model.Male)
+	a3 := s.createActor("C", model.Male)
+	s.createMovie("Movie1", 5, date(2020, time.January, 1), a1.Id, a3.Id)
+	s.createMovie("Movie2", 5, date(2020, time.January, 1), a1.Id)
+
+	tests := []struct {
+		sortBy string
+		want   []uint64
+	}{
+		{sortBy: "", want: []uint64{a1.Id, a2.Id, a3.Id}},
+		{sortBy: "name", want: []uint64{a2.Id, a1.Id, a3.Id}},
+		{sortBy: "-name", want: []uint64{a3.Id, a1.Id, a2.Id}},
+		{sortBy: "movies_count", want: []uint64{a2.Id, a3.Id, a1.Id}},
+		{sortBy: "-movies_count,name", want: []uint64{a1.Id, a3.Id, a2.Id}},
+	}
+
+	for _, test := range tests {
+		got := s.allActors(test.sortBy)
+		s.Equal(test.want, filterIds(actorsIds(got), a1.Id, a2.Id, a3.Id), "sort by %q", test.sortBy)
+	}
+
+	// cursor keeps the number of movies of the last actor of the page
+	sortBy := model.ParseSort("-movies_count,name")
+	ids := make([]uint64, 0)
+	page := model.Page{Limit: 2}
+	for {
+		list, err := s.r.GetActors(s.ctx, model.ActorFilter{}, sortBy, page)
+		s.Require().NoError(err)
+		ids = append(ids, actorsIds(list.Actors)...)
+		if list.NextCursor == "" {
+			break
+		}
+		page.Cursor = list.NextCursor
+	}
+	s.Equal([]uint64{a1.Id, a3.Id, a2.Id}, filterIds(ids, a1.Id, a2.Id, a3.Id))
+}
+
+func (s *Suite) TestSearchActors() {
+	a1 := s.createActor("Keanu", model.Male)
+	a2 := s.createActor("Carrie-Anne", model.Female)
+	a3 := s.createActor("Laurence", model.Male)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), a1.Id, a2.Id)
+	s.createMovie("Other", 5, date(2020, time.January, 1), a3.Id)
+
+	tests := []struct {
+		description string
+		pattern     string
+		filter      model.ActorFilter
+		want        []uint64
+	}{
+		{
+			description: "search by first name ignores case",
+			pattern:     s.prefix + "keanu",
+			want:        []uint64{a1.Id},
+		},
+		{
+			description: "search by part of second name",
+			pattern:     s.prefix + "surn",
+			want:        []uint64{a1.Id, a2.Id, a3.Id},
+		},
+		{
+			description: "every word matches first or second name",
+			pattern:     "anne " + s.prefix + "SURNAME",
+			want:        []uint64{a2.Id},
+		},
+		{
+			description: "LIKE wildcards are matched literally",
+			pattern:     s.prefix + "K%u",
+			want:        []uint64{},
+		},
+		{
+			description: "search with gender",
+			pattern:     s.prefix,
+			filter:      model.ActorFilter{Gender: model.Male},
+			want:        []uint64{a1.Id, a3.Id},
+		},
+		{
+			description: "search of actors of the movie",
+			pattern:     s.prefix,
+			filter:      model.ActorFilter{MovieId: movie.Id},
+			want:        []uint64{a1.Id, a2.Id},
+		},
+		{
+			description: "search of actors of the movie with gender",
+			pattern:     s.prefix,
+			filter:      model.ActorFilter{Gender: model.Female, MovieId: movie.Id},
+			want:        []uint64{a2.Id},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := s.r.SearchActors(s.ctx, test.pattern, test.filter, nil, model.Page{Limit: model.MaxPageLimit})
+		s.Require().NoError(err)
+		s.Equal(test.want, filterIds(actorsIds(got.Actors), a1.Id, a2.Id, a3.Id), test.description)
+		for _, actor := range got.Actors {
+			if actor.Id == a1.Id {
+				s.Equal([]uint64{movie.Id}, moviesIds(actor.Movies))
+			}
+		}
+	}
+
+	// filters are applied without the pattern too
+	list, err := s.r.GetActors(s.ctx, model.ActorFilter{MovieId: movie.Id}, nil, model.Page{Limit: model.MaxPageLimit})
+	s.Require().NoError(err)
+	s.Equal([]uint64{a1.Id, a2.Id}, actorsIds(list.Actors))
+	s.Equal(uint64(2), list.Total)
+}
+
+func (s *Suite) TestUnknownSortParam() {
+	_, err := s.r.GetMovies(s.ctx, model.MovieFilter{}, model.ParseSort("unknown"), model.Page{Limit: 1})
+	s.ErrorIs(err, model.ErrValidationError)
+	_, err = s.r.GetActors(s.ctx, model.ActorFilter{}, model.ParseSort("title"), model.Page{Limit: 1})
+	s.ErrorIs(err, model.ErrValidationError)
+	// relevance is available only for the search
+	_, err = s.r.GetMovies(s.ctx, model.MovieFilter{}, model.ParseSort("relevance"), model.Page{Limit: 1})
+	s.ErrorIs(err, model.ErrValidationError)
+}
@@ -0,0 +1,108 @@
+package repotest
+
+import (
+	"movie-lib/internal/auth"
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+func (s *Suite) TestApiKeys() {
+	user := s.createUser("ApiKeys", model.Regular)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+
+	key, err := s.r.CreateApiKey(s.ctx, model.ApiKey{
+		UserId:    user.Id,
+		Name:      "ingestion",
+		Prefix:    "mlk_first",
+		KeyHash:   auth.HashToken(s.prefix + "first"),
+		Scope:     model.ReadScope,
+		ExpiresAt: &expiresAt,
+	})
+	s.Require().NoError(err)
+	s.NotZero(key.Id)
+	s.Equal(user.Id, key.UserId)
+	s.Equal("mlk_first", key.Prefix)
+	s.Require().NotNil(key.ExpiresAt)
+	s.Equal(expiresAt, *key.ExpiresAt)
+	s.WithinDuration(time.Now(), key.CreatedAt, time.Minute)
+
+	got, err := s.r.GetApiKey(s.ctx, key.Id)
+	s.Require().NoError(err)
+	s.Equal(key, got)
+	got, err = s.r.GetApiKeyByHash(s.ctx, key.KeyHash)
+	s.Require().NoError(err)
+	s.Equal(key, got)
+
+	// keys without the expiration time never expire, expired keys are not
+	// found by hash but are listed
+	forever, err := s.r.CreateApiKey(s.ctx, model.ApiKey{
+		UserId:  user.Id,
+		Name:    "import",
+		Prefix:  "mlk_second",
+		KeyHash: auth.HashToken(s.prefix + "second"),
+		Scope:   model.WriteScope,
+	})
+	s.Require().NoError(err)
+	s.Nil(forever.ExpiresAt)
+	past := time.Now().Add(-time.Minute)
+	expired, err := s.r.CreateApiKey(s.ctx, model.ApiKey{
+		UserId:    user.Id,
+		Name:      "expired",
+		Prefix:    "mlk_third",
+		KeyHash:   auth.HashToken(s.prefix + "third"),
+		Scope:     model.WriteScope,
+		ExpiresAt: &past,
+	})
+	s.Require().NoError(err)
+	_, err = s.r.GetApiKeyByHash(s.ctx, forever.KeyHash)
+	s.NoError(err)
+	_, err = s.r.GetApiKeyByHash(s.ctx, expired.KeyHash)
+	s.ErrorIs(err, model.ErrApiKeyNotExists)
+
+	keys, err := s.r.GetApiKeys(s.ctx, user.Id)
+	s.Require().NoError(err)
+	s.Equal([]model.ApiKey{key, forever, expired}, keys)
+
+	_, err = s.r.CreateApiKey(s.ctx, model.ApiKey{
+		UserId:  user.Id,
+		Name:    "duplicate",
+		Prefix:  "mlk_first",
+		KeyHash: key.KeyHash,
+		Scope:   model.ReadScope,
+	})
+	s.ErrorIs(err, model.ErrConflict)
+	_, err = s.r.CreateApiKey(s.ctx, model.ApiKey{
+		UserId:  0,
+		Name:    "unknown user",
+		Prefix:  "mlk_unknown",
+		KeyHash: auth.HashToken(s.prefix + "unknown"),
+		Scope:   model.ReadScope,
+	})
+	s.ErrorIs(err, model.ErrUserNotExists)
+	for name, k := range map[string]model.ApiKey{
+		"empty name":    {Name: "", KeyHash: auth.HashToken(s.prefix + "a"), Scope: model.ReadScope},
+		"long name":     {Name: strings.Repeat("a", 101), KeyHash: auth.HashToken(s.prefix + "b"), Scope: model.ReadScope},
+		"empty hash":    {Name: "hash", KeyHash: "", Scope: model.ReadScope},
+		"unknown scope": {Name: "scope", KeyHash: auth.HashToken(s.prefix + "c"), Scope: "admin"},
+	} {
+		k.UserId = user.Id
+		_, err = s.r.CreateApiKey(s.ctx, k)
+		s.ErrorIs(err, model.ErrValidationError, name)
+	}
+
+	s.Require().NoError(s.r.DeleteApiKey(s.ctx, key.Id))
+	_, err = s.r.GetApiKey(s.ctx, key.Id)
+	s.ErrorIs(err, model.ErrApiKeyNotExists)
+	_, err = s.r.GetApiKeyByHash(s.ctx, key.KeyHash)
+	s.ErrorIs(err, model.ErrApiKeyNotExists)
+	s.ErrorIs(s.r.DeleteApiKey(s.ctx, key.Id), model.ErrApiKeyNotExists)
+
+	// keys are deleted with the user
+	s.Require().NoError(s.r.DeleteUser(s.ctx, user.Id))
+	_, err = s.r.GetApiKey(s.ctx, forever.Id)
+	s.ErrorIs(err, model.ErrApiKeyNotExists)
+	keys, err = s.r.GetApiKeys(s.ctx, user.Id)
+	s.Require().NoError(err)
+	s.Empty(keys)
+}
@@ -0,0 +1,138 @@
+package repotest
+
+import (
+	"movie-lib/internal/auth"
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+func (s *Suite) TestUserCredentials() {
+	user := s.createUser("Credentials", model.Regular)
+
+	// users are created without a password
+	creds, err := s.r.GetUserCredentials(s.ctx, user.Username)
+	s.Require().NoError(err)
+	s.Equal(model.Credentials{UserId: user.Id}, creds)
+
+	s.Require().NoError(s.r.SetPasswordHash(s.ctx, user.Id, "hash"))
+	creds, err = s.r.GetUserCredentials(s.ctx, strings.ToUpper(user.Username))
+	s.Require().NoError(err)
+	s.Equal(model.Credentials{UserId: user.Id, PasswordHash: "hash"}, creds)
+
+	_, err = s.r.UpdateUser(s.ctx, user.Id, model.UpdateUser{
+		Username: user.Username,
+		Role:     model.Regular,
+		Disabled: true,
+	})
+	s.Require().NoError(err)
+	creds, err = s.r.GetUserCredentials(s.ctx, user.Username)
+	s.Require().NoError(err)
+	s.True(creds.Disabled)
+
+	s.Require().NoError(s.r.SetPasswordHash(s.ctx, user.Id, ""))
+	creds, err = s.r.GetUserCredentials(s.ctx, user.Username)
+	s.Require().NoError(err)
+	s.Empty(creds.PasswordHash)
+
+	s.ErrorIs(s.r.SetPasswordHash(s.ctx, 0, "hash"), model.ErrUserNotExists)
+	_, err = s.r.GetUserCredentials(s.ctx, s.prefix+"Unknown")
+	s.ErrorIs(err, model.ErrUserNotExists)
+}
+
+func (s *Suite) TestSessions() {
+	user := s.createUser("Sessions", model.Regular)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+
+	session, err := s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: auth.HashToken(s.prefix + "first"),
+		ExpiresAt:        expiresAt,
+	})
+	s.Require().NoError(err)
+	s.NotZero(session.Id)
+	s.Equal(expiresAt, session.ExpiresAt)
+	s.WithinDuration(time.Now(), session.CreatedAt, time.Minute)
+	got, err := s.r.GetSession(s.ctx, session.Id)
+	s.Require().NoError(err)
+	s.Equal(session, got)
+
+	// refresh token hashes are unique
+	_, err = s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: session.RefreshTokenHash,
+		ExpiresAt:        expiresAt,
+	})
+	s.ErrorIs(err, model.ErrConflict)
+	_, err = s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: "",
+		ExpiresAt:        expiresAt,
+	})
+	s.ErrorIs(err, model.ErrValidationError)
+	_, err = s.r.CreateSession(s.ctx, model.Session{
+		UserId:           0,
+		RefreshTokenHash: auth.HashToken(s.prefix + "unknown"),
+		ExpiresAt:        expiresAt,
+	})
+	s.ErrorIs(err, model.ErrUserNotExists)
+
+	// the old hash can not be used after the refresh
+	newExpiresAt := expiresAt.Add(time.Hour)
+	refreshed, err := s.r.RefreshSession(s.ctx, session.RefreshTokenHash,
+		auth.HashToken(s.prefix+"second"), newExpiresAt)
+	s.Require().NoError(err)
+	s.Equal(session.Id, refreshed.Id)
+	s.Equal(auth.HashToken(s.prefix+"second"), refreshed.RefreshTokenHash)
+	s.Equal(newExpiresAt, refreshed.ExpiresAt)
+	_, err = s.r.RefreshSession(s.ctx, session.RefreshTokenHash,
+		auth.HashToken(s.prefix+"third"), newExpiresAt)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+
+	// expired sessions can be neither got nor refreshed
+	expired, err := s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: auth.HashToken(s.prefix + "expired"),
+		ExpiresAt:        time.Now().Add(-time.Minute),
+	})
+	s.Require().NoError(err)
+	_, err = s.r.GetSession(s.ctx, expired.Id)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+	_, err = s.r.RefreshSession(s.ctx, expired.RefreshTokenHash,
+		auth.HashToken(s.prefix+"fourth"), newExpiresAt)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+
+	s.Require().NoError(s.r.DeleteSession(s.ctx, session.Id))
+	_, err = s.r.GetSession(s.ctx, session.Id)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+	s.ErrorIs(s.r.DeleteSession(s.ctx, session.Id), model.ErrSessionNotExists)
+
+	// sessions are deleted with the user
+	first, err := s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: auth.HashToken(s.prefix + "fifth"),
+		ExpiresAt:        expiresAt,
+	})
+	s.Require().NoError(err)
+	second, err := s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: auth.HashToken(s.prefix + "sixth"),
+		ExpiresAt:        expiresAt,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(s.r.DeleteUserSessions(s.ctx, user.Id))
+	_, err = s.r.GetSession(s.ctx, first.Id)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+
+	third, err := s.r.CreateSession(s.ctx, model.Session{
+		UserId:           user.Id,
+		RefreshTokenHash: auth.HashToken(s.prefix + "seventh"),
+		ExpiresAt:        expiresAt,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(s.r.DeleteUser(s.ctx, user.Id))
+	_, err = s.r.GetSession(s.ctx, third.Id)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+	_, err = s.r.GetSession(s.ctx, second.Id)
+	s.ErrorIs(err, model.ErrSessionNotExists)
+}
@@ -0,0 +1,188 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+// createCollection creates collection of the user with unique name and
+// schedules its deletion
+func (s *Suite) createCollection(ownerId uint64, name string, visibility model.Visibility,
+	sharedWith ...uint64) model.Collection {
+	collection, err := s.r.CreateCollection(s.ctx, model.Collection{
+		OwnerId:    ownerId,
+		Name:       s.prefix + name,
+		Visibility: visibility,
+		SharedWith: sharedWith,
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(collection.Id)
+	s.collectionsIdsToDelete = append(s.collectionsIdsToDelete, collection.Id)
+	return collection
+}
+
+func entriesIds(entries []model.CollectionEntry) []uint64 {
+	ids := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.MovieId)
+	}
+	return ids
+}
+
+func (s *Suite) TestCreateCollection() {
+	collection := s.createCollection(regularUserId, "Collection", model.Private, adminUserId)
+	s.Equal(uint64(regularUserId), collection.OwnerId)
+	s.Equal(s.prefix+"Collection", collection.Name)
+	s.Equal(model.Private, collection.Visibility)
+	s.Equal([]uint64{adminUserId}, collection.SharedWith)
+	s.Empty(collection.Entries)
+	s.False(collection.CreatedAt.IsZero())
+
+	got, err := s.r.GetCollection(s.ctx, collection.Id)
+	s.Require().NoError(err)
+	s.Equal(collection, got)
+
+	for name, c := range map[string]model.Collection{
+		"empty name":         {OwnerId: regularUserId, Name: "", Visibility: model.Private},
+		"long name":          {OwnerId: regularUserId, Name: strings.Repeat("a", 101), Visibility: model.Private},
+		"long description":   {OwnerId: regularUserId, Name: "a", Description: strings.Repeat("a", 1001), Visibility: model.Private},
+		"unknown visibility": {OwnerId: regularUserId, Name: "a", Visibility: "hidden"},
+		"unknown shared":     {OwnerId: regularUserId, Name: "a", Visibility: model.Private, SharedWith: []uint64{0}},
+	} {
+		_, err = s.r.CreateCollection(s.ctx, c)
+		s.ErrorIs(err, model.ErrValidationError, name)
+	}
+	_, err = s.r.CreateCollection(s.ctx, model.Collection{OwnerId: 0, Name: "a", Visibility: model.Private})
+	s.ErrorIs(err, model.ErrUserNotExists)
+
+	_, err = s.r.GetCollection(s.ctx, 0)
+	s.ErrorIs(err, model.ErrCollectionNotExists)
+}
+
+func (s *Suite) TestUpdateCollection() {
+	collection := s.createCollection(regularUserId, "Collection", model.Private, adminUserId)
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1))
+	s.Require().NoError(s.r.AddCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: m1.Id}))
+
+	updated, err := s.r.UpdateCollection(s.ctx, collection.Id, model.UpdateCollection{
+		Name:        s.prefix + "Updated",
+		Description: "description",
+		Visibility:  model.Public,
+	})
+	s.Require().NoError(err)
+	s.Equal(s.prefix+"Updated", updated.Name)
+	s.Equal("description", updated.Description)
+	s.Equal(model.Public, updated.Visibility)
+	// users are replaced, entries are kept
+	s.Empty(updated.SharedWith)
+	s.Equal([]uint64{m1.Id}, entriesIds(updated.Entries))
+	s.True(collection.CreatedAt.Equal(updated.CreatedAt))
+
+	_, err = s.r.UpdateCollection(s.ctx, collection.Id, model.UpdateCollection{Name: "", Visibility: model.Public})
+	s.ErrorIs(err, model.ErrValidationError)
+	_, err = s.r.UpdateCollection(s.ctx, 0, model.UpdateCollection{Name: "a", Visibility: model.Public})
+	s.ErrorIs(err, model.ErrCollectionNotExists)
+
+	s.Require().NoError(s.r.DeleteCollection(s.ctx, collection.Id))
+	s.ErrorIs(s.r.DeleteCollection(s.ctx, collection.Id), model.ErrCollectionNotExists)
+	_, err = s.r.GetCollection(s.ctx, collection.Id)
+	s.ErrorIs(err, model.ErrCollectionNotExists)
+	// movies are not deleted together with the collection
+	_, err = s.r.GetMovie(s.ctx, m1.Id)
+	s.NoError(err)
+}
+
+func (s *Suite) TestCollectionEntries() {
+	collection := s.createCollection(regularUserId, "Collection", model.Private)
+	actor := s.createActor("Actor", model.Male)
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1), actor.Id)
+	m2 := s.createMovie("Movie2", 5, date(2020, time.January, 1))
+	m3 := s.createMovie("Movie3", 5, date(2020, time.January, 1))
+
+	// entries are appended to the end of the collection
+	for _, id := range []uint64{m2.Id, m1.Id, m3.Id} {
+		s.Require().NoError(s.r.AddCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: id}))
+	}
+	s.ErrorIs(s.r.AddCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: m1.Id}), model.ErrConflict)
+	s.ErrorIs(s.r.AddCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: 0}), model.ErrMovieNotExists)
+	s.ErrorIs(s.r.AddCollectionEntry(s.ctx, 0, model.CollectionEntry{MovieId: m1.Id}), model.ErrCollectionNotExists)
+	s.ErrorIs(s.r.AddCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{
+		MovieId: m1.Id,
+		Note:    strings.Repeat("a", 501),
+	}), model.ErrValidationError)
+
+	s.Require().NoError(s.r.UpdateCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: m1.Id, Note: "note"}))
+	s.ErrorIs(s.r.UpdateCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: 0}), model.ErrEntryNotExists)
+
+	got, err := s.r.GetCollection(s.ctx, collection.Id)
+	s.Require().NoError(err)
+	s.Equal([]uint64{m2.Id, m1.Id, m3.Id}, entriesIds(got.Entries))
+	s.Equal("note", got.Entries[1].Note)
+	// movies of entries have relations
+	s.Equal(m1.Title, got.Entries[1].Movie.Title)
+	s.Equal([]uint64{actor.Id}, actorsIds(got.Entries[1].Movie.Actors))
+
+	s.Require().NoError(s.r.ReorderCollection(s.ctx, collection.Id, []uint64{m3.Id, m2.Id, m1.Id}))
+	got, err = s.r.GetCollection(s.ctx, collection.Id)
+	s.Require().NoError(err)
+	s.Equal([]uint64{m3.Id, m2.Id, m1.Id}, entriesIds(got.Entries))
+	s.Equal("note", got.Entries[2].Note)
+
+	for name, ids := range map[string][]uint64{
+		"missing":   {m3.Id, m2.Id},
+		"duplicate": {m3.Id, m2.Id, m2.Id},
+		"unknown":   {m3.Id, m2.Id, m1.Id, 0},
+	} {
+		s.ErrorIs(s.r.ReorderCollection(s.ctx, collection.Id, ids), model.ErrValidationError, name)
+	}
+	s.ErrorIs(s.r.ReorderCollection(s.ctx, 0, nil), model.ErrCollectionNotExists)
+
+	s.Require().NoError(s.r.RemoveCollectionEntry(s.ctx, collection.Id, m2.Id))
+	s.ErrorIs(s.r.RemoveCollectionEntry(s.ctx, collection.Id, m2.Id), model.ErrEntryNotExists)
+	// the appended entry goes after the remaining ones
+	s.Require().NoError(s.r.AddCollectionEntry(s.ctx, collection.Id, model.CollectionEntry{MovieId: m2.Id}))
+
+	// entries are deleted together with the movie
+	s.Require().NoError(s.r.DeleteMovie(s.ctx, m3.Id))
+	got, err = s.r.GetCollection(s.ctx, collection.Id)
+	s.Require().NoError(err)
+	s.Equal([]uint64{m1.Id, m2.Id}, entriesIds(got.Entries))
+}
+
+func (s *Suite) TestGetCollections() {
+	own := s.createCollection(regularUserId, "Own", model.Private)
+	public := s.createCollection(adminUserId, "Public", model.Public)
+	shared := s.createCollection(adminUserId, "Shared", model.Private, regularUserId)
+	private := s.createCollection(adminUserId, "Private", model.Private)
+	all := []uint64{own.Id, public.Id, shared.Id, private.Id}
+
+	collections := func(filter model.CollectionFilter) []uint64 {
+		ids := make([]uint64, 0)
+		page := model.Page{Limit: 1}
+		for {
+			list, err := s.r.GetCollections(s.ctx, filter, page)
+			s.Require().NoError(err)
+			s.Require().LessOrEqual(len(list.Collections), 1)
+			for _, c := range list.Collections {
+				// entries are not loaded for lists
+				s.Empty(c.Entries)
+				ids = append(ids, c.Id)
+			}
+			if list.NextCursor == "" {
+				return ids
+			}
+			page.Cursor = list.NextCursor
+		}
+	}
+
+	s.Equal(all, filterIds(collections(model.CollectionFilter{}), all...))
+	s.Equal([]uint64{own.Id, public.Id, shared.Id},
+		filterIds(collections(model.CollectionFilter{ReaderId: regularUserId}), all...))
+	// the filter does not depend on the role of the reader
+	s.Equal([]uint64{public.Id, shared.Id, private.Id},
+		filterIds(collections(model.CollectionFilter{ReaderId: adminUserId}), all...))
+	s.Equal([]uint64{public.Id, shared.Id},
+		filterIds(collections(model.CollectionFilter{OwnerId: adminUserId, ReaderId: regularUserId}), all...))
+	s.Equal([]uint64{own.Id}, filterIds(collections(model.CollectionFilter{OwnerId: regularUserId}), all...))
+}
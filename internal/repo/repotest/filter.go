@@ -0,0 +1,126 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (s *Suite) TestGetMoviesWithFilter() {
+	a1 := s.createActor("Actor1", model.Male)
+	a2 := s.createActor("Actor2", model.Female)
+	a3 := s.createActor("Actor3", model.Male)
+	m1 := s.createMovie("Movie1", 3, date(2019, time.January, 1), a1.Id)
+	m2 := s.createMovie("Movie2", 6, date(2020, time.June, 1), a1.Id, a2.Id)
+	m3 := s.createMovie("Movie3", 8, date(2021, time.January, 1), a3.Id)
+	m4 := s.createMovie("Movie4", 9, date(2022, time.January, 1))
+	all := []uint64{m1.Id, m2.Id, m3.Id, m4.Id}
+
+	drama := s.createGenre("Drama")
+	comedy := s.createGenre("Comedy")
+	s.setMovieGenres(m1, drama.Id)
+	s.setMovieGenres(m2, drama.Id, comedy.Id)
+	s.setMovieGenres(m4, comedy.Id)
+
+	ptr := func(v float64) *float64 { return &v }
+	datePtr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		description string
+		filter      model.MovieFilter
+		want        []uint64
+	}{
+		{
+			description: "empty filter",
+			filter:      model.MovieFilter{},
+			want:        all,
+		},
+		{
+			description: "release date range with inclusive bounds",
+			filter: model.MovieFilter{
+				ReleaseDateFrom: datePtr(date(2020, time.June, 1)),
+				ReleaseDateTo:   datePtr(date(2021, time.January, 1)),
+			},
+			want: []uint64{m2.Id, m3.Id},
+		},
+		{
+			description: "rating range with inclusive bounds",
+			filter:      model.MovieFilter{RatingFrom: ptr(6), RatingTo: ptr(8)},
+			want:        []uint64{m2.Id, m3.Id},
+		},
+		{
+			description: "rating lower bound only",
+			filter:      model.MovieFilter{RatingFrom: ptr(8)},
+			want:        []uint64{m3.Id, m4.Id},
+		},
+		{
+			description: "any of actors",
+			filter:      model.MovieFilter{ActorsId: []uint64{a2.Id, a3.Id}},
+			want:        []uint64{m2.Id, m3.Id},
+		},
+		{
+			description: "all actors",
+			filter: model.MovieFilter{
+				ActorsId:    []uint64{a1.Id, a2.Id, a1.Id},
+				ActorsMatch: model.MatchAll,
+			},
+			want: []uint64{m2.Id},
+		},
+		{
+			description: "actor gender",
+			filter:      model.MovieFilter{ActorGender: model.Female},
+			want:        []uint64{m2.Id},
+		},
+		{
+			description: "any of genres",
+			filter:      model.MovieFilter{GenresId: []uint64{drama.Id, comedy.Id}},
+			want:        []uint64{m1.Id, m2.Id, m4.Id},
+		},
+		{
+			description: "all genres",
+			filter: model.MovieFilter{
+				GenresId:    []uint64{drama.Id, comedy.Id},
+				GenresMatch: model.MatchAll,
+			},
+			want: []uint64{m2.Id},
+		},
+		{
+			description: "genres with actor gender",
+			filter:      model.MovieFilter{GenresId: []uint64{comedy.Id}, ActorGender: model.Female},
+			want:        []uint64{m2.Id},
+		},
+		{
+			description: "combination of filters",
+			filter: model.MovieFilter{
+				RatingFrom:  ptr(5),
+				ActorsId:    []uint64{a1.Id, a3.Id},
+				ActorGender: model.Male,
+			},
+			want: []uint64{m2.Id, m3.Id},
+		},
+	}
+
+	for _, test := range tests {
+		got := s.filterMovies(test.filter, "title")
+		s.Equal(test.want, filterIds(moviesIds(got), all...), test.description)
+	}
+}
+
+func (s *Suite) TestSearchMoviesWithFilterAndPagination() {
+	actor := s.createActor("Actor", model.Male)
+	s.createMovie("A", 3, date(2020, time.January, 1), actor.Id)
+	m2 := s.createMovie("B", 6, date(2020, time.January, 1), actor.Id)
+	m3 := s.createMovie("C", 8, date(2020, time.January, 1), actor.Id)
+	rating := 5.
+
+	filter := model.MovieFilter{RatingFrom: &rating}
+	first, err := s.r.SearchMovies(s.ctx, s.prefix+"Surname", filter, model.ParseSort("title"), model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Equal([]uint64{m2.Id}, moviesIds(first.Movies))
+	s.Equal(uint64(2), first.Total)
+
+	second, err := s.r.SearchMovies(s.ctx, s.prefix+"Surname", filter, model.ParseSort("title"),
+		model.Page{Limit: 1, Cursor: first.NextCursor})
+	s.Require().NoError(err)
+	s.Equal([]uint64{m3.Id}, moviesIds(second.Movies))
+	s.Empty(second.NextCursor)
+}
@@ -0,0 +1,142 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+func (s *Suite) TestCreateGenre() {
+	genre := s.createGenre("Drama")
+	s.Equal(s.prefix+"Drama", genre.Name)
+
+	got, err := s.r.GetGenre(s.ctx, genre.Id)
+	s.Require().NoError(err)
+	s.Equal(genre, got)
+
+	_, err = s.r.GetGenre(s.ctx, 0)
+	s.ErrorIs(err, model.ErrGenreNotExists)
+}
+
+func (s *Suite) TestCreateGenreWithInvalidName() {
+	for _, name := range []string{"", strings.Repeat("a", 51)} {
+		genre, err := s.r.CreateGenre(s.ctx, model.Genre{Name: name})
+		if err == nil {
+			s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+		}
+		s.ErrorIs(err, model.ErrValidationError)
+	}
+}
+
+func (s *Suite) TestGenreNameConflict() {
+	drama := s.createGenre("Drama")
+	comedy := s.createGenre("Comedy")
+
+	// names are unique ignoring case
+	genre, err := s.r.CreateGenre(s.ctx, model.Genre{Name: s.prefix + "DRAMA"})
+	if err == nil {
+		s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+	}
+	s.ErrorIs(err, model.ErrConflict)
+
+	_, err = s.r.UpdateGenre(s.ctx, comedy.Id, model.UpdateGenre{Name: drama.Name})
+	s.ErrorIs(err, model.ErrConflict)
+
+	// the genre can be renamed to the same name in another case
+	updated, err := s.r.UpdateGenre(s.ctx, drama.Id, model.UpdateGenre{Name: s.prefix + "drama"})
+	s.Require().NoError(err)
+	s.Equal(s.prefix+"drama", updated.Name)
+}
+
+func (s *Suite) TestUpdateGenre() {
+	genre := s.createGenre("Drama")
+	movie := s.setMovieGenres(s.createMovie("Movie", 5, date(2020, time.January, 1)), genre.Id)
+
+	updated, err := s.r.UpdateGenre(s.ctx, genre.Id, model.UpdateGenre{Name: s.prefix + "Comedy"})
+	s.Require().NoError(err)
+	s.Equal(model.Genre{Id: genre.Id, Name: s.prefix + "Comedy"}, updated)
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal([]model.Genre{updated}, got.Genres)
+
+	_, err = s.r.UpdateGenre(s.ctx, 0, model.UpdateGenre{Name: s.prefix + "Name"})
+	s.ErrorIs(err, model.ErrGenreNotExists)
+}
+
+func (s *Suite) TestDeleteGenre() {
+	drama := s.createGenre("Drama")
+	comedy := s.createGenre("Comedy")
+	movie := s.setMovieGenres(s.createMovie("Movie", 5, date(2020, time.January, 1)), drama.Id, comedy.Id)
+
+	s.Require().NoError(s.r.DeleteGenre(s.ctx, drama.Id))
+	s.ErrorIs(s.r.DeleteGenre(s.ctx, drama.Id), model.ErrGenreNotExists)
+
+	_, err := s.r.GetGenre(s.ctx, drama.Id)
+	s.ErrorIs(err, model.ErrGenreNotExists)
+
+	// deleted genre is removed from the movie
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal([]uint64{comedy.Id}, genresIds(got.Genres))
+}
+
+func (s *Suite) TestGetGenres() {
+	western := s.createGenre("Western")
+	drama := s.createGenre("Drama")
+	comedy := s.createGenre("Comedy")
+
+	genres, err := s.r.GetGenres(s.ctx)
+	s.Require().NoError(err)
+	s.Equal([]uint64{comedy.Id, drama.Id, western.Id},
+		filterIds(genresIds(genres), western.Id, drama.Id, comedy.Id))
+}
+
+func (s *Suite) TestMovieGenres() {
+	drama := s.createGenre("Drama")
+	comedy := s.createGenre("Comedy")
+
+	movie, err := s.r.CreateMovie(s.ctx, model.Movie{
+		Title:       s.prefix + "Movie",
+		ReleaseDate: date(2020, time.January, 1),
+		GenresId:    []uint64{drama.Id, comedy.Id, drama.Id},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	// genres are ordered by name
+	s.Equal([]model.Genre{comedy, drama}, movie.Genres)
+
+	movie = s.setMovieGenres(movie, drama.Id)
+	s.Equal([]model.Genre{drama}, movie.Genres)
+
+	list, err := s.r.GetMovies(s.ctx, model.MovieFilter{GenresId: []uint64{drama.Id}}, nil, model.Page{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(list.Movies, 1)
+	s.Equal([]model.Genre{drama}, list.Movies[0].Genres)
+
+	movie = s.setMovieGenres(movie)
+	s.NotNil(movie.Genres)
+	s.Empty(movie.Genres)
+
+	// links to non existing genres are not created
+	_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Genres:      []uint64{drama.Id, 0},
+	})
+	s.ErrorIs(err, model.ErrGenreNotExists)
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Empty(got.Genres)
+
+	movie, err = s.r.CreateMovie(s.ctx, model.Movie{
+		Title:       s.prefix + "Movie",
+		ReleaseDate: date(2020, time.January, 1),
+		GenresId:    []uint64{0},
+	})
+	if err == nil {
+		s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	}
+	s.ErrorIs(err, model.ErrGenreNotExists)
+}
@@ -0,0 +1,359 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (s *Suite) TestCreateMovie() {
+	actor1 := s.createActor("Actor1", model.Male)
+	actor2 := s.createActor("Actor2", model.Female)
+
+	movie := s.createMovie("Movie", 7.5, date(2020, time.January, 1), actor1.Id, actor2.Id)
+	s.Equal(s.prefix+"Movie", movie.Title)
+	s.Equal("description of Movie", movie.Description)
+	s.True(date(2020, time.January, 1).Equal(movie.ReleaseDate))
+	s.Equal(7.5, movie.Rating)
+	s.ElementsMatch([]uint64{actor1.Id, actor2.Id}, actorsIds(movie.Actors))
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal(movie.Title, got.Title)
+	s.ElementsMatch([]uint64{actor1.Id, actor2.Id}, actorsIds(got.Actors))
+}
+
+func (s *Suite) TestCreateMovieWithoutActors() {
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1))
+	s.NotNil(movie.Actors)
+	s.Empty(movie.Actors)
+}
+
+func (s *Suite) TestCreateMovieWithMissingActor() {
+	actor := s.createActor("Actor", model.Male)
+	before, err := s.r.GetMovies(s.ctx, model.MovieFilter{}, nil, model.Page{Limit: 1})
+	s.Require().NoError(err)
+
+	_, err = s.r.CreateMovie(s.ctx, model.Movie{
+		Title:    s.prefix + "Movie",
+		ActorsId: []uint64{actor.Id, 0},
+	})
+	s.ErrorIs(err, model.ErrActorNotExists)
+
+	after, err := s.r.GetMovies(s.ctx, model.MovieFilter{}, nil, model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Equal(before.Total, after.Total)
+
+	gotActor, err := s.r.GetActor(s.ctx, actor.Id)
+	s.Require().NoError(err)
+	s.Empty(gotActor.Movies)
+}
+
+func (s *Suite) TestCreateInvalidMovie() {
+	tests := []struct {
+		description string
+		movie       model.Movie
+	}{
+		{
+			description: "empty title",
+			movie:       model.Movie{Title: "", Rating: 5},
+		},
+		{
+			description: "rating is greater than 10",
+			movie:       model.Movie{Title: s.prefix + "Movie", Rating: 10.5},
+		},
+		{
+			description: "negative rating",
+			movie:       model.Movie{Title: s.prefix + "Movie", Rating: -1},
+		},
+	}
+
+	for _, test := range tests {
+		movie, err := s.r.CreateMovie(s.ctx, test.movie)
+		if err == nil {
+			s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+		}
+		s.ErrorIs(err, model.ErrValidationError, test.description)
+	}
+}
+
+func (s *Suite) TestUpdateMovie() {
+	actor1 := s.createActor("Actor1", model.Male)
+	actor2 := s.createActor("Actor2", model.Male)
+	actor3 := s.createActor("Actor3", model.Female)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor1.Id, actor2.Id)
+
+	updated, err := s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       s.prefix + "Updated",
+		Description: "new description",
+		ReleaseDate: date(2021, time.February, 2),
+		Rating:      8,
+		Actors:      []uint64{actor2.Id, actor3.Id},
+	})
+	s.Require().NoError(err)
+	s.Equal(movie.Id, updated.Id)
+	s.Equal(s.prefix+"Updated", updated.Title)
+	s.Equal("new description", updated.Description)
+	s.True(date(2021, time.February, 2).Equal(updated.ReleaseDate))
+	s.Equal(8., updated.Rating)
+	s.ElementsMatch([]uint64{actor2.Id, actor3.Id}, actorsIds(updated.Actors))
+
+	// links of the replaced actor are removed
+	gotActor, err := s.r.GetActor(s.ctx, actor1.Id)
+	s.Require().NoError(err)
+	s.Empty(gotActor.Movies)
+}
+
+func (s *Suite) TestUpdateMovieErrors() {
+	actor := s.createActor("Actor", model.Male)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor.Id)
+
+	_, err := s.r.UpdateMovie(s.ctx, 0, model.UpdateMovie{
+		Title:  s.prefix + "Updated",
+		Actors: []uint64{actor.Id},
+	})
+	s.ErrorIs(err, model.ErrMovieNotExists)
+
+	_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:  s.prefix + "Updated",
+		Actors: []uint64{0},
+	})
+	s.ErrorIs(err, model.ErrActorNotExists)
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal(movie.Title, got.Title)
+	s.Equal([]uint64{actor.Id}, actorsIds(got.Actors))
+}
+
+func (s *Suite) TestMovieCast() {
+	actor1 := s.createActor("Actor1", model.Male)
+	actor2 := s.createActor("Actor2", model.Female)
+	actor3 := s.createActor("Actor3", model.Male)
+
+	movie, err := s.r.CreateMovie(s.ctx, model.Movie{
+		Title:       s.prefix + "Movie",
+		ReleaseDate: date(2020, time.January, 1),
+		Cast: []model.CastCredit{
+			{ActorId: actor2.Id, Characters: []string{"Trinity"}, Billing: 2},
+			{ActorId: actor1.Id, Characters: []string{"Neo", "Thomas Anderson"}, Billing: 1},
+			{ActorId: actor1.Id, Characters: []string{"Duplicate"}},
+		},
+		// bare ids are billed after the cast by positions in the joined list
+		ActorsId: []uint64{actor3.Id},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	type credit struct {
+		id         uint64
+		characters []string
+		billing    int
+	}
+	credits := func(actors []model.Actor) []credit {
+		res := make([]credit, 0, len(actors))
+		for _, actor := range actors {
+			res = append(res, credit{actor.Id, actor.Characters, actor.Billing})
+		}
+		return res
+	}
+	// the cast is ordered by billing and only the first credit of the actor
+	// is kept
+	s.Equal([]credit{
+		{actor1.Id, []string{"Neo", "Thomas Anderson"}, 1},
+		{actor2.Id, []string{"Trinity"}, 2},
+		{actor3.Id, []string{}, 4},
+	}, credits(movie.Actors))
+
+	list, err := s.r.GetMovies(s.ctx, model.MovieFilter{ActorsId: []uint64{actor3.Id}}, nil, model.Page{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(list.Movies, 1)
+	s.Equal(credits(movie.Actors), credits(list.Movies[0].Actors))
+
+	// billing positions may be equal, then actors are ordered by id
+	updated, err := s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Cast: []model.CastCredit{
+			{ActorId: actor3.Id, Billing: 1},
+			{ActorId: actor2.Id, Characters: []string{"Trinity"}, Billing: 1},
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal([]credit{
+		{actor2.Id, []string{"Trinity"}, 1},
+		{actor3.Id, []string{}, 1},
+	}, credits(updated.Actors))
+
+	for _, cast := range [][]model.CastCredit{
+		{{ActorId: actor1.Id, Billing: -1}},
+		{{ActorId: actor1.Id, Characters: []string{""}}},
+	} {
+		_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Cast:        cast,
+		})
+		s.ErrorIs(err, model.ErrValidationError)
+	}
+
+	_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Cast:        []model.CastCredit{{ActorId: 0, Characters: []string{"Nobody"}}},
+	})
+	s.ErrorIs(err, model.ErrActorNotExists)
+}
+
+func (s *Suite) TestDeleteMovie() {
+	actor := s.createActor("Actor", model.Male)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor.Id)
+
+	s.Require().NoError(s.r.DeleteMovie(s.ctx, movie.Id))
+	s.ErrorIs(s.r.DeleteMovie(s.ctx, movie.Id), model.ErrMovieNotExists)
+
+	_, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.ErrorIs(err, model.ErrMovieNotExists)
+
+	gotActor, err := s.r.GetActor(s.ctx, actor.Id)
+	s.Require().NoError(err)
+	s.Empty(gotActor.Movies)
+}
+
+func (s *Suite) TestGetMovieNotExists() {
+	_, err := s.r.GetMovie(s.ctx, 0)
+	s.ErrorIs(err, model.ErrMovieNotExists)
+}
+
+func (s *Suite) TestGetMoviesSortOrders() {
+	m1 := s.createMovie("B", 9.91, date(2021, time.January, 1))
+	m2 := s.createMovie("C", 9.92, date(2019, time.January, 1))
+	m3 := s.createMovie("A", 9.90, date(2020, time.January, 1))
+
+	tests := []struct {
+		sortBy string
+		want   []uint64
+	}{
+		{sortBy: "", want: []uint64{m2.Id, m1.Id, m3.Id}},
+		{sortBy: "title", want: []uint64{m3.Id, m1.Id, m2.Id}},
+		{sortBy: "rating", want: []uint64{m3.Id, m1.Id, m2.Id}},
+		{sortBy: "release_date", want: []uint64{m2.Id, m3.Id, m1.Id}},
+		{sortBy: "-title", want: []uint64{m2.Id, m1.Id, m3.Id}},
+		{sortBy: "-rating", want: []uint64{m2.Id, m1.Id, m3.Id}},
+		{sortBy: "-release_date", want: []uint64{m1.Id, m3.Id, m2.Id}},
+	}
+
+	for _, test := range tests {
+		got := s.allMovies(test.sortBy)
+		s.Equal(test.want, filterIds(moviesIds(got), m1.Id, m2.Id, m3.Id), "sort by %q", test.sortBy)
+	}
+}
+
+func (s *Suite) TestGetMoviesReturnsActors() {
+	actor := s.createActor("Actor", model.Male)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), actor.Id)
+
+	for _, m := range s.allMovies("") {
+		if m.Id == movie.Id {
+			s.Equal([]uint64{actor.Id}, actorsIds(m.Actors))
+			return
+		}
+	}
+	s.Fail("created movie is not in the list")
+}
+
+func (s *Suite) TestSearchMovies() {
+	actor1 := s.createActor("Actor", model.Male)
+	actor2 := s.createActor("Keanu", model.Female)
+	m1 := s.createMovieWithDescription("Running Man", "A man runs from the police",
+		5, date(2020, time.January, 1), actor1.Id)
+	m2 := s.createMovieWithDescription("Фильмы о войне", "Исторический фильм",
+		5, date(2020, time.January, 1), actor1.Id, actor2.Id)
+	m3 := s.createMovieWithDescription("Silent Night", "Christmas story about a running dog",
+		5, date(2020, time.January, 1))
+
+	// every query contains the prefix to match only movies of the test
+	tests := []struct {
+		description string
+		query       string
+		want        []uint64
+	}{
+		{
+			description: "search by title and description is case insensitive and stemmed",
+			query:       s.prefix + "RUN",
+			want:        []uint64{m1.Id, m3.Id},
+		},
+		{
+			description: "russian words are stemmed",
+			query:       s.prefix + "фильм",
+			want:        []uint64{m2.Id},
+		},
+		{
+			description: "search by description",
+			query:       s.prefix + "police",
+			want:        []uint64{m1.Id},
+		},
+		{
+			description: "search by actor name",
+			query:       s.prefix + "keanu",
+			want:        []uint64{m2.Id},
+		},
+		{
+			description: "movies without actors are found",
+			query:       s.prefix + "silent",
+			want:        []uint64{m3.Id},
+		},
+		{
+			description: "alternatives",
+			query:       s.prefix + "police or " + s.prefix + "christmas",
+			want:        []uint64{m1.Id, m3.Id},
+		},
+		{
+			description: "excluded word",
+			query:       s.prefix + "run -police",
+			want:        []uint64{m3.Id},
+		},
+		{
+			description: "all words should match",
+			query:       s.prefix + "run missing",
+			want:        []uint64{},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := s.r.SearchMovies(s.ctx, test.query, model.MovieFilter{}, nil, model.Page{Limit: model.MaxPageLimit})
+		s.Require().NoError(err)
+		s.ElementsMatch(test.want, filterIds(moviesIds(got.Movies), m1.Id, m2.Id, m3.Id), test.description)
+	}
+}
+
+func (s *Suite) TestSearchMoviesRelevanceAndSnippet() {
+	m1 := s.createMovieWithDescription("Dog", "A story about a cat",
+		5, date(2020, time.January, 1))
+	m2 := s.createMovieWithDescription("Cat", "A story about a dog",
+		5, date(2020, time.January, 1))
+
+	// a match in the title is more relevant than one in the description
+	got, err := s.r.SearchMovies(s.ctx, s.prefix+"cats", model.MovieFilter{}, nil, model.Page{Limit: model.MaxPageLimit})
+	s.Require().NoError(err)
+	s.Require().Equal([]uint64{m2.Id, m1.Id}, moviesIds(got.Movies))
+	s.Greater(got.Movies[0].Relevance, got.Movies[1].Relevance)
+	s.Equal("A story about a <b>cat</b>", got.Movies[1].Snippet)
+
+	got, err = s.r.SearchMovies(s.ctx, s.prefix+"cat", model.MovieFilter{}, model.ParseSort("-relevance"),
+		model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Equal([]uint64{m2.Id}, moviesIds(got.Movies))
+	got, err = s.r.SearchMovies(s.ctx, s.prefix+"cat", model.MovieFilter{}, model.ParseSort("-relevance"),
+		model.Page{Limit: 1, Cursor: got.NextCursor})
+	s.Require().NoError(err)
+	s.Equal([]uint64{m1.Id}, moviesIds(got.Movies))
+	s.Empty(got.NextCursor)
+
+	// movies are not ranked outside of the search
+	for _, movie := range s.allMovies("") {
+		if movie.Id == m1.Id {
+			s.Zero(movie.Relevance)
+			s.Empty(movie.Snippet)
+		}
+	}
+}
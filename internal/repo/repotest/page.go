@@ -0,0 +1,112 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (s *Suite) TestMoviesPagination() {
+	actor := s.createActor("Actor", model.Male)
+	movies := []model.Movie{
+		s.createMovie("B", 5, date(2020, time.January, 1), actor.Id),
+		s.createMovie("A", 7, date(2020, time.January, 1), actor.Id),
+		s.createMovie("B", 5, date(2019, time.January, 1), actor.Id),
+		s.createMovie("A", 5, date(2021, time.January, 1), actor.Id),
+		s.createMovie("C", 7, date(2019, time.January, 1), actor.Id),
+	}
+	query := s.prefix + "Surname"
+
+	for _, sortBy := range []string{"", "title", "rating", "-release_date", "title,-rating", "-rating,release_date", "-relevance,title"} {
+		all, err := s.r.SearchMovies(s.ctx, query, model.MovieFilter{}, model.ParseSort(sortBy), model.Page{Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Len(all.Movies, len(movies))
+		s.Equal(uint64(len(movies)), all.Total)
+		s.Empty(all.NextCursor)
+
+		// pages of two movies contain the same movies in the same order,
+		// ties are broken by id, so no movie is skipped or repeated
+		got := make([]uint64, 0, len(movies))
+		page := model.Page{Limit: 2}
+		for i := 0; ; i++ {
+			s.Require().Less(i, len(movies), "sort by %q: too many pages", sortBy)
+			list, err := s.r.SearchMovies(s.ctx, query, model.MovieFilter{}, model.ParseSort(sortBy), page)
+			s.Require().NoError(err)
+			s.Equal(uint64(len(movies)), list.Total)
+			s.LessOrEqual(len(list.Movies), 2)
+			got = append(got, moviesIds(list.Movies)...)
+			if list.NextCursor == "" {
+				break
+			}
+			page.Cursor = list.NextCursor
+		}
+		s.Equal(moviesIds(all.Movies), got, "sort by %q", sortBy)
+	}
+}
+
+func (s *Suite) TestMoviesPaginationIsStableAfterDelete() {
+	actor := s.createActor("Actor", model.Male)
+	m1 := s.createMovie("A", 5, date(2020, time.January, 1), actor.Id)
+	m2 := s.createMovie("B", 5, date(2020, time.January, 1), actor.Id)
+	m3 := s.createMovie("C", 5, date(2020, time.January, 1), actor.Id)
+	query := s.prefix + "Surname"
+
+	first, err := s.r.SearchMovies(s.ctx, query, model.MovieFilter{}, model.ParseSort("title"), model.Page{Limit: 2})
+	s.Require().NoError(err)
+	s.Equal([]uint64{m1.Id, m2.Id}, moviesIds(first.Movies))
+
+	// deleting a movie of the previous page does not shift the next page
+	s.Require().NoError(s.r.DeleteMovie(s.ctx, m1.Id))
+	second, err := s.r.SearchMovies(s.ctx, query, model.MovieFilter{}, model.ParseSort("title"),
+		model.Page{Limit: 2, Cursor: first.NextCursor})
+	s.Require().NoError(err)
+	s.Equal([]uint64{m3.Id}, moviesIds(second.Movies))
+	s.Equal(uint64(2), second.Total)
+	s.Empty(second.NextCursor)
+}
+
+func (s *Suite) TestInvalidCursor() {
+	actor := s.createActor("Actor", model.Male)
+	s.createMovie("A", 5, date(2020, time.January, 1), actor.Id)
+	s.createMovie("B", 5, date(2020, time.January, 1), actor.Id)
+	s.createActor("Actor2", model.Male)
+
+	_, err := s.r.GetMovies(s.ctx, model.MovieFilter{}, nil, model.Page{Limit: 1, Cursor: "garbage"})
+	s.ErrorIs(err, model.ErrInvalidCursor)
+	_, err = s.r.GetActors(s.ctx, model.ActorFilter{}, nil, model.Page{Limit: 1, Cursor: "garbage"})
+	s.ErrorIs(err, model.ErrInvalidCursor)
+
+	// cursor of one sort order cannot be used with another one
+	list, err := s.r.GetMovies(s.ctx, model.MovieFilter{}, model.ParseSort("title"), model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(list.NextCursor)
+	_, err = s.r.GetMovies(s.ctx, model.MovieFilter{}, model.ParseSort("-title"), model.Page{Limit: 1, Cursor: list.NextCursor})
+	s.ErrorIs(err, model.ErrInvalidCursor)
+
+	actors, err := s.r.GetActors(s.ctx, model.ActorFilter{}, nil, model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(actors.NextCursor)
+	_, err = s.r.GetMovies(s.ctx, model.MovieFilter{}, nil, model.Page{Limit: 1, Cursor: actors.NextCursor})
+	s.ErrorIs(err, model.ErrInvalidCursor)
+}
+
+func (s *Suite) TestActorsPagination() {
+	created := []uint64{
+		s.createActor("Actor1", model.Male).Id,
+		s.createActor("Actor2", model.Female).Id,
+		s.createActor("Actor3", model.Male).Id,
+	}
+
+	first, err := s.r.GetActors(s.ctx, model.ActorFilter{}, nil, model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Len(first.Actors, 1)
+	s.GreaterOrEqual(first.Total, uint64(len(created)))
+
+	ids := actorsIds(s.allActors(""))
+	seen := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		_, ok := seen[id]
+		s.False(ok, "actor %d is repeated", id)
+		seen[id] = struct{}{}
+	}
+	s.Equal(created, filterIds(ids, created...))
+}
@@ -0,0 +1,169 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (s *Suite) TestMovieCrew() {
+	director := s.createActor("Director", model.Male)
+	writer := s.createActor("Writer", model.Female)
+	actor := s.createActor("Actor", model.Male)
+
+	movie, err := s.r.CreateMovie(s.ctx, model.Movie{
+		Title:       s.prefix + "Movie",
+		ReleaseDate: date(2020, time.January, 1),
+		ActorsId:    []uint64{actor.Id},
+		CrewCredits: []model.CrewCredit{
+			{PersonId: writer.Id, Role: model.WriterRole},
+			{PersonId: director.Id, Role: model.DirectorRole},
+			{PersonId: director.Id, Role: model.WriterRole},
+			{PersonId: director.Id, Role: model.DirectorRole},
+		},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	// the crew is ordered by roles and ids, the cast is not changed
+	s.Equal([]model.CrewMember{
+		{Person: director, Role: model.DirectorRole},
+		{Person: director, Role: model.WriterRole},
+		{Person: writer, Role: model.WriterRole},
+	}, movie.Crew)
+	s.Equal([]uint64{actor.Id}, actorsIds(movie.Actors))
+
+	updated, err := s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Actors:      []uint64{actor.Id},
+		Crew:        []model.CrewCredit{{PersonId: actor.Id, Role: model.ProducerRole}},
+	})
+	s.Require().NoError(err)
+	s.Equal([]model.CrewMember{{Person: actor, Role: model.ProducerRole}}, updated.Crew)
+
+	list, err := s.r.GetMovies(s.ctx, model.MovieFilter{ActorsId: []uint64{actor.Id}}, nil, model.Page{Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(list.Movies, 1)
+	s.Equal(updated.Crew, list.Movies[0].Crew)
+
+	_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Crew:        []model.CrewCredit{{PersonId: actor.Id, Role: model.ActorRole}},
+	})
+	s.ErrorIs(err, model.ErrValidationError)
+
+	_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Crew:        []model.CrewCredit{{PersonId: 0, Role: model.DirectorRole}},
+	})
+	s.ErrorIs(err, model.ErrPersonNotExists)
+
+	// deleted person is removed from the crew
+	s.Require().NoError(s.r.DeleteActor(s.ctx, actor.Id))
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.NotNil(got.Crew)
+	s.Empty(got.Crew)
+}
+
+func (s *Suite) TestCrewOnlyPerson() {
+	actor := s.createActor("Actor", model.Male)
+	person, err := s.r.CreateActor(s.ctx, model.Actor{
+		FirstName:  s.prefix + "Director",
+		SecondName: s.prefix + "Surname",
+		Gender:     model.Female,
+		CrewOnly:   true,
+	})
+	s.Require().NoError(err)
+	s.actorsIdsToDelete = append(s.actorsIdsToDelete, person.Id)
+	movie, err := s.r.CreateMovie(s.ctx, model.Movie{
+		Title:       s.prefix + "Movie",
+		ReleaseDate: date(2020, time.January, 1),
+		CrewCredits: []model.CrewCredit{{PersonId: person.Id, Role: model.DirectorRole}},
+	})
+	s.Require().NoError(err)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+
+	// crew only persons are not listed, searched and suggested as actors
+	got, err := s.r.GetActor(s.ctx, person.Id)
+	s.Require().NoError(err)
+	s.True(got.CrewOnly)
+	s.Equal([]model.CrewMember{{Person: person, Role: model.DirectorRole}}, movie.Crew)
+	list, err := s.r.GetActors(s.ctx, model.ActorFilter{}, nil, model.Page{Limit: model.MaxPageLimit})
+	s.Require().NoError(err)
+	s.Equal([]uint64{actor.Id}, filterIds(actorsIds(list.Actors), actor.Id, person.Id))
+	list, err = s.r.SearchActors(s.ctx, s.prefix+"Surname", model.ActorFilter{}, nil, model.Page{Limit: model.MaxPageLimit})
+	s.Require().NoError(err)
+	s.Equal([]uint64{actor.Id}, actorsIds(list.Actors))
+	fuzzy, err := s.r.FuzzySearch(s.ctx, s.prefix+"Director "+s.prefix+"Surname", model.MaxFuzzySearchLimit)
+	s.Require().NoError(err)
+	s.Empty(filterIds(actorsIds(fuzzy.Actors), person.Id))
+	suggestions, err := s.r.Suggest(s.ctx, s.prefix+"Dir", model.MaxSuggestLimit)
+	s.Require().NoError(err)
+	s.Empty(suggestions)
+
+	// casting the person in a movie makes them an actor
+	_, err = s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		ReleaseDate: movie.ReleaseDate,
+		Actors:      []uint64{person.Id},
+		Crew:        []model.CrewCredit{{PersonId: person.Id, Role: model.DirectorRole}},
+	})
+	s.Require().NoError(err)
+	got, err = s.r.GetActor(s.ctx, person.Id)
+	s.Require().NoError(err)
+	s.False(got.CrewOnly)
+	list, err = s.r.SearchActors(s.ctx, s.prefix+"Surname", model.ActorFilter{}, nil, model.Page{Limit: model.MaxPageLimit})
+	s.Require().NoError(err)
+	s.ElementsMatch([]uint64{actor.Id, person.Id}, actorsIds(list.Actors))
+	suggestions, err = s.r.Suggest(s.ctx, s.prefix+"Dir", model.MaxSuggestLimit)
+	s.Require().NoError(err)
+	s.Equal([]uint64{person.Id}, suggestionsIds(suggestions))
+}
+
+func (s *Suite) TestGetFilmography() {
+	person := s.createActor("Person", model.Female)
+	m1 := s.createMovie("Movie1", 5, date(2021, time.January, 1), person.Id)
+	m2 := s.createMovie("Movie2", 5, date(2019, time.January, 1))
+	m3 := s.createMovie("Movie3", 5, date(2020, time.January, 1), person.Id)
+	for _, movie := range []model.Movie{m1, m2, m3} {
+		crew := []model.CrewCredit{{PersonId: person.Id, Role: model.DirectorRole}}
+		if movie.Id == m1.Id {
+			crew = append(crew, model.CrewCredit{PersonId: person.Id, Role: model.WriterRole})
+		}
+		_, err := s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+			Title:       movie.Title,
+			ReleaseDate: movie.ReleaseDate,
+			Actors:      actorsIds(movie.Actors),
+			Crew:        crew,
+		})
+		s.Require().NoError(err)
+	}
+
+	filmography, err := s.r.GetFilmography(s.ctx, person.Id)
+	s.Require().NoError(err)
+	s.Equal(person.Id, filmography.Person.Id)
+	s.Equal(person.FirstName, filmography.Person.FirstName)
+
+	credits := make(map[model.CreditRole][]uint64)
+	for role, movies := range filmography.Credits {
+		credits[role] = moviesIds(movies)
+	}
+	// movies of every role are ordered by release date
+	s.Equal(map[model.CreditRole][]uint64{
+		model.ActorRole:    {m3.Id, m1.Id},
+		model.DirectorRole: {m2.Id, m3.Id, m1.Id},
+		model.WriterRole:   {m1.Id},
+	}, credits)
+	s.Equal(m1.Title, filmography.Credits[model.WriterRole][0].Title)
+
+	empty := s.createActor("Empty", model.Male)
+	filmography, err = s.r.GetFilmography(s.ctx, empty.Id)
+	s.Require().NoError(err)
+	s.Empty(filmography.Credits)
+
+	_, err = s.r.GetFilmography(s.ctx, 0)
+	s.ErrorIs(err, model.ErrPersonNotExists)
+}
@@ -0,0 +1,217 @@
+// Package repotest contains conformance test suite which every
+// implementation of repo.Repo should pass
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/suite"
+	"movie-lib/internal/model"
+	"movie-lib/internal/repo"
+	"time"
+)
+
+const (
+	adminUserId   = 1
+	regularUserId = 2
+)
+
+// Suite is a conformance test suite for repo.Repo. Tests do not expect the
+// repository to be empty and remove all created data after themselves, so
+// the suite can be run against a shared database.
+//
+// Usage:
+//
+//	suite.Run(t, &repotest.Suite{NewRepo: func() repo.Repo { ... }})
+type Suite struct {
+	suite.Suite
+
+	// NewRepo is called before every test and returns tested repository
+	NewRepo func() repo.Repo
+
+	ctx    context.Context
+	r      repo.Repo
+	prefix string
+
+	moviesIdsToDelete []uint64
+	actorsIdsToDelete []uint64
+	genresIdsToDelete []uint64
+
+	collectionsIdsToDelete []uint64
+	usersIdsToDelete       []uint64
+	rolesToDelete          []model.Role
+}
+
+func (s *Suite) SetupTest() {
+	s.ctx = context.Background()
+	s.r = s.NewRepo()
+	// the prefix is a separate word, so the full-text search can find all
+	// movies and actors of the test by it
+	s.prefix = fmt.Sprintf("repotest%d ", time.Now().UnixNano())
+	s.moviesIdsToDelete = nil
+	s.actorsIdsToDelete = nil
+	s.genresIdsToDelete = nil
+	s.collectionsIdsToDelete = nil
+	s.usersIdsToDelete = nil
+	s.rolesToDelete = nil
+}
+
+func (s *Suite) TearDownTest() {
+	for _, id := range s.moviesIdsToDelete {
+		_ = s.r.DeleteMovie(s.ctx, id)
+	}
+	for _, id := range s.actorsIdsToDelete {
+		_ = s.r.DeleteActor(s.ctx, id)
+	}
+	for _, id := range s.genresIdsToDelete {
+		_ = s.r.DeleteGenre(s.ctx, id)
+	}
+	for _, id := range s.collectionsIdsToDelete {
+		_ = s.r.DeleteCollection(s.ctx, id)
+	}
+	for _, id := range s.usersIdsToDelete {
+		_ = s.r.DeleteUser(s.ctx, id)
+	}
+	for _, name := range s.rolesToDelete {
+		_ = s.r.DeleteRole(s.ctx, name)
+	}
+}
+
+// date returns midnight UTC of the given day, that is how dates are stored
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// createActor creates actor with unique first name and schedules its deletion
+func (s *Suite) createActor(firstName string, gender model.Gender) model.Actor {
+	actor, err := s.r.CreateActor(s.ctx, model.Actor{
+		FirstName:  s.prefix + firstName,
+		SecondName: s.prefix + "Surname",
+		Gender:     gender,
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(actor.Id)
+	s.actorsIdsToDelete = append(s.actorsIdsToDelete, actor.Id)
+	return actor
+}
+
+// createGenre creates genre with unique name and schedules its deletion
+func (s *Suite) createGenre(name string) model.Genre {
+	genre, err := s.r.CreateGenre(s.ctx, model.Genre{Name: s.prefix + name})
+	s.Require().NoError(err)
+	s.Require().NotZero(genre.Id)
+	s.genresIdsToDelete = append(s.genresIdsToDelete, genre.Id)
+	return genre
+}
+
+// setMovieGenres replaces genres of the movie keeping its other fields
+func (s *Suite) setMovieGenres(movie model.Movie, genresId ...uint64) model.Movie {
+	updated, err := s.r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+		Title:       movie.Title,
+		Description: movie.Description,
+		ReleaseDate: movie.ReleaseDate,
+		Rating:      movie.Rating,
+		Actors:      actorsIds(movie.Actors),
+		Genres:      genresId,
+	})
+	s.Require().NoError(err)
+	return updated
+}
+
+// createMovie creates movie with unique title and schedules its deletion
+func (s *Suite) createMovie(title string, rating float64, releaseDate time.Time, actorsId ...uint64) model.Movie {
+	return s.createMovieWithDescription(title, "description of "+title, rating, releaseDate, actorsId...)
+}
+
+// createMovieWithDescription creates movie with unique title and the given
+// description and schedules its deletion
+func (s *Suite) createMovieWithDescription(title, description string, rating float64,
+	releaseDate time.Time, actorsId ...uint64) model.Movie {
+	movie, err := s.r.CreateMovie(s.ctx, model.Movie{
+		Title:       s.prefix + title,
+		Description: description,
+		ReleaseDate: releaseDate,
+		Rating:      rating,
+		ActorsId:    actorsId,
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(movie.Id)
+	s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+	return movie
+}
+
+// allMovies returns movies of all pages of GetMovies, sortBy is parsed by
+// model.ParseSort
+func (s *Suite) allMovies(sortBy string) []model.Movie {
+	return s.filterMovies(model.MovieFilter{}, sortBy)
+}
+
+// filterMovies returns movies of all pages of GetMovies with the filter
+func (s *Suite) filterMovies(filter model.MovieFilter, sortBy string) []model.Movie {
+	movies := make([]model.Movie, 0)
+	page := model.Page{Limit: 100}
+	for {
+		list, err := s.r.GetMovies(s.ctx, filter, model.ParseSort(sortBy), page)
+		s.Require().NoError(err)
+		movies = append(movies, list.Movies...)
+		if list.NextCursor == "" {
+			return movies
+		}
+		page.Cursor = list.NextCursor
+	}
+}
+
+// allActors returns actors of all pages of GetActors, sortBy is parsed by
+// model.ParseSort
+func (s *Suite) allActors(sortBy string) []model.Actor {
+	actors := make([]model.Actor, 0)
+	page := model.Page{Limit: 100}
+	for {
+		list, err := s.r.GetActors(s.ctx, model.ActorFilter{}, model.ParseSort(sortBy), page)
+		s.Require().NoError(err)
+		actors = append(actors, list.Actors...)
+		if list.NextCursor == "" {
+			return actors
+		}
+		page.Cursor = list.NextCursor
+	}
+}
+
+func actorsIds(actors []model.Actor) []uint64 {
+	ids := make([]uint64, 0, len(actors))
+	for _, actor := range actors {
+		ids = append(ids, actor.Id)
+	}
+	return ids
+}
+
+func genresIds(genres []model.Genre) []uint64 {
+	ids := make([]uint64, 0, len(genres))
+	for _, genre := range genres {
+		ids = append(ids, genre.Id)
+	}
+	return ids
+}
+
+func moviesIds(movies []model.Movie) []uint64 {
+	ids := make([]uint64, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+	return ids
+}
+
+// filterIds returns ids from got which are in want keeping the order of got
+func filterIds(got []uint64, want ...uint64) []uint64 {
+	set := make(map[uint64]struct{}, len(want))
+	for _, id := range want {
+		set[id] = struct{}{}
+	}
+	res := make([]uint64, 0, len(want))
+	for _, id := range got {
+		if _, ok := set[id]; ok {
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -0,0 +1,151 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+// createReview creates review of the user, the review is deleted together
+// with the movie
+func (s *Suite) createReview(movieId, userId uint64, rating int) model.Review {
+	review, err := s.r.CreateReview(s.ctx, model.Review{
+		MovieId: movieId,
+		UserId:  userId,
+		Rating:  rating,
+		Text:    "review",
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(review.Id)
+	return review
+}
+
+func (s *Suite) TestCreateReview() {
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1))
+
+	review := s.createReview(movie.Id, regularUserId, 7)
+	s.Equal(movie.Id, review.MovieId)
+	s.Equal(uint64(regularUserId), review.UserId)
+	s.Equal(7, review.Rating)
+	s.Equal("review", review.Text)
+	s.WithinDuration(time.Now(), review.CreatedAt, time.Minute)
+	s.True(review.CreatedAt.Equal(review.UpdatedAt))
+
+	got, err := s.r.GetReview(s.ctx, review.Id)
+	s.Require().NoError(err)
+	s.Equal(review, got)
+
+	// the user can review the movie only once
+	_, err = s.r.CreateReview(s.ctx, model.Review{MovieId: movie.Id, UserId: regularUserId, Rating: 8})
+	s.ErrorIs(err, model.ErrConflict)
+
+	_, err = s.r.CreateReview(s.ctx, model.Review{MovieId: 0, UserId: adminUserId, Rating: 8})
+	s.ErrorIs(err, model.ErrMovieNotExists)
+	_, err = s.r.CreateReview(s.ctx, model.Review{MovieId: movie.Id, UserId: 0, Rating: 8})
+	s.ErrorIs(err, model.ErrUserNotExists)
+
+	for _, invalid := range []model.Review{
+		{MovieId: movie.Id, UserId: adminUserId, Rating: 0},
+		{MovieId: movie.Id, UserId: adminUserId, Rating: 11},
+		{MovieId: movie.Id, UserId: adminUserId, Rating: 5, Text: strings.Repeat("a", 5001)},
+	} {
+		_, err = s.r.CreateReview(s.ctx, invalid)
+		s.ErrorIs(err, model.ErrValidationError)
+	}
+}
+
+func (s *Suite) TestUpdateReview() {
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1))
+	review := s.createReview(movie.Id, regularUserId, 7)
+
+	updated, err := s.r.UpdateReview(s.ctx, review.Id, model.UpdateReview{Rating: 9, Text: "better"})
+	s.Require().NoError(err)
+	s.Equal(review.Id, updated.Id)
+	s.Equal(movie.Id, updated.MovieId)
+	s.Equal(uint64(regularUserId), updated.UserId)
+	s.Equal(9, updated.Rating)
+	s.Equal("better", updated.Text)
+	s.True(review.CreatedAt.Equal(updated.CreatedAt))
+	s.False(updated.UpdatedAt.Before(review.UpdatedAt))
+
+	_, err = s.r.UpdateReview(s.ctx, review.Id, model.UpdateReview{Rating: 11})
+	s.ErrorIs(err, model.ErrValidationError)
+	_, err = s.r.UpdateReview(s.ctx, 0, model.UpdateReview{Rating: 5})
+	s.ErrorIs(err, model.ErrReviewNotExists)
+}
+
+func (s *Suite) TestDeleteReview() {
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1))
+	review := s.createReview(movie.Id, regularUserId, 7)
+
+	s.Require().NoError(s.r.DeleteReview(s.ctx, review.Id))
+	s.ErrorIs(s.r.DeleteReview(s.ctx, review.Id), model.ErrReviewNotExists)
+	_, err := s.r.GetReview(s.ctx, review.Id)
+	s.ErrorIs(err, model.ErrReviewNotExists)
+
+	// reviews are deleted together with the movie
+	review = s.createReview(movie.Id, regularUserId, 7)
+	s.Require().NoError(s.r.DeleteMovie(s.ctx, movie.Id))
+	_, err = s.r.GetReview(s.ctx, review.Id)
+	s.ErrorIs(err, model.ErrReviewNotExists)
+}
+
+func (s *Suite) TestGetReviews() {
+	movie1 := s.createMovie("Movie1", 5, date(2020, time.January, 1))
+	movie2 := s.createMovie("Movie2", 5, date(2020, time.January, 1))
+	r1 := s.createReview(movie1.Id, regularUserId, 7)
+	r2 := s.createReview(movie1.Id, adminUserId, 8)
+	r3 := s.createReview(movie2.Id, regularUserId, 4)
+
+	reviewsIds := func(filter model.ReviewFilter) []uint64 {
+		ids := make([]uint64, 0)
+		page := model.Page{Limit: 1}
+		for {
+			list, err := s.r.GetReviews(s.ctx, filter, page)
+			s.Require().NoError(err)
+			for _, review := range list.Reviews {
+				ids = append(ids, review.Id)
+			}
+			if list.NextCursor == "" {
+				return ids
+			}
+			page.Cursor = list.NextCursor
+		}
+	}
+
+	// the newest reviews go first
+	s.Equal([]uint64{r2.Id, r1.Id}, reviewsIds(model.ReviewFilter{MovieId: movie1.Id}))
+	s.Equal([]uint64{r3.Id}, reviewsIds(model.ReviewFilter{MovieId: movie2.Id}))
+	s.Equal([]uint64{r3.Id, r1.Id}, filterIds(reviewsIds(model.ReviewFilter{UserId: regularUserId}), r1.Id, r2.Id, r3.Id))
+	s.Equal([]uint64{r1.Id}, reviewsIds(model.ReviewFilter{MovieId: movie1.Id, UserId: regularUserId}))
+
+	list, err := s.r.GetReviews(s.ctx, model.ReviewFilter{MovieId: movie1.Id}, model.Page{Limit: 1})
+	s.Require().NoError(err)
+	s.Equal(uint64(2), list.Total)
+
+	_, err = s.r.GetReviews(s.ctx, model.ReviewFilter{}, model.Page{Limit: 1, Cursor: "invalid"})
+	s.ErrorIs(err, model.ErrInvalidCursor)
+}
+
+func (s *Suite) TestMovieCommunityRating() {
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1))
+	s.Zero(movie.CommunityRating)
+	s.Zero(movie.VotesCount)
+
+	s.createReview(movie.Id, regularUserId, 7)
+	s.createReview(movie.Id, adminUserId, 8)
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal(7.5, got.CommunityRating)
+	s.Equal(uint64(2), got.VotesCount)
+	// the editorial rating does not depend on reviews
+	s.Equal(5.0, got.Rating)
+
+	for _, m := range s.allMovies("") {
+		if m.Id == movie.Id {
+			s.Equal(7.5, m.CommunityRating)
+			s.Equal(uint64(2), m.VotesCount)
+		}
+	}
+}
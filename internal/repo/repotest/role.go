@@ -0,0 +1,124 @@
+package repotest
+
+import (
+	"fmt"
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+// createRole creates the role with unique name and schedules its deletion.
+// Names of roles are short, so the name is made unique by a part of the
+// current time instead of the prefix.
+func (s *Suite) createRole(name string, permissions ...model.Permission) model.RolePermissions {
+	role, err := s.r.CreateRole(s.ctx, model.RolePermissions{
+		Role:        model.Role(fmt.Sprintf("t%d-%s", time.Now().UnixNano()%1e9, name)),
+		Permissions: permissions,
+	})
+	s.Require().NoError(err)
+	s.rolesToDelete = append(s.rolesToDelete, role.Role)
+	return role
+}
+
+func (s *Suite) TestDefaultRoles() {
+	admin, err := s.r.GetRole(s.ctx, model.Admin)
+	s.Require().NoError(err)
+	for _, p := range model.Permissions {
+		s.True(admin.Has(p), p)
+	}
+	regular, err := s.r.GetRole(s.ctx, model.Regular)
+	s.Require().NoError(err)
+	s.Empty(regular.Permissions)
+
+	roles, err := s.r.GetRoles(s.ctx)
+	s.Require().NoError(err)
+	names := make([]model.Role, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Role)
+	}
+	s.Subset(names, []model.Role{model.Admin, model.Regular, "editor", "moderator"})
+	s.IsIncreasing(names)
+}
+
+func (s *Suite) TestCreateRole() {
+	// permissions are ordered by name and not repeated
+	role := s.createRole("a", model.UsersManage, model.MoviesWrite, model.MoviesWrite)
+	s.Equal([]model.Permission{model.MoviesWrite, model.UsersManage}, role.Permissions)
+
+	got, err := s.r.GetRole(s.ctx, role.Role)
+	s.Require().NoError(err)
+	s.Equal(role, got)
+	roles, err := s.r.GetRoles(s.ctx)
+	s.Require().NoError(err)
+	s.Contains(roles, role)
+
+	empty := s.createRole("b")
+	s.Empty(empty.Permissions)
+
+	_, err = s.r.CreateRole(s.ctx, model.RolePermissions{Role: role.Role})
+	s.ErrorIs(err, model.ErrConflict)
+	for name, r := range map[string]model.RolePermissions{
+		"empty name":         {Role: ""},
+		"long name":          {Role: model.Role(strings.Repeat("a", 21))},
+		"unknown permission": {Role: "t-unknown", Permissions: []model.Permission{"movies:read"}},
+	} {
+		_, err = s.r.CreateRole(s.ctx, r)
+		s.ErrorIs(err, model.ErrValidationError, name)
+	}
+	_, err = s.r.GetRole(s.ctx, "t-unknown")
+	s.ErrorIs(err, model.ErrRoleNotExists)
+}
+
+func (s *Suite) TestUpdateRole() {
+	role := s.createRole("a", model.MoviesWrite)
+
+	updated, err := s.r.UpdateRole(s.ctx, model.RolePermissions{
+		Role:        role.Role,
+		Permissions: []model.Permission{model.ReviewsModerate, model.ActorsDelete},
+	})
+	s.Require().NoError(err)
+	s.Equal([]model.Permission{model.ActorsDelete, model.ReviewsModerate}, updated.Permissions)
+	got, err := s.r.GetRole(s.ctx, role.Role)
+	s.Require().NoError(err)
+	s.Equal(updated, got)
+
+	// permissions are not changed if the new ones are invalid
+	_, err = s.r.UpdateRole(s.ctx, model.RolePermissions{
+		Role:        role.Role,
+		Permissions: []model.Permission{model.MoviesWrite, "movies:read"},
+	})
+	s.ErrorIs(err, model.ErrValidationError)
+	got, err = s.r.GetRole(s.ctx, role.Role)
+	s.Require().NoError(err)
+	s.Equal(updated, got)
+
+	updated, err = s.r.UpdateRole(s.ctx, model.RolePermissions{Role: role.Role})
+	s.Require().NoError(err)
+	s.Empty(updated.Permissions)
+
+	_, err = s.r.UpdateRole(s.ctx, model.RolePermissions{Role: "t-unknown"})
+	s.ErrorIs(err, model.ErrRoleNotExists)
+}
+
+func (s *Suite) TestDeleteRole() {
+	role := s.createRole("a", model.MoviesWrite)
+	user := s.createUser("User", role.Role)
+
+	got, err := s.r.GetUserRole(s.ctx, user.Id)
+	s.Require().NoError(err)
+	s.Equal(role.Role, got)
+
+	s.ErrorIs(s.r.DeleteRole(s.ctx, role.Role), model.ErrRoleInUse)
+	_, err = s.r.GetRole(s.ctx, role.Role)
+	s.NoError(err)
+
+	_, err = s.r.UpdateUser(s.ctx, user.Id, model.UpdateUser{Username: user.Username, Role: model.Regular})
+	s.Require().NoError(err)
+	s.Require().NoError(s.r.DeleteRole(s.ctx, role.Role))
+	_, err = s.r.GetRole(s.ctx, role.Role)
+	s.ErrorIs(err, model.ErrRoleNotExists)
+	s.ErrorIs(s.r.DeleteRole(s.ctx, role.Role), model.ErrRoleNotExists)
+
+	_, err = s.r.UpdateUser(s.ctx, user.Id, model.UpdateUser{Username: user.Username, Role: role.Role})
+	s.ErrorIs(err, model.ErrRoleNotExists)
+}
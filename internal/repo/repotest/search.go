@@ -0,0 +1,93 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+// every fuzzy query contains the prefix, so movies and actors of other tests
+// are not similar to it
+
+func (s *Suite) TestFuzzySearchMovies() {
+	actor := s.createActor("Actor", model.Male)
+	m1 := s.createMovie("Matrix Reloaded", 5, date(2003, time.May, 15), actor.Id)
+	m2 := s.createMovie("Matrix Revolutions", 5, date(2003, time.November, 5))
+
+	got, err := s.r.FuzzySearch(s.ctx, s.prefix+"Matrx Reloded", model.MaxFuzzySearchLimit)
+	s.Require().NoError(err)
+	s.Equal([]uint64{m1.Id, m2.Id}, filterIds(moviesIds(got.Movies), m1.Id, m2.Id))
+	for _, movie := range got.Movies {
+		if movie.Id == m1.Id {
+			s.Greater(movie.Relevance, 0.3)
+			s.LessOrEqual(movie.Relevance, 1.)
+			s.Equal([]uint64{actor.Id}, actorsIds(movie.Actors))
+		}
+	}
+
+	// the exact title is the most similar
+	got, err = s.r.FuzzySearch(s.ctx, s.prefix+"Matrix Revolutions", 1)
+	s.Require().NoError(err)
+	s.Equal([]uint64{m2.Id}, moviesIds(got.Movies))
+	s.Equal(1., got.Movies[0].Relevance)
+
+	got, err = s.r.FuzzySearch(s.ctx, "completely different query", model.MaxFuzzySearchLimit)
+	s.Require().NoError(err)
+	s.Empty(filterIds(moviesIds(got.Movies), m1.Id, m2.Id))
+}
+
+func (s *Suite) TestFuzzySearchActors() {
+	a1 := s.createActor("Keanu", model.Male)
+	a2 := s.createActor("Carrie-Anne", model.Female)
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1), a1.Id)
+
+	got, err := s.r.FuzzySearch(s.ctx, s.prefix+"Keanu "+s.prefix+"Surnam", model.MaxFuzzySearchLimit)
+	s.Require().NoError(err)
+	s.Equal([]uint64{a1.Id, a2.Id}, filterIds(actorsIds(got.Actors), a1.Id, a2.Id))
+	for _, actor := range got.Actors {
+		switch actor.Id {
+		case a1.Id:
+			s.Greater(actor.Relevance, 0.3)
+			s.Equal([]uint64{movie.Id}, moviesIds(actor.Movies))
+		case a2.Id:
+			s.NotNil(actor.Movies)
+			s.Empty(actor.Movies)
+		}
+	}
+}
+
+func (s *Suite) TestSuggest() {
+	m1 := s.createMovie("Matrix Reloaded", 5, date(2003, time.May, 15))
+	m2 := s.createMovie("Matrix", 5, date(1999, time.March, 31))
+	s.createMovie("The Matrix Revolutions", 5, date(2003, time.November, 5))
+	actor := s.createActor("Mat", model.Male)
+
+	got, err := s.r.Suggest(s.ctx, s.prefix+"MAT", model.MaxSuggestLimit)
+	s.Require().NoError(err)
+	s.Equal([]model.Suggestion{
+		{Id: m2.Id, Type: model.MovieSuggestion, Text: m2.Title},
+		{Id: m1.Id, Type: model.MovieSuggestion, Text: m1.Title},
+		{Id: actor.Id, Type: model.ActorSuggestion, Text: actor.FirstName + " " + actor.SecondName},
+	}, got)
+
+	got, err = s.r.Suggest(s.ctx, s.prefix+"mat", 2)
+	s.Require().NoError(err)
+	s.Equal([]uint64{m2.Id, m1.Id}, suggestionsIds(got))
+
+	// actors are suggested by the second name too
+	got, err = s.r.Suggest(s.ctx, s.prefix+"surn", model.MaxSuggestLimit)
+	s.Require().NoError(err)
+	s.Equal([]uint64{actor.Id}, suggestionsIds(got))
+
+	// LIKE wildcards are matched literally
+	got, err = s.r.Suggest(s.ctx, s.prefix+"%", model.MaxSuggestLimit)
+	s.Require().NoError(err)
+	s.Empty(got)
+}
+
+func suggestionsIds(suggestions []model.Suggestion) []uint64 {
+	ids := make([]uint64, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		ids = append(ids, suggestion.Id)
+	}
+	return ids
+}
@@ -0,0 +1,87 @@
+package repotest
+
+import (
+	"errors"
+	"movie-lib/internal/model"
+	"movie-lib/internal/repo"
+	"time"
+)
+
+func (s *Suite) TestWithTxCommit() {
+	var actor model.Actor
+	var movie model.Movie
+	err := s.r.WithTx(s.ctx, func(r repo.Repo) error {
+		var err error
+		if actor, err = r.CreateActor(s.ctx, model.Actor{FirstName: s.prefix + "Actor"}); err != nil {
+			return err
+		}
+		s.actorsIdsToDelete = append(s.actorsIdsToDelete, actor.Id)
+
+		movie, err = r.CreateMovie(s.ctx, model.Movie{
+			Title:       s.prefix + "Movie",
+			ReleaseDate: date(2020, time.January, 1),
+			ActorsId:    []uint64{actor.Id},
+		})
+		if err != nil {
+			return err
+		}
+		s.moviesIdsToDelete = append(s.moviesIdsToDelete, movie.Id)
+		return nil
+	})
+	s.Require().NoError(err)
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal([]uint64{actor.Id}, actorsIds(got.Actors))
+}
+
+func (s *Suite) TestWithTxRollback() {
+	movie := s.createMovie("Movie", 5, date(2020, time.January, 1))
+	errRollback := errors.New("rollback")
+
+	var actor model.Actor
+	err := s.r.WithTx(s.ctx, func(r repo.Repo) error {
+		var err error
+		if actor, err = r.CreateActor(s.ctx, model.Actor{FirstName: s.prefix + "Actor"}); err != nil {
+			return err
+		}
+		s.actorsIdsToDelete = append(s.actorsIdsToDelete, actor.Id)
+
+		if _, err = r.UpdateMovie(s.ctx, movie.Id, model.UpdateMovie{
+			Title:       s.prefix + "Updated",
+			ReleaseDate: movie.ReleaseDate,
+			Actors:      []uint64{actor.Id},
+		}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	s.ErrorIs(err, errRollback)
+
+	_, err = s.r.GetActor(s.ctx, actor.Id)
+	s.ErrorIs(err, model.ErrActorNotExists)
+
+	got, err := s.r.GetMovie(s.ctx, movie.Id)
+	s.Require().NoError(err)
+	s.Equal(movie.Title, got.Title)
+	s.Empty(got.Actors)
+}
+
+func (s *Suite) TestWithTxFailedOperation() {
+	actor := s.createActor("Actor", model.Male)
+
+	err := s.r.WithTx(s.ctx, func(r repo.Repo) error {
+		if err := r.DeleteActor(s.ctx, actor.Id); err != nil {
+			return err
+		}
+		_, err := r.CreateMovie(s.ctx, model.Movie{
+			Title:    s.prefix + "Movie",
+			ActorsId: []uint64{actor.Id},
+		})
+		return err
+	})
+	s.ErrorIs(err, model.ErrActorNotExists)
+
+	_, err = s.r.GetActor(s.ctx, actor.Id)
+	s.NoError(err)
+}
@@ -0,0 +1,202 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"strings"
+	"time"
+)
+
+// createUser creates user with unique username and schedules its deletion
+func (s *Suite) createUser(username string, role model.Role) model.User {
+	user, err := s.r.CreateUser(s.ctx, model.User{
+		Username: s.prefix + username,
+		Role:     role,
+	})
+	s.Require().NoError(err)
+	s.Require().NotZero(user.Id)
+	s.usersIdsToDelete = append(s.usersIdsToDelete, user.Id)
+	return user
+}
+
+func (s *Suite) TestGetUserRole() {
+	role, err := s.r.GetUserRole(s.ctx, adminUserId)
+	s.Require().NoError(err)
+	s.Equal(model.Admin, role)
+
+	role, err = s.r.GetUserRole(s.ctx, regularUserId)
+	s.Require().NoError(err)
+	s.Equal(model.Regular, role)
+
+	_, err = s.r.GetUserRole(s.ctx, 0)
+	s.ErrorIs(err, model.ErrUserNotExists)
+}
+
+func (s *Suite) TestCreateUser() {
+	user, err := s.r.CreateUser(s.ctx, model.User{
+		Username: s.prefix + "User",
+		Email:    s.prefix + "user@example.com",
+		Role:     model.Regular,
+	})
+	s.Require().NoError(err)
+	s.usersIdsToDelete = append(s.usersIdsToDelete, user.Id)
+	s.Equal(s.prefix+"User", user.Username)
+	s.Equal(s.prefix+"user@example.com", user.Email)
+	s.Equal(model.Regular, user.Role)
+	s.False(user.Disabled)
+	s.WithinDuration(time.Now(), user.CreatedAt, time.Minute)
+
+	got, err := s.r.GetUser(s.ctx, user.Id)
+	s.Require().NoError(err)
+	s.Equal(user, got)
+
+	// usernames and emails are unique regardless of case, emails are optional
+	_, err = s.r.CreateUser(s.ctx, model.User{Username: strings.ToUpper(s.prefix + "User"), Role: model.Regular})
+	s.ErrorIs(err, model.ErrConflict)
+	_, err = s.r.CreateUser(s.ctx, model.User{
+		Username: s.prefix + "Other",
+		Email:    strings.ToUpper(s.prefix + "user@example.com"),
+		Role:     model.Regular,
+	})
+	s.ErrorIs(err, model.ErrConflict)
+	noEmail := s.createUser("NoEmail", model.Regular)
+	s.Empty(noEmail.Email)
+	s.createUser("NoEmailToo", model.Regular)
+
+	for name, u := range map[string]model.User{
+		"empty username": {Username: "", Role: model.Regular},
+		"long username":  {Username: strings.Repeat("a", 51), Role: model.Regular},
+		"long email":     {Username: s.prefix + "Email", Email: strings.Repeat("a", 255), Role: model.Regular},
+	} {
+		_, err = s.r.CreateUser(s.ctx, u)
+		s.ErrorIs(err, model.ErrValidationError, name)
+	}
+	_, err = s.r.CreateUser(s.ctx, model.User{Username: s.prefix + "Role", Role: "owner"})
+	s.ErrorIs(err, model.ErrRoleNotExists)
+
+	_, err = s.r.GetUser(s.ctx, 0)
+	s.ErrorIs(err, model.ErrUserNotExists)
+}
+
+func (s *Suite) TestUpdateUser() {
+	user := s.createUser("User", model.Regular)
+	other := s.createUser("Other", model.Regular)
+
+	updated, err := s.r.UpdateUser(s.ctx, user.Id, model.UpdateUser{
+		Username: s.prefix + "Renamed",
+		Email:    s.prefix + "renamed@example.com",
+		Role:     model.Admin,
+	})
+	s.Require().NoError(err)
+	s.Equal(s.prefix+"Renamed", updated.Username)
+	s.Equal(s.prefix+"renamed@example.com", updated.Email)
+	s.Equal(model.Admin, updated.Role)
+	s.True(user.CreatedAt.Equal(updated.CreatedAt))
+
+	role, err := s.r.GetUserRole(s.ctx, user.Id)
+	s.Require().NoError(err)
+	s.Equal(model.Admin, role)
+
+	// disabled users are not active
+	_, err = s.r.UpdateUser(s.ctx, user.Id, model.UpdateUser{
+		Username: updated.Username,
+		Role:     model.Admin,
+		Disabled: true,
+	})
+	s.Require().NoError(err)
+	_, err = s.r.GetUserRole(s.ctx, user.Id)
+	s.ErrorIs(err, model.ErrUserNotExists)
+	got, err := s.r.GetUser(s.ctx, user.Id)
+	s.Require().NoError(err)
+	s.True(got.Disabled)
+	s.Empty(got.Email)
+
+	_, err = s.r.UpdateUser(s.ctx, other.Id, model.UpdateUser{Username: updated.Username, Role: model.Regular})
+	s.ErrorIs(err, model.ErrConflict)
+	_, err = s.r.UpdateUser(s.ctx, other.Id, model.UpdateUser{Username: "", Role: model.Regular})
+	s.ErrorIs(err, model.ErrValidationError)
+	_, err = s.r.UpdateUser(s.ctx, 0, model.UpdateUser{Username: s.prefix + "Missing", Role: model.Regular})
+	s.ErrorIs(err, model.ErrUserNotExists)
+}
+
+func (s *Suite) TestDeleteUser() {
+	user := s.createUser("User", model.Regular)
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1))
+	s.createReview(m1.Id, user.Id, 5)
+	s.Require().NoError(s.r.AddToWatchlist(s.ctx, user.Id, m1.Id))
+	collection := s.createCollection(user.Id, "Collection", model.Public)
+	shared := s.createCollection(regularUserId, "Shared", model.Private, user.Id)
+
+	s.Require().NoError(s.r.DeleteUser(s.ctx, user.Id))
+	s.ErrorIs(s.r.DeleteUser(s.ctx, user.Id), model.ErrUserNotExists)
+
+	// data of the user is deleted together with the user
+	reviews, err := s.r.GetReviews(s.ctx, model.ReviewFilter{MovieId: m1.Id}, model.Page{Limit: 10})
+	s.Require().NoError(err)
+	s.Empty(reviews.Reviews)
+	_, err = s.r.GetCollection(s.ctx, collection.Id)
+	s.ErrorIs(err, model.ErrCollectionNotExists)
+	got, err := s.r.GetCollection(s.ctx, shared.Id)
+	s.Require().NoError(err)
+	s.Empty(got.SharedWith)
+}
+
+func (s *Suite) TestLastAdmin() {
+	admin := s.createUser("Admin", model.Admin)
+
+	// the admin can be demoted while another active admin exists
+	_, err := s.r.UpdateUser(s.ctx, admin.Id, model.UpdateUser{Username: admin.Username, Role: model.Regular})
+	s.Require().NoError(err)
+	_, err = s.r.UpdateUser(s.ctx, admin.Id, model.UpdateUser{Username: admin.Username, Role: model.Admin})
+	s.Require().NoError(err)
+	s.Require().NoError(s.r.DeleteUser(s.ctx, admin.Id))
+
+	admins := 0
+	list, err := s.r.GetUsers(s.ctx, model.Page{Limit: model.MaxPageLimit})
+	s.Require().NoError(err)
+	for _, user := range list.Users {
+		if user.Role == model.Admin && !user.Disabled {
+			admins++
+		}
+	}
+	if list.NextCursor != "" || admins != 1 {
+		// the shared database has other admins
+		return
+	}
+
+	defaultAdmin, err := s.r.GetUser(s.ctx, adminUserId)
+	s.Require().NoError(err)
+	for name, upd := range map[string]model.UpdateUser{
+		"demoting":  {Username: defaultAdmin.Username, Email: defaultAdmin.Email, Role: model.Regular},
+		"disabling": {Username: defaultAdmin.Username, Email: defaultAdmin.Email, Role: model.Admin, Disabled: true},
+	} {
+		_, err = s.r.UpdateUser(s.ctx, adminUserId, upd)
+		s.ErrorIs(err, model.ErrLastAdmin, name)
+	}
+	s.ErrorIs(s.r.DeleteUser(s.ctx, adminUserId), model.ErrLastAdmin)
+
+	role, err := s.r.GetUserRole(s.ctx, adminUserId)
+	s.Require().NoError(err)
+	s.Equal(model.Admin, role)
+}
+
+func (s *Suite) TestGetUsers() {
+	u1 := s.createUser("User1", model.Regular)
+	u2 := s.createUser("User2", model.Admin)
+
+	ids := make([]uint64, 0)
+	page := model.Page{Limit: 1}
+	for {
+		list, err := s.r.GetUsers(s.ctx, page)
+		s.Require().NoError(err)
+		s.Require().LessOrEqual(len(list.Users), 1)
+		for _, user := range list.Users {
+			ids = append(ids, user.Id)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		page.Cursor = list.NextCursor
+	}
+	s.Equal([]uint64{adminUserId, regularUserId, u1.Id, u2.Id},
+		filterIds(ids, adminUserId, regularUserId, u1.Id, u2.Id))
+}
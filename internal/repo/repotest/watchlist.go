@@ -0,0 +1,125 @@
+package repotest
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+func (s *Suite) TestWatchlist() {
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1))
+	m2 := s.createMovie("Movie2", 5, date(2020, time.January, 1))
+
+	s.Require().NoError(s.r.AddToWatchlist(s.ctx, regularUserId, m1.Id))
+	// adding of the movie which is already in the watchlist is not an error
+	s.Require().NoError(s.r.AddToWatchlist(s.ctx, regularUserId, m1.Id))
+
+	s.ErrorIs(s.r.AddToWatchlist(s.ctx, regularUserId, 0), model.ErrMovieNotExists)
+	s.ErrorIs(s.r.AddToWatchlist(s.ctx, 0, m1.Id), model.ErrUserNotExists)
+
+	statuses, err := s.r.GetMoviesStatus(s.ctx, regularUserId, []uint64{m1.Id, m2.Id})
+	s.Require().NoError(err)
+	s.Equal(map[uint64]model.MovieStatus{m1.Id: {InWatchlist: true}}, statuses)
+
+	// lists are personal
+	statuses, err = s.r.GetMoviesStatus(s.ctx, adminUserId, []uint64{m1.Id, m2.Id})
+	s.Require().NoError(err)
+	s.Empty(statuses)
+
+	s.Require().NoError(s.r.RemoveFromWatchlist(s.ctx, regularUserId, m1.Id))
+	s.ErrorIs(s.r.RemoveFromWatchlist(s.ctx, regularUserId, m1.Id), model.ErrEntryNotExists)
+
+	statuses, err = s.r.GetMoviesStatus(s.ctx, regularUserId, []uint64{m1.Id})
+	s.Require().NoError(err)
+	s.Empty(statuses)
+}
+
+func (s *Suite) TestWatched() {
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1))
+
+	s.Require().NoError(s.r.MarkWatched(s.ctx, regularUserId, m1.Id, time.Date(2023, time.May, 1, 15, 0, 0, 0, time.UTC)))
+	s.Require().NoError(s.r.AddToWatchlist(s.ctx, regularUserId, m1.Id))
+
+	statuses, err := s.r.GetMoviesStatus(s.ctx, regularUserId, []uint64{m1.Id})
+	s.Require().NoError(err)
+	s.Require().Contains(statuses, m1.Id)
+	s.True(statuses[m1.Id].InWatchlist)
+	s.True(statuses[m1.Id].Watched)
+	// the date of watching is stored without time
+	s.True(date(2023, time.May, 1).Equal(statuses[m1.Id].WatchedAt))
+
+	// marking of the watched movie changes the date
+	s.Require().NoError(s.r.MarkWatched(s.ctx, regularUserId, m1.Id, date(2023, time.June, 2)))
+	statuses, err = s.r.GetMoviesStatus(s.ctx, regularUserId, []uint64{m1.Id})
+	s.Require().NoError(err)
+	s.True(date(2023, time.June, 2).Equal(statuses[m1.Id].WatchedAt))
+
+	s.ErrorIs(s.r.MarkWatched(s.ctx, regularUserId, 0, date(2023, time.June, 2)), model.ErrMovieNotExists)
+
+	s.Require().NoError(s.r.UnmarkWatched(s.ctx, regularUserId, m1.Id))
+	s.ErrorIs(s.r.UnmarkWatched(s.ctx, regularUserId, m1.Id), model.ErrEntryNotExists)
+	statuses, err = s.r.GetMoviesStatus(s.ctx, regularUserId, []uint64{m1.Id})
+	s.Require().NoError(err)
+	s.Equal(map[uint64]model.MovieStatus{m1.Id: {InWatchlist: true}}, statuses)
+
+	// entries are deleted together with the movie
+	s.Require().NoError(s.r.DeleteMovie(s.ctx, m1.Id))
+	statuses, err = s.r.GetMoviesStatus(s.ctx, regularUserId, []uint64{m1.Id})
+	s.Require().NoError(err)
+	s.Empty(statuses)
+}
+
+func (s *Suite) TestGetMoviesWithWatchlistFilter() {
+	actor := s.createActor("Actor", model.Male)
+	m1 := s.createMovie("Movie1", 5, date(2020, time.January, 1), actor.Id)
+	m2 := s.createMovie("Movie2", 6, date(2020, time.January, 1), actor.Id)
+	m3 := s.createMovie("Movie3", 7, date(2020, time.January, 1))
+	all := []uint64{m1.Id, m2.Id, m3.Id}
+
+	s.Require().NoError(s.r.AddToWatchlist(s.ctx, regularUserId, m1.Id))
+	s.Require().NoError(s.r.AddToWatchlist(s.ctx, regularUserId, m3.Id))
+	s.Require().NoError(s.r.MarkWatched(s.ctx, regularUserId, m1.Id, date(2023, time.May, 1)))
+	s.Require().NoError(s.r.MarkWatched(s.ctx, adminUserId, m2.Id, date(2023, time.May, 1)))
+
+	yes, no := true, false
+	tests := []struct {
+		description string
+		filter      model.MovieFilter
+		want        []uint64
+	}{
+		{
+			description: "movies in the watchlist",
+			filter:      model.MovieFilter{InWatchlist: &yes, UserId: regularUserId},
+			want:        []uint64{m3.Id, m1.Id},
+		},
+		{
+			description: "movies not in the watchlist",
+			filter:      model.MovieFilter{InWatchlist: &no, UserId: regularUserId},
+			want:        []uint64{m2.Id},
+		},
+		{
+			description: "watched movies",
+			filter:      model.MovieFilter{Watched: &yes, UserId: regularUserId},
+			want:        []uint64{m1.Id},
+		},
+		{
+			description: "unwatched movies with the actor",
+			filter:      model.MovieFilter{Watched: &no, UserId: regularUserId, ActorsId: []uint64{actor.Id}},
+			want:        []uint64{m2.Id},
+		},
+		{
+			description: "unwatched movies in the watchlist",
+			filter:      model.MovieFilter{InWatchlist: &yes, Watched: &no, UserId: regularUserId},
+			want:        []uint64{m3.Id},
+		},
+		{
+			description: "watched movies of another user",
+			filter:      model.MovieFilter{Watched: &yes, UserId: adminUserId},
+			want:        []uint64{m2.Id},
+		},
+	}
+
+	for _, test := range tests {
+		got := filterIds(moviesIds(s.filterMovies(test.filter, "")), all...)
+		s.Equal(test.want, got, test.description)
+	}
+}
@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+)
+
+const (
+	reviewColumns = `"reviews"."id", "reviews"."movie_id", "reviews"."user_id", "reviews"."rating",
+		"reviews"."text", "reviews"."created_at", "reviews"."updated_at"`
+
+	createReviewQuery = `
+		INSERT INTO "reviews" ("movie_id", "user_id", "rating", "text")
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + reviewColumns + `;`
+
+	updateReviewQuery = `
+		UPDATE "reviews"
+		SET "rating" = $2,
+		    "text" = $3,
+		    "updated_at" = now()
+		WHERE "id" = $1
+		RETURNING ` + reviewColumns + `;`
+
+	deleteReviewQuery = `
+		DELETE FROM "reviews"
+		WHERE "id" = $1;`
+
+	getReviewQuery = `
+		SELECT ` + reviewColumns + ` FROM "reviews"
+		WHERE "id" = $1;`
+
+	// getMoviesVotesQuery loads the community rating of several movies at
+	// once, movies without reviews are not returned
+	getMoviesVotesQuery = `
+		SELECT "reviews"."movie_id", avg("reviews"."rating")::float8, count(*)
+		FROM "reviews"
+		WHERE "reviews"."movie_id" = ANY($1::bigint[])
+		GROUP BY "reviews"."movie_id";`
+)
+
+func (r *repoImpl) CreateReview(ctx context.Context, review model.Review) (model.Review, error) {
+	review, err := scanReview(r.QueryRow(ctx, createReviewQuery,
+		review.MovieId,
+		review.UserId,
+		review.Rating,
+		review.Text,
+	))
+	if err != nil {
+		return model.Review{}, mapError(err)
+	}
+	return review, nil
+}
+
+func (r *repoImpl) UpdateReview(ctx context.Context, id uint64, upd model.UpdateReview) (model.Review, error) {
+	review, err := scanReview(r.QueryRow(ctx, updateReviewQuery, id, upd.Rating, upd.Text))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Review{}, model.ErrReviewNotExists
+	} else if err != nil {
+		return model.Review{}, mapError(err)
+	}
+	return review, nil
+}
+
+func (r *repoImpl) DeleteReview(ctx context.Context, id uint64) error {
+	if e, err := r.Exec(ctx, deleteReviewQuery, id); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrReviewNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) GetReview(ctx context.Context, id uint64) (model.Review, error) {
+	review, err := scanReview(r.QueryRow(ctx, getReviewQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Review{}, model.ErrReviewNotExists
+	} else if err != nil {
+		return model.Review{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return review, nil
+}
+
+func (r *repoImpl) GetReviews(ctx context.Context, filter model.ReviewFilter, page model.Page) (model.ReviewList, error) {
+	var b conditionBuilder
+	b.addReviewFilter(filter)
+	condition, args := b.where()
+
+	keys := reviewSortKeys()
+	reviews, hasNext, total, err := selectPage(ctx, r, `"reviews"`, reviewColumns, keys,
+		condition, args, page, scanReview)
+	if err != nil {
+		return model.ReviewList{}, err
+	}
+	list := model.ReviewList{
+		Reviews: reviews,
+		Total:   total,
+	}
+	if hasNext {
+		list.NextCursor = encodeCursor(keys, reviews[len(reviews)-1])
+	}
+	return list, nil
+}
+
+// loadMoviesVotes sets the community rating and the number of votes of all
+// given movies using one query
+func (r *repoImpl) loadMoviesVotes(ctx context.Context, movies []model.Movie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.Id)
+	}
+
+	rows, err := r.Query(ctx, getMoviesVotesQuery, ids)
+	if err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+	type votes struct {
+		rating float64
+		count  uint64
+	}
+	var (
+		movieId uint64
+		v       votes
+	)
+	byId := make(map[uint64]votes, len(movies))
+	if _, err = pgx.ForEachRow(rows, []any{&movieId, &v.rating, &v.count}, func() error {
+		byId[movieId] = v
+		return nil
+	}); err != nil {
+		return errors.Join(model.ErrDatabaseError, err)
+	}
+
+	for i := range movies {
+		v := byId[movies[i].Id]
+		movies[i].CommunityRating = v.rating
+		movies[i].VotesCount = v.count
+	}
+	return nil
+}
+
+func scanReview(row pgx.Row) (model.Review, error) {
+	var review model.Review
+	err := row.Scan(
+		&review.Id,
+		&review.MovieId,
+		&review.UserId,
+		&review.Rating,
+		&review.Text,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	review.CreatedAt = review.CreatedAt.UTC()
+	review.UpdatedAt = review.UpdatedAt.UTC()
+	return review, err
+}
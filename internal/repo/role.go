@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"movie-lib/internal/model"
+)
+
+const (
+	// roleColumns aggregates permissions of the role joined with the
+	// "role_permissions" table, roles without permissions get an empty array
+	roleColumns = `"roles"."name",
+		coalesce(array_agg("role_permissions"."permission" ORDER BY "role_permissions"."permission" COLLATE "C")
+			FILTER (WHERE "role_permissions"."permission" IS NOT NULL), '{}')`
+
+	createRoleQuery = `
+		INSERT INTO "roles" ("name")
+		VALUES ($1);`
+
+	lockRoleQuery = `
+		SELECT "name" FROM "roles"
+		WHERE "name" = $1
+		FOR UPDATE;`
+
+	deleteRoleQuery = `
+		DELETE FROM "roles"
+		WHERE "name" = $1;`
+
+	getRoleQuery = `
+		SELECT ` + roleColumns + `
+		FROM "roles"
+			LEFT JOIN "role_permissions" ON "role_permissions"."role" = "roles"."name"
+		WHERE "roles"."name" = $1
+		GROUP BY "roles"."name";`
+
+	getRolesQuery = `
+		SELECT ` + roleColumns + `
+		FROM "roles"
+			LEFT JOIN "role_permissions" ON "role_permissions"."role" = "roles"."name"
+		GROUP BY "roles"."name"
+		ORDER BY "roles"."name" COLLATE "C";`
+
+	addRolePermissionsQuery = `
+		INSERT INTO "role_permissions" ("role", "permission")
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING;`
+
+	deleteRolePermissionsQuery = `
+		DELETE FROM "role_permissions"
+		WHERE "role" = $1;`
+)
+
+func (r *repoImpl) CreateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error) {
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		if _, err := tx.Exec(ctx, createRoleQuery, role.Role); err != nil {
+			return mapError(err)
+		}
+		if _, err := tx.Exec(ctx, addRolePermissionsQuery, role.Role, role.Permissions); err != nil {
+			return mapError(err)
+		}
+		var err error
+		role, err = tx.GetRole(ctx, role.Role)
+		return err
+	})
+	if err != nil {
+		return model.RolePermissions{}, err
+	}
+	return role, nil
+}
+
+// UpdateRole replaces permissions of the role
+func (r *repoImpl) UpdateRole(ctx context.Context, role model.RolePermissions) (model.RolePermissions, error) {
+	err := r.inTx(ctx, func(tx *repoImpl) error {
+		var name model.Role
+		if err := tx.QueryRow(ctx, lockRoleQuery, role.Role).Scan(&name); errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrRoleNotExists
+		} else if err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		if _, err := tx.Exec(ctx, deleteRolePermissionsQuery, role.Role); err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		if _, err := tx.Exec(ctx, addRolePermissionsQuery, role.Role, role.Permissions); err != nil {
+			return mapError(err)
+		}
+		var err error
+		role, err = tx.GetRole(ctx, role.Role)
+		return err
+	})
+	if err != nil {
+		return model.RolePermissions{}, err
+	}
+	return role, nil
+}
+
+// DeleteRole deletes the role with its permissions, roles assigned to users
+// can not be deleted
+func (r *repoImpl) DeleteRole(ctx context.Context, name model.Role) error {
+	e, err := r.Exec(ctx, deleteRoleQuery, name)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "users_role_fkey" {
+		return errors.Join(model.ErrRoleInUse, err)
+	} else if err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrRoleNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) GetRole(ctx context.Context, name model.Role) (model.RolePermissions, error) {
+	role, err := scanRole(r.QueryRow(ctx, getRoleQuery, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.RolePermissions{}, model.ErrRoleNotExists
+	} else if err != nil {
+		return model.RolePermissions{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return role, nil
+}
+
+func (r *repoImpl) GetRoles(ctx context.Context) ([]model.RolePermissions, error) {
+	rows, err := r.Query(ctx, getRolesQuery)
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	roles, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RolePermissions, error) {
+		return scanRole(row)
+	})
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	return roles, nil
+}
+
+func scanRole(row pgx.Row) (model.RolePermissions, error) {
+	var role model.RolePermissions
+	err := row.Scan(
+		&role.Role,
+		&role.Permissions,
+	)
+	return role, err
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+)
+
+// suggestCandidatesLimit is the number of candidates read by every part of
+// suggestQuery, it bounds the work done for short and frequent prefixes
+const suggestCandidatesLimit = 100
+
+// Similarity of titles and names to the query is computed by pg_trgm, the %
+// operator selects rows with similarity not less than the similarity
+// threshold which is 0.3 by default
+const (
+	fuzzySearchMoviesQuery = `
+		SELECT ` + movieColumns + `, similarity("movies"."title", $1)::float8 AS "similarity"
+		FROM "movies"
+		WHERE "movies"."title" % $1
+		ORDER BY "similarity" DESC, "movies"."id"
+		LIMIT $2;`
+
+	fuzzySearchActorsQuery = `
+		SELECT ` + actorColumns + `,
+			similarity("actors"."first_name" || ' ' || "actors"."second_name", $1)::float8 AS "similarity"
+		FROM "actors"
+		WHERE ("actors"."first_name" || ' ' || "actors"."second_name") % $1 AND
+			NOT "actors"."crew_only"
+		ORDER BY "similarity" DESC, "actors"."id"
+		LIMIT $2;`
+
+	// suggestQuery selects movies with titles and actors with full or second
+	// names starting with the prefix $1 and returns at most $2 of them. Every
+	// part reads at most $3 candidates in the order of its prefix index, the
+	// candidates are ranked by length. Unlike LIKE with a parameter, the range
+	// condition uses the index in generic plans too: names starting with the
+	// prefix are not less than the prefix and less than the prefix followed by
+	// the greatest code point.
+	suggestQuery = `
+		SELECT "id", "type", "text" FROM (
+			SELECT DISTINCT "id", "type", "text" FROM (
+				(SELECT "id", 'movie' AS "type", "title" AS "text"
+				FROM "movies"
+				WHERE lower("title") ~>=~ lower($1::text) AND
+					lower("title") ~<~ (lower($1::text) || chr(1114111))
+				ORDER BY lower("title") USING ~<~
+				LIMIT $3)
+				UNION ALL
+				(SELECT "id", 'actor' AS "type", "first_name" || ' ' || "second_name" AS "text"
+				FROM "actors"
+				WHERE lower("first_name" || ' ' || "second_name") ~>=~ lower($1::text) AND
+					lower("first_name" || ' ' || "second_name") ~<~ (lower($1::text) || chr(1114111)) AND
+					NOT "crew_only"
+				ORDER BY lower("first_name" || ' ' || "second_name") USING ~<~
+				LIMIT $3)
+				UNION ALL
+				(SELECT "id", 'actor' AS "type", "first_name" || ' ' || "second_name" AS "text"
+				FROM "actors"
+				WHERE lower("second_name") ~>=~ lower($1::text) AND
+					lower("second_name") ~<~ (lower($1::text) || chr(1114111)) AND
+					NOT "crew_only"
+				ORDER BY lower("second_name") USING ~<~
+				LIMIT $3)) AS "candidates") AS "suggestions"
+		ORDER BY length("text"), "type" DESC, "id"
+		LIMIT $2;`
+)
+
+// FuzzySearch finds at most limit movies with titles and limit actors with
+// names similar to the query, so misspelled queries find them too
+func (r *repoImpl) FuzzySearch(ctx context.Context, query string, limit int) (model.FuzzySearchResult, error) {
+	rows, err := r.Query(ctx, fuzzySearchMoviesQuery, query, limit)
+	if err != nil {
+		return model.FuzzySearchResult{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	movies, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Movie, error) {
+		var movie model.Movie
+		err := row.Scan(
+			&movie.Id,
+			&movie.Title,
+			&movie.Description,
+			&movie.ReleaseDate,
+			&movie.Rating,
+			&movie.Relevance,
+		)
+		return movie, err
+	})
+	if err != nil {
+		return model.FuzzySearchResult{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	if err = r.loadMoviesRelations(ctx, movies); err != nil {
+		return model.FuzzySearchResult{}, err
+	}
+
+	rows, err = r.Query(ctx, fuzzySearchActorsQuery, query, limit)
+	if err != nil {
+		return model.FuzzySearchResult{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	actors, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Actor, error) {
+		var actor model.Actor
+		err := row.Scan(
+			&actor.Id,
+			&actor.FirstName,
+			&actor.SecondName,
+			&actor.Gender,
+			&actor.CrewOnly,
+			&actor.Relevance,
+		)
+		return actor, err
+	})
+	if err != nil {
+		return model.FuzzySearchResult{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	if err = r.loadActorsMovies(ctx, actors); err != nil {
+		return model.FuzzySearchResult{}, err
+	}
+
+	return model.FuzzySearchResult{
+		Movies: movies,
+		Actors: actors,
+	}, nil
+}
+
+func (r *repoImpl) Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error) {
+	rows, err := r.Query(ctx, suggestQuery, prefix, limit, suggestCandidatesLimit)
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	suggestions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Suggestion, error) {
+		var suggestion model.Suggestion
+		err := row.Scan(&suggestion.Id, &suggestion.Type, &suggestion.Text)
+		return suggestion, err
+	})
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"movie-lib/internal/model"
+	"time"
+)
+
+var (
+	movieIdKey = sortKey[model.Movie]{
+		name:   "id",
+		column: `"movies"."id"`,
+		value:  func(m model.Movie) any { return m.Id },
+		decode: decodeAs[uint64],
+	}
+	movieTitleKey = sortKey[model.Movie]{
+		name:   "title",
+		column: `"movies"."title"`,
+		value:  func(m model.Movie) any { return m.Title },
+		decode: decodeAs[string],
+	}
+	movieRatingKey = sortKey[model.Movie]{
+		name:   "rating",
+		column: `"movies"."rating"`,
+		value:  func(m model.Movie) any { return m.Rating },
+		decode: decodeAs[float64],
+	}
+	movieReleaseDateKey = sortKey[model.Movie]{
+		name:   "release_date",
+		column: `"movies"."release_date"`,
+		value:  func(m model.Movie) any { return m.ReleaseDate },
+		decode: decodeAs[time.Time],
+	}
+	// movieRelevanceKey is available only for the search, the relevance is
+	// a column of searchMoviesTable
+	movieRelevanceKey = sortKey[model.Movie]{
+		name:   "relevance",
+		column: `"movies"."relevance"`,
+		value:  func(m model.Movie) any { return m.Relevance },
+		decode: decodeAs[float64],
+	}
+
+	actorIdKey = sortKey[model.Actor]{
+		name:   "id",
+		column: `"actors"."id"`,
+		value:  func(a model.Actor) any { return a.Id },
+		decode: decodeAs[uint64],
+	}
+	actorFirstNameKey = sortKey[model.Actor]{
+		name:   "first_name",
+		column: `"actors"."first_name"`,
+		value:  func(a model.Actor) any { return a.FirstName },
+		decode: decodeAs[string],
+	}
+	actorSecondNameKey = sortKey[model.Actor]{
+		name:   "second_name",
+		column: `"actors"."second_name"`,
+		value:  func(a model.Actor) any { return a.SecondName },
+		decode: decodeAs[string],
+	}
+	// actorMoviesCountKey requires movies of the actor to be loaded
+	actorMoviesCountKey = sortKey[model.Actor]{
+		name: "movies_count",
+		column: `(SELECT count(*) FROM "movie-actor"
+			WHERE "movie-actor"."actor_id" = "actors"."id")`,
+		value:  func(a model.Actor) any { return uint64(len(a.Movies)) },
+		decode: decodeAs[uint64],
+	}
+
+	reviewIdKey = sortKey[model.Review]{
+		name:   "id",
+		column: `"reviews"."id"`,
+		value:  func(r model.Review) any { return r.Id },
+		decode: decodeAs[uint64],
+	}
+	collectionIdKey = sortKey[model.Collection]{
+		name:   "id",
+		column: `"collections"."id"`,
+		value:  func(c model.Collection) any { return c.Id },
+		decode: decodeAs[uint64],
+	}
+	userIdKey = sortKey[model.User]{
+		name:   "id",
+		column: `"users"."id"`,
+		value:  func(u model.User) any { return u.Id },
+		decode: decodeAs[uint64],
+	}
+
+	movieSortKeysByParam = map[model.SortParam][]sortKey[model.Movie]{
+		model.Title:       {movieTitleKey},
+		model.Rating:      {movieRatingKey},
+		model.ReleaseDate: {movieReleaseDateKey},
+	}
+	searchMovieSortKeysByParam = map[model.SortParam][]sortKey[model.Movie]{
+		model.Relevance:   {movieRelevanceKey},
+		model.Title:       {movieTitleKey},
+		model.Rating:      {movieRatingKey},
+		model.ReleaseDate: {movieReleaseDateKey},
+	}
+	actorSortKeysByParam = map[model.SortParam][]sortKey[model.Actor]{
+		model.Name:        {actorSecondNameKey, actorFirstNameKey},
+		model.MoviesCount: {actorMoviesCountKey},
+	}
+)
+
+// movieSortKeys returns the order of movies for the sort, movies are sorted
+// by rating descending by default
+func movieSortKeys(sortBy model.Sort) ([]sortKey[model.Movie], error) {
+	if len(sortBy) == 0 {
+		sortBy = model.Sort{{Param: model.Rating, Desc: true}}
+	}
+	return sortKeys(movieSortKeysByParam, sortBy, movieIdKey)
+}
+
+// searchMovieSortKeys returns the order of found movies for the sort, movies
+// are sorted by relevance descending by default
+func searchMovieSortKeys(sortBy model.Sort) ([]sortKey[model.Movie], error) {
+	if len(sortBy) == 0 {
+		sortBy = model.Sort{{Param: model.Relevance, Desc: true}}
+	}
+	return sortKeys(searchMovieSortKeysByParam, sortBy, movieIdKey)
+}
+
+// actorSortKeys returns the order of actors for the sort, actors are sorted
+// by id by default
+func actorSortKeys(sortBy model.Sort) ([]sortKey[model.Actor], error) {
+	return sortKeys(actorSortKeysByParam, sortBy, actorIdKey)
+}
+
+// reviewSortKeys returns the order of reviews, the newest reviews go first
+func reviewSortKeys() []sortKey[model.Review] {
+	key := reviewIdKey
+	key.desc = true
+	return []sortKey[model.Review]{key}
+}
+
+// collectionSortKeys returns the order of collections, collections are
+// ordered by id
+func collectionSortKeys() []sortKey[model.Collection] {
+	return []sortKey[model.Collection]{collectionIdKey}
+}
+
+// userSortKeys returns the order of users, users are ordered by id
+func userSortKeys() []sortKey[model.User] {
+	return []sortKey[model.User]{userIdKey}
+}
+
+// sortKeys returns keys of the sort params followed by the id key
+func sortKeys[T any](byParam map[model.SortParam][]sortKey[T], sortBy model.Sort, idKey sortKey[T]) ([]sortKey[T], error) {
+	keys := make([]sortKey[T], 0, len(sortBy)+1)
+	for _, s := range sortBy {
+		paramKeys, ok := byParam[s.Param]
+		if !ok {
+			return nil, model.ErrValidationError
+		}
+		for _, key := range paramKeys {
+			key.desc = s.Desc
+			keys = append(keys, key)
+		}
+	}
+	return append(keys, idKey), nil
+}
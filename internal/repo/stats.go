@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"context"
+	"movie-lib/internal/model"
+)
+
+func (r *repoImpl) PoolStats(_ context.Context) model.PoolStats {
+	stat := r.pool.Stat()
+	return model.PoolStats{
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDuration:         stat.AcquireDuration(),
+		AcquiredConns:           stat.AcquiredConns(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		ConstructingConns:       stat.ConstructingConns(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		IdleConns:               stat.IdleConns(),
+		MaxConns:                stat.MaxConns(),
+		TotalConns:              stat.TotalConns(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	}
+}
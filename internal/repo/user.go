@@ -7,10 +7,52 @@ import (
 	"movie-lib/internal/model"
 )
 
-const getUserRoleQuery = `
+const (
+	userColumns = `"users"."id", "users"."username", coalesce("users"."email", ''), "users"."role",
+		"users"."disabled", "users"."created_at"`
+
+	// getUserRoleQuery returns roles only of active users, so disabled users
+	// can not perform any operation
+	getUserRoleQuery = `
 		SELECT "role" FROM "users"
+		WHERE "id" = $1 AND NOT "disabled";`
+
+	createUserQuery = `
+		INSERT INTO "users" ("username", "email", "role", "disabled")
+		VALUES ($1, nullif($2, ''), $3, $4)
+		RETURNING ` + userColumns + `;`
+
+	updateUserQuery = `
+		UPDATE "users"
+		SET "username" = $2,
+		    "email" = nullif($3, ''),
+		    "role" = $4,
+		    "disabled" = $5
+		WHERE "id" = $1
+		RETURNING ` + userColumns + `;`
+
+	deleteUserQuery = `
+		DELETE FROM "users"
 		WHERE "id" = $1;`
 
+	getUserQuery = `
+		SELECT ` + userColumns + ` FROM "users"
+		WHERE "id" = $1;`
+
+	// lockAdminsQuery locks active admins until the end of the transaction,
+	// so concurrent transactions can not demote all of them
+	lockAdminsQuery = `
+		SELECT "id" FROM "users"
+		WHERE "role" = 'admin' AND NOT "disabled"
+		FOR UPDATE;`
+
+	hasActiveAdminQuery = `
+		SELECT EXISTS (
+			SELECT 1 FROM "users"
+			WHERE "role" = 'admin' AND NOT "disabled"
+		);`
+)
+
 func (r *repoImpl) GetUserRole(ctx context.Context, id uint64) (model.Role, error) {
 	row := r.QueryRow(ctx, getUserRoleQuery, id)
 	var role model.Role
@@ -21,3 +63,119 @@ func (r *repoImpl) GetUserRole(ctx context.Context, id uint64) (model.Role, erro
 	}
 	return role, nil
 }
+
+func (r *repoImpl) CreateUser(ctx context.Context, user model.User) (model.User, error) {
+	user, err := scanUser(r.QueryRow(ctx, createUserQuery,
+		user.Username,
+		user.Email,
+		user.Role,
+		user.Disabled,
+	))
+	if err != nil {
+		return model.User{}, mapError(err)
+	}
+	return user, nil
+}
+
+func (r *repoImpl) UpdateUser(ctx context.Context, id uint64, upd model.UpdateUser) (model.User, error) {
+	var user model.User
+	err := r.withActiveAdmin(ctx, func(tx *repoImpl) error {
+		var err error
+		user, err = scanUser(tx.QueryRow(ctx, updateUserQuery,
+			id,
+			upd.Username,
+			upd.Email,
+			upd.Role,
+			upd.Disabled,
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrUserNotExists
+		} else if err != nil {
+			return mapError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// DeleteUser deletes the user, reviews, lists and collections of the user
+// are deleted by cascade
+func (r *repoImpl) DeleteUser(ctx context.Context, id uint64) error {
+	return r.withActiveAdmin(ctx, func(tx *repoImpl) error {
+		if e, err := tx.Exec(ctx, deleteUserQuery, id); err != nil {
+			return mapError(err)
+		} else if e.RowsAffected() == 0 {
+			return model.ErrUserNotExists
+		}
+		return nil
+	})
+}
+
+func (r *repoImpl) GetUser(ctx context.Context, id uint64) (model.User, error) {
+	user, err := scanUser(r.QueryRow(ctx, getUserQuery, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.User{}, model.ErrUserNotExists
+	} else if err != nil {
+		return model.User{}, errors.Join(model.ErrDatabaseError, err)
+	}
+	return user, nil
+}
+
+func (r *repoImpl) GetUsers(ctx context.Context, page model.Page) (model.UserList, error) {
+	var b conditionBuilder
+	condition, args := b.where()
+
+	keys := userSortKeys()
+	users, hasNext, total, err := selectPage(ctx, r, `"users"`, userColumns, keys,
+		condition, args, page, scanUser)
+	if err != nil {
+		return model.UserList{}, err
+	}
+	list := model.UserList{
+		Users: users,
+		Total: total,
+	}
+	if hasNext {
+		list.NextCursor = encodeCursor(keys, users[len(users)-1])
+	}
+	return list, nil
+}
+
+// withActiveAdmin runs fn in the transaction and rolls it back with
+// model.ErrLastAdmin if no active admins are left after fn
+func (r *repoImpl) withActiveAdmin(ctx context.Context, fn func(tx *repoImpl) error) error {
+	return r.inTx(ctx, func(tx *repoImpl) error {
+		if _, err := tx.Exec(ctx, lockAdminsQuery); err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		var exists bool
+		if err := tx.QueryRow(ctx, hasActiveAdminQuery).Scan(&exists); err != nil {
+			return errors.Join(model.ErrDatabaseError, err)
+		}
+		if !exists {
+			return model.ErrLastAdmin
+		}
+		return nil
+	})
+}
+
+func scanUser(row pgx.Row) (model.User, error) {
+	var user model.User
+	err := row.Scan(
+		&user.Id,
+		&user.Username,
+		&user.Email,
+		&user.Role,
+		&user.Disabled,
+		&user.CreatedAt,
+	)
+	user.CreatedAt = user.CreatedAt.UTC()
+	return user, err
+}
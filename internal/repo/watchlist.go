@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"movie-lib/internal/model"
+	"time"
+)
+
+const (
+	addToWatchlistQuery = `
+		INSERT INTO "watchlist" ("user_id", "movie_id")
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;`
+
+	removeFromWatchlistQuery = `
+		DELETE FROM "watchlist"
+		WHERE "user_id" = $1 AND "movie_id" = $2;`
+
+	// markWatchedQuery adds the movie to the watched history or changes the
+	// date of watching if the movie is already there
+	markWatchedQuery = `
+		INSERT INTO "watched" ("user_id", "movie_id", "watched_at")
+		VALUES ($1, $2, $3)
+		ON CONFLICT ("user_id", "movie_id") DO UPDATE
+		SET "watched_at" = EXCLUDED."watched_at";`
+
+	unmarkWatchedQuery = `
+		DELETE FROM "watched"
+		WHERE "user_id" = $1 AND "movie_id" = $2;`
+
+	// getMoviesStatusQuery loads the status of several movies at once,
+	// movies which are not in the lists of the user are not returned
+	getMoviesStatusQuery = `
+		SELECT "movies"."id", "watchlist"."movie_id" IS NOT NULL, "watched"."watched_at"
+		FROM unnest($2::bigint[]) AS "movies" ("id")
+			LEFT JOIN "watchlist" ON "watchlist"."movie_id" = "movies"."id" AND "watchlist"."user_id" = $1
+			LEFT JOIN "watched" ON "watched"."movie_id" = "movies"."id" AND "watched"."user_id" = $1
+		WHERE "watchlist"."movie_id" IS NOT NULL OR "watched"."movie_id" IS NOT NULL;`
+)
+
+func (r *repoImpl) AddToWatchlist(ctx context.Context, userId, movieId uint64) error {
+	if _, err := r.Exec(ctx, addToWatchlistQuery, userId, movieId); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
+
+func (r *repoImpl) RemoveFromWatchlist(ctx context.Context, userId, movieId uint64) error {
+	if e, err := r.Exec(ctx, removeFromWatchlistQuery, userId, movieId); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrEntryNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) MarkWatched(ctx context.Context, userId, movieId uint64, watchedAt time.Time) error {
+	if _, err := r.Exec(ctx, markWatchedQuery, userId, movieId, watchedAt); err != nil {
+		return mapError(err)
+	}
+	return nil
+}
+
+func (r *repoImpl) UnmarkWatched(ctx context.Context, userId, movieId uint64) error {
+	if e, err := r.Exec(ctx, unmarkWatchedQuery, userId, movieId); err != nil {
+		return mapError(err)
+	} else if e.RowsAffected() == 0 {
+		return model.ErrEntryNotExists
+	}
+	return nil
+}
+
+func (r *repoImpl) GetMoviesStatus(ctx context.Context, userId uint64, moviesId []uint64) (map[uint64]model.MovieStatus, error) {
+	statuses := make(map[uint64]model.MovieStatus, len(moviesId))
+	if len(moviesId) == 0 {
+		return statuses, nil
+	}
+
+	rows, err := r.Query(ctx, getMoviesStatusQuery, userId, moviesId)
+	if err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	var (
+		movieId   uint64
+		status    model.MovieStatus
+		watchedAt *time.Time
+	)
+	if _, err = pgx.ForEachRow(rows, []any{&movieId, &status.InWatchlist, &watchedAt}, func() error {
+		status.Watched = watchedAt != nil
+		status.WatchedAt = time.Time{}
+		if watchedAt != nil {
+			status.WatchedAt = *watchedAt
+		}
+		statuses[movieId] = status
+		return nil
+	}); err != nil {
+		return nil, errors.Join(model.ErrDatabaseError, err)
+	}
+	return statuses, nil
+}
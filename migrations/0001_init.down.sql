@@ -0,0 +1,7 @@
+DROP TABLE "users";
+
+DROP TABLE "movie-actor";
+
+DROP TABLE "actors";
+
+DROP TABLE "movies";
@@ -29,5 +29,3 @@ VALUES
     ('admin'),
     ('regular')
 ;
-
-SET TIME ZONE 'UTC';
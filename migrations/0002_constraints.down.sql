@@ -0,0 +1,29 @@
+ALTER TABLE "users"
+    DROP CONSTRAINT "users_role_check",
+    ALTER COLUMN "role" DROP NOT NULL;
+
+DROP INDEX "movie-actor_actor_id_idx";
+
+ALTER TABLE "movie-actor"
+    DROP CONSTRAINT "movie-actor_actor_id_fkey",
+    DROP CONSTRAINT "movie-actor_movie-id_fkey",
+    ALTER COLUMN "actor_id" DROP NOT NULL,
+    ALTER COLUMN "movie-id" DROP NOT NULL;
+
+ALTER TABLE "actors"
+    DROP CONSTRAINT "actors_gender_check",
+    ALTER COLUMN "gender" DROP DEFAULT,
+    ALTER COLUMN "gender" DROP NOT NULL,
+    ALTER COLUMN "second_name" DROP DEFAULT,
+    ALTER COLUMN "second_name" DROP NOT NULL,
+    ALTER COLUMN "first_name" DROP DEFAULT,
+    ALTER COLUMN "first_name" DROP NOT NULL;
+
+ALTER TABLE "movies"
+    DROP CONSTRAINT "movies_rating_check",
+    DROP CONSTRAINT "movies_title_check",
+    ALTER COLUMN "rating" DROP NOT NULL,
+    ALTER COLUMN "release_date" DROP NOT NULL,
+    ALTER COLUMN "description" DROP DEFAULT,
+    ALTER COLUMN "description" DROP NOT NULL,
+    ALTER COLUMN "title" DROP NOT NULL;
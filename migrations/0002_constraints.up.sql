@@ -0,0 +1,41 @@
+DELETE FROM "movie-actor"
+WHERE "movie-id" IS NULL OR "actor_id" IS NULL OR
+      "movie-id" NOT IN (SELECT "id" FROM "movies") OR
+      "actor_id" NOT IN (SELECT "id" FROM "actors");
+
+UPDATE "movies" SET "description" = '' WHERE "description" IS NULL;
+UPDATE "actors" SET "first_name" = '' WHERE "first_name" IS NULL;
+UPDATE "actors" SET "second_name" = '' WHERE "second_name" IS NULL;
+UPDATE "actors" SET "gender" = '' WHERE "gender" IS NULL;
+
+ALTER TABLE "movies"
+    ALTER COLUMN "title" SET NOT NULL,
+    ALTER COLUMN "description" SET NOT NULL,
+    ALTER COLUMN "description" SET DEFAULT '',
+    ALTER COLUMN "release_date" SET NOT NULL,
+    ALTER COLUMN "rating" SET NOT NULL,
+    ADD CONSTRAINT "movies_title_check" CHECK ("title" <> ''),
+    ADD CONSTRAINT "movies_rating_check" CHECK ("rating" >= 0 AND "rating" <= 10);
+
+ALTER TABLE "actors"
+    ALTER COLUMN "first_name" SET NOT NULL,
+    ALTER COLUMN "first_name" SET DEFAULT '',
+    ALTER COLUMN "second_name" SET NOT NULL,
+    ALTER COLUMN "second_name" SET DEFAULT '',
+    ALTER COLUMN "gender" SET NOT NULL,
+    ALTER COLUMN "gender" SET DEFAULT '',
+    ADD CONSTRAINT "actors_gender_check" CHECK ("gender" IN ('', 'male', 'female'));
+
+ALTER TABLE "movie-actor"
+    ALTER COLUMN "movie-id" SET NOT NULL,
+    ALTER COLUMN "actor_id" SET NOT NULL,
+    ADD CONSTRAINT "movie-actor_movie-id_fkey" FOREIGN KEY ("movie-id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE,
+    ADD CONSTRAINT "movie-actor_actor_id_fkey" FOREIGN KEY ("actor_id")
+        REFERENCES "actors" ("id") ON DELETE CASCADE;
+
+CREATE INDEX "movie-actor_actor_id_idx" ON "movie-actor" ("actor_id");
+
+ALTER TABLE "users"
+    ALTER COLUMN "role" SET NOT NULL,
+    ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('regular', 'admin'));
@@ -0,0 +1,15 @@
+DROP TRIGGER "actors_search_vector" ON "actors";
+DROP FUNCTION "actors_search_vector_trigger"();
+
+DROP TRIGGER "movie-actor_search_vector" ON "movie-actor";
+DROP FUNCTION "movie-actor_search_vector_trigger"();
+
+DROP TRIGGER "movies_search_vector" ON "movies";
+DROP FUNCTION "movies_search_vector_trigger"();
+
+DROP INDEX "movies_search_vector_idx";
+
+ALTER TABLE "movies"
+    DROP COLUMN "search_vector";
+
+DROP FUNCTION "movie_search_vector"(BIGINT, TEXT, TEXT);
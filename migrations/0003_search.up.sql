@@ -0,0 +1,69 @@
+-- Search document of a movie consists of its title, names of its actors and
+-- description with decreasing weights. The russian configuration stems
+-- russian words by the russian stemmer and latin words by the english one.
+CREATE FUNCTION "movie_search_vector"("movie_id" BIGINT, "title" TEXT, "description" TEXT)
+    RETURNS tsvector
+    LANGUAGE sql
+    STABLE
+AS $$
+SELECT setweight(to_tsvector('russian', "title"), 'A') ||
+       setweight(to_tsvector('russian', coalesce((
+           SELECT string_agg("actors"."first_name" || ' ' || "actors"."second_name", ' ')
+           FROM "movie-actor"
+               INNER JOIN "actors" ON "actors"."id" = "movie-actor"."actor_id"
+           WHERE "movie-actor"."movie-id" = "movie_id"), '')), 'B') ||
+       setweight(to_tsvector('russian', "description"), 'C');
+$$;
+
+ALTER TABLE "movies"
+    ADD COLUMN "search_vector" tsvector NOT NULL DEFAULT ''::tsvector;
+
+UPDATE "movies"
+SET "search_vector" = "movie_search_vector"("id", "title", "description");
+
+CREATE INDEX "movies_search_vector_idx" ON "movies" USING GIN ("search_vector");
+
+-- Search vector of a movie is rebuilt when the movie, its cast or names of
+-- its actors are changed.
+CREATE FUNCTION "movies_search_vector_trigger"() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW."search_vector" := "movie_search_vector"(NEW."id", NEW."title", NEW."description");
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER "movies_search_vector"
+    BEFORE INSERT OR UPDATE OF "title", "description" ON "movies"
+    FOR EACH ROW EXECUTE FUNCTION "movies_search_vector_trigger"();
+
+CREATE FUNCTION "movie-actor_search_vector_trigger"() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE "movies"
+    SET "search_vector" = "movie_search_vector"("id", "title", "description")
+    WHERE "id" = CASE WHEN TG_OP = 'DELETE' THEN OLD."movie-id" ELSE NEW."movie-id" END;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER "movie-actor_search_vector"
+    AFTER INSERT OR DELETE ON "movie-actor"
+    FOR EACH ROW EXECUTE FUNCTION "movie-actor_search_vector_trigger"();
+
+CREATE FUNCTION "actors_search_vector_trigger"() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    UPDATE "movies"
+    SET "search_vector" = "movie_search_vector"("id", "title", "description")
+    WHERE "id" IN (SELECT "movie-id" FROM "movie-actor" WHERE "actor_id" = NEW."id");
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER "actors_search_vector"
+    AFTER UPDATE OF "first_name", "second_name" ON "actors"
+    FOR EACH ROW EXECUTE FUNCTION "actors_search_vector_trigger"();
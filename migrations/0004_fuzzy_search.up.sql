@@ -0,0 +1,6 @@
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+CREATE INDEX "movies_title_trgm_idx" ON "movies" USING GIN ("title" gin_trgm_ops);
+
+CREATE INDEX "actors_name_trgm_idx" ON "actors"
+    USING GIN (("first_name" || ' ' || "second_name") gin_trgm_ops);
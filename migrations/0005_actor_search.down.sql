@@ -0,0 +1,3 @@
+DROP INDEX "actors_second_name_trgm_idx";
+
+DROP INDEX "actors_first_name_trgm_idx";
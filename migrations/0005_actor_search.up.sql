@@ -0,0 +1,4 @@
+-- Trigram indexes are used by ILIKE '%...%' conditions of the actor search.
+CREATE INDEX "actors_first_name_trgm_idx" ON "actors" USING GIN ("first_name" gin_trgm_ops);
+
+CREATE INDEX "actors_second_name_trgm_idx" ON "actors" USING GIN ("second_name" gin_trgm_ops);
@@ -0,0 +1,5 @@
+DROP INDEX "actors_second_name_prefix_idx";
+
+DROP INDEX "actors_name_prefix_idx";
+
+DROP INDEX "movies_title_prefix_idx";
@@ -0,0 +1,8 @@
+-- Prefix indexes serve range conditions of suggestions (~>=~ and ~<~ on the
+-- lower-cased text) and read candidates in their order.
+CREATE INDEX "movies_title_prefix_idx" ON "movies" (lower("title") text_pattern_ops);
+
+CREATE INDEX "actors_name_prefix_idx" ON "actors"
+    (lower("first_name" || ' ' || "second_name") text_pattern_ops);
+
+CREATE INDEX "actors_second_name_prefix_idx" ON "actors" (lower("second_name") text_pattern_ops);
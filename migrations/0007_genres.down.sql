@@ -0,0 +1,3 @@
+DROP TABLE "movie-genre";
+
+DROP TABLE "genres";
@@ -0,0 +1,20 @@
+CREATE TABLE "genres" (
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR(50) NOT NULL,
+    CONSTRAINT "genres_name_check" CHECK ("name" <> '')
+);
+
+-- Names of genres are unique ignoring case.
+CREATE UNIQUE INDEX "genres_name_key" ON "genres" (lower("name"));
+
+CREATE TABLE "movie-genre" (
+    "movie_id" INTEGER NOT NULL,
+    "genre_id" INTEGER NOT NULL,
+    PRIMARY KEY ("movie_id", "genre_id"),
+    CONSTRAINT "movie-genre_movie_id_fkey" FOREIGN KEY ("movie_id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE,
+    CONSTRAINT "movie-genre_genre_id_fkey" FOREIGN KEY ("genre_id")
+        REFERENCES "genres" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "movie-genre_genre_id_idx" ON "movie-genre" ("genre_id");
@@ -0,0 +1,15 @@
+-- Persons are stored in the "actors" table, the cast is linked to movies by
+-- the "movie-actor" table and other roles by this one.
+CREATE TABLE "movie-crew" (
+    "movie_id" INTEGER NOT NULL,
+    "person_id" INTEGER NOT NULL,
+    "role" VARCHAR(20) NOT NULL,
+    PRIMARY KEY ("movie_id", "person_id", "role"),
+    CONSTRAINT "movie-crew_role_check" CHECK ("role" IN ('director', 'writer', 'composer', 'producer')),
+    CONSTRAINT "movie-crew_movie_id_fkey" FOREIGN KEY ("movie_id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE,
+    CONSTRAINT "movie-crew_person_id_fkey" FOREIGN KEY ("person_id")
+        REFERENCES "actors" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "movie-crew_person_id_idx" ON "movie-crew" ("person_id");
@@ -0,0 +1,3 @@
+ALTER TABLE "movie-actor"
+    DROP COLUMN "billing",
+    DROP COLUMN "characters";
@@ -0,0 +1,15 @@
+ALTER TABLE "movie-actor"
+    ADD COLUMN "characters" TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN "billing" INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT "movie-actor_characters_check" CHECK (array_position("characters", '') IS NULL),
+    ADD CONSTRAINT "movie-actor_billing_check" CHECK ("billing" >= 0);
+
+-- The existing cast is billed in the order of ids of actors.
+UPDATE "movie-actor"
+SET "billing" = "numbered"."billing"
+FROM (
+    SELECT "movie-id", "actor_id",
+        row_number() OVER (PARTITION BY "movie-id" ORDER BY "actor_id") AS "billing"
+    FROM "movie-actor") AS "numbered"
+WHERE "movie-actor"."movie-id" = "numbered"."movie-id" AND
+      "movie-actor"."actor_id" = "numbered"."actor_id";
@@ -0,0 +1,19 @@
+-- Every user can review the movie only once, reviews are deleted together
+-- with the movie or the user.
+CREATE TABLE "reviews" (
+    "id" SERIAL PRIMARY KEY,
+    "movie_id" INTEGER NOT NULL,
+    "user_id" INTEGER NOT NULL,
+    "rating" INTEGER NOT NULL,
+    "text" VARCHAR(5000) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT "reviews_rating_check" CHECK ("rating" BETWEEN 1 AND 10),
+    CONSTRAINT "reviews_movie_id_user_id_key" UNIQUE ("movie_id", "user_id"),
+    CONSTRAINT "reviews_movie_id_fkey" FOREIGN KEY ("movie_id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE,
+    CONSTRAINT "reviews_user_id_fkey" FOREIGN KEY ("user_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "reviews_user_id_idx" ON "reviews" ("user_id");
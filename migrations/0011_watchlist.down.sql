@@ -0,0 +1,3 @@
+DROP TABLE "watched";
+
+DROP TABLE "watchlist";
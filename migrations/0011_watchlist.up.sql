@@ -0,0 +1,22 @@
+-- Personal lists of users: movies which the user wants to watch and movies
+-- which the user has watched with the date of watching.
+CREATE TABLE "watchlist" (
+    "user_id" INTEGER NOT NULL,
+    "movie_id" INTEGER NOT NULL,
+    PRIMARY KEY ("user_id", "movie_id"),
+    CONSTRAINT "watchlist_user_id_fkey" FOREIGN KEY ("user_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE,
+    CONSTRAINT "watchlist_movie_id_fkey" FOREIGN KEY ("movie_id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "watched" (
+    "user_id" INTEGER NOT NULL,
+    "movie_id" INTEGER NOT NULL,
+    "watched_at" DATE NOT NULL,
+    PRIMARY KEY ("user_id", "movie_id"),
+    CONSTRAINT "watched_user_id_fkey" FOREIGN KEY ("user_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE,
+    CONSTRAINT "watched_movie_id_fkey" FOREIGN KEY ("movie_id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE
+);
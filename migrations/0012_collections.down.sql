@@ -0,0 +1,5 @@
+DROP TABLE "collection-user";
+
+DROP TABLE "collection-movie";
+
+DROP TABLE "collections";
@@ -0,0 +1,43 @@
+CREATE TABLE "collections" (
+    "id" SERIAL PRIMARY KEY,
+    "owner_id" INTEGER NOT NULL,
+    "name" VARCHAR(100) NOT NULL,
+    "description" VARCHAR(1000) NOT NULL DEFAULT '',
+    "visibility" VARCHAR(10) NOT NULL DEFAULT 'private',
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT "collections_name_check" CHECK ("name" <> ''),
+    CONSTRAINT "collections_visibility_check" CHECK ("visibility" IN ('private', 'public')),
+    CONSTRAINT "collections_owner_id_fkey" FOREIGN KEY ("owner_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "collections_owner_id_idx" ON "collections" ("owner_id");
+
+-- Entries of the collection are ordered by positions, positions may have gaps
+-- after removal of entries.
+CREATE TABLE "collection-movie" (
+    "collection_id" INTEGER NOT NULL,
+    "movie_id" INTEGER NOT NULL,
+    "position" INTEGER NOT NULL,
+    "note" VARCHAR(500) NOT NULL DEFAULT '',
+    PRIMARY KEY ("collection_id", "movie_id"),
+    CONSTRAINT "collection-movie_collection_id_fkey" FOREIGN KEY ("collection_id")
+        REFERENCES "collections" ("id") ON DELETE CASCADE,
+    CONSTRAINT "collection-movie_movie_id_fkey" FOREIGN KEY ("movie_id")
+        REFERENCES "movies" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "collection-movie_movie_id_idx" ON "collection-movie" ("movie_id");
+
+-- Users who can read the collection regardless of its visibility.
+CREATE TABLE "collection-user" (
+    "collection_id" INTEGER NOT NULL,
+    "user_id" INTEGER NOT NULL,
+    PRIMARY KEY ("collection_id", "user_id"),
+    CONSTRAINT "collection-user_collection_id_fkey" FOREIGN KEY ("collection_id")
+        REFERENCES "collections" ("id") ON DELETE CASCADE,
+    CONSTRAINT "collection-user_user_id_fkey" FOREIGN KEY ("user_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "collection-user_user_id_idx" ON "collection-user" ("user_id");
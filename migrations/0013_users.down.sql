@@ -0,0 +1,5 @@
+ALTER TABLE "users"
+    DROP COLUMN "username",
+    DROP COLUMN "email",
+    DROP COLUMN "disabled",
+    DROP COLUMN "created_at";
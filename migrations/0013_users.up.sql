@@ -0,0 +1,18 @@
+-- Accounts of users. Usernames are unique regardless of case, emails are
+-- optional. Disabled users keep their data but can not perform operations.
+ALTER TABLE "users"
+    ADD COLUMN "username" VARCHAR(50),
+    ADD COLUMN "email" VARCHAR(254),
+    ADD COLUMN "disabled" BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN "created_at" TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE "users" SET "username" = 'user' || "id";
+
+ALTER TABLE "users"
+    ALTER COLUMN "username" SET NOT NULL,
+    ADD CONSTRAINT "users_username_check" CHECK ("username" <> ''),
+    ADD CONSTRAINT "users_email_check" CHECK ("email" <> '');
+
+CREATE UNIQUE INDEX "users_username_key" ON "users" (lower("username"));
+
+CREATE UNIQUE INDEX "users_email_key" ON "users" (lower("email"));
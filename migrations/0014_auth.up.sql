@@ -0,0 +1,20 @@
+-- Passwords of users are stored as bcrypt hashes, users without a password
+-- can not log in.
+ALTER TABLE "users"
+    ADD COLUMN "password_hash" VARCHAR(100);
+
+-- Sessions of logged in users. A session is identified by the hash of its
+-- refresh token and is revoked by deletion.
+CREATE TABLE "sessions" (
+    "id" SERIAL PRIMARY KEY,
+    "user_id" INTEGER NOT NULL,
+    "refresh_token_hash" VARCHAR(64) NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT "sessions_user_id_fkey" FOREIGN KEY ("user_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE,
+    CONSTRAINT "sessions_refresh_token_hash_key" UNIQUE ("refresh_token_hash"),
+    CONSTRAINT "sessions_refresh_token_hash_check" CHECK ("refresh_token_hash" <> '')
+);
+
+CREATE INDEX "sessions_user_id_idx" ON "sessions" ("user_id");
@@ -0,0 +1,20 @@
+-- Long-lived API keys of users. Only hashes of keys are stored, prefixes
+-- tell keys apart in lists. Keys without the expiration time never expire.
+CREATE TABLE "api_keys" (
+    "id" SERIAL PRIMARY KEY,
+    "user_id" INTEGER NOT NULL,
+    "name" VARCHAR(100) NOT NULL,
+    "prefix" VARCHAR(20) NOT NULL,
+    "key_hash" VARCHAR(64) NOT NULL,
+    "scope" VARCHAR(10) NOT NULL,
+    "expires_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT "api_keys_user_id_fkey" FOREIGN KEY ("user_id")
+        REFERENCES "users" ("id") ON DELETE CASCADE,
+    CONSTRAINT "api_keys_key_hash_key" UNIQUE ("key_hash"),
+    CONSTRAINT "api_keys_name_check" CHECK ("name" <> ''),
+    CONSTRAINT "api_keys_key_hash_check" CHECK ("key_hash" <> ''),
+    CONSTRAINT "api_keys_scope_check" CHECK ("scope" IN ('read', 'write'))
+);
+
+CREATE INDEX "api_keys_user_id_idx" ON "api_keys" ("user_id");
@@ -0,0 +1,10 @@
+UPDATE "users" SET "role" = 'regular' WHERE "role" NOT IN ('regular', 'admin');
+
+ALTER TABLE "users"
+    DROP CONSTRAINT "users_role_fkey",
+    ALTER COLUMN "role" TYPE VARCHAR(10),
+    ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('regular', 'admin'));
+
+DROP TABLE "role_permissions";
+
+DROP TABLE "roles";
@@ -0,0 +1,46 @@
+-- Roles of users and permissions granted to them. Roles of users reference
+-- this table instead of the fixed list, so new roles can be added without
+-- migrations.
+CREATE TABLE "roles" (
+    "name" VARCHAR(20) PRIMARY KEY,
+    CONSTRAINT "roles_name_check" CHECK ("name" <> '')
+);
+
+CREATE TABLE "role_permissions" (
+    "role" VARCHAR(20) NOT NULL,
+    "permission" VARCHAR(30) NOT NULL,
+    PRIMARY KEY ("role", "permission"),
+    CONSTRAINT "role_permissions_role_fkey" FOREIGN KEY ("role")
+        REFERENCES "roles" ("name") ON DELETE CASCADE,
+    CONSTRAINT "role_permissions_permission_check" CHECK ("permission" IN (
+        'movies:write', 'movies:delete', 'actors:write', 'actors:delete',
+        'genres:write', 'genres:delete', 'reviews:moderate', 'collections:moderate',
+        'users:manage', 'roles:manage', 'stats:read'))
+);
+
+INSERT INTO "roles" ("name")
+VALUES ('admin'), ('regular'), ('editor'), ('moderator');
+
+INSERT INTO "role_permissions" ("role", "permission")
+VALUES ('admin', 'movies:write'),
+       ('admin', 'movies:delete'),
+       ('admin', 'actors:write'),
+       ('admin', 'actors:delete'),
+       ('admin', 'genres:write'),
+       ('admin', 'genres:delete'),
+       ('admin', 'reviews:moderate'),
+       ('admin', 'collections:moderate'),
+       ('admin', 'users:manage'),
+       ('admin', 'roles:manage'),
+       ('admin', 'stats:read'),
+       ('editor', 'movies:write'),
+       ('editor', 'actors:write'),
+       ('editor', 'genres:write'),
+       ('moderator', 'reviews:moderate'),
+       ('moderator', 'collections:moderate');
+
+ALTER TABLE "users"
+    DROP CONSTRAINT "users_role_check",
+    ALTER COLUMN "role" TYPE VARCHAR(20),
+    ADD CONSTRAINT "users_role_fkey" FOREIGN KEY ("role")
+        REFERENCES "roles" ("name");
@@ -0,0 +1,7 @@
+-- Persons credited only in crews are not listed, searched and suggested as
+-- actors, the mark is cleared when the person is cast in a movie.
+ALTER TABLE "actors" ADD COLUMN "crew_only" BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE "actors" SET "crew_only" = TRUE
+WHERE EXISTS (SELECT 1 FROM "movie-crew" WHERE "movie-crew"."person_id" = "actors"."id") AND
+    NOT EXISTS (SELECT 1 FROM "movie-actor" WHERE "movie-actor"."actor_id" = "actors"."id");
@@ -0,0 +1,8 @@
+// Package migrations contains SQL migrations of the database schema which
+// are embedded into the binary
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,322 @@
+// Package migrator applies numbered up/down SQL migrations to PostgreSQL
+// and tracks applied versions in the schema_migrations table.
+//
+// Migrations are read from fs.FS and should be named as
+// <version>_<name>.up.sql and <version>_<name>.down.sql, e.g.
+// 0001_init.up.sql and 0001_init.down.sql. Every migration is applied in its
+// own transaction together with the update of schema_migrations.
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	createMigrationsTableQuery = `
+		CREATE TABLE IF NOT EXISTS "schema_migrations" (
+			"version" BIGINT PRIMARY KEY,
+			"name" VARCHAR(255) NOT NULL,
+			"applied_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`
+
+	getAppliedMigrationsQuery = `
+		SELECT "version", "applied_at" FROM "schema_migrations"
+		ORDER BY "version";`
+
+	addMigrationQuery = `
+		INSERT INTO "schema_migrations" ("version", "name")
+		VALUES ($1, $2);`
+
+	deleteMigrationQuery = `
+		DELETE FROM "schema_migrations"
+		WHERE "version" = $1;`
+
+	// lockKey is a key of the advisory lock which prevents concurrent
+	// migrations from several instances of the application
+	lockKey = 7_320_551_004
+
+	lockQuery   = `SELECT pg_advisory_lock($1);`
+	unlockQuery = `SELECT pg_advisory_unlock($1);`
+)
+
+var (
+	ErrInvalidMigrations = errors.New("invalid migrations")
+	ErrUnknownVersion    = errors.New("migration with required version does not exist")
+)
+
+var fileNameRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a pair of up and down SQL scripts
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a state of the migration in the database
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New reads migrations from fsys and creates Migrator
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+	}, nil
+}
+
+// Load reads migrations from the root of fsys sorted by version. Every
+// migration must have both up and down scripts and a unique version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Join(ErrInvalidMigrations, err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		m := fileNameRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidMigrations, err)
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, errors.Join(ErrInvalidMigrations, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("%w: version %d is used by %q and %q",
+				ErrInvalidMigrations, version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("%w: migration %d_%s should have both up and down scripts",
+				ErrInvalidMigrations, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Up applies all not applied migrations and returns them
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				continue
+			}
+			if err = m.apply(ctx, conn, s.Migration, true); err != nil {
+				return err
+			}
+			applied = append(applied, s.Migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back n last applied migrations and returns them
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	rolledBack := make([]Migration, 0, n)
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(statuses) - 1; i >= 0 && len(rolledBack) < n; i-- {
+			if !statuses[i].Applied {
+				continue
+			}
+			if err = m.apply(ctx, conn, statuses[i].Migration, false); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, statuses[i].Migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status returns states of all known migrations sorted by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		var err error
+		statuses, err = m.status(ctx, conn)
+		return err
+	})
+	return statuses, err
+}
+
+// Force marks all migrations up to version inclusive as applied and all
+// later ones as not applied without running their scripts. It is used to
+// fix the state after manual changes of the schema. Version 0 marks all
+// migrations as not applied.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	if version != 0 && !m.exists(version) {
+		return ErrUnknownVersion
+	}
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx) // does nothing if transaction is committed
+		}()
+
+		for _, s := range statuses {
+			switch {
+			case s.Version <= version && !s.Applied:
+				_, err = tx.Exec(ctx, addMigrationQuery, s.Version, s.Name)
+			case s.Version > version && s.Applied:
+				_, err = tx.Exec(ctx, deleteMigrationQuery, s.Version)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Commit(ctx)
+	})
+}
+
+func (m *Migrator) exists(version uint64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock creates schema_migrations table if needed and executes fn on
+// the connection holding the advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, lockQuery, lockKey); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), unlockQuery, lockKey)
+	}()
+
+	if _, err = conn.Exec(ctx, createMigrationsTableQuery); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) status(ctx context.Context, conn *pgxpool.Conn) ([]Status, error) {
+	rows, err := conn.Query(ctx, getAppliedMigrationsQuery)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[uint64]time.Time)
+	var (
+		version   uint64
+		appliedAt time.Time
+	)
+	if _, err = pgx.ForEachRow(rows, []any{&version, &appliedAt}, func() error {
+		applied[version] = appliedAt
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Migration: migration,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// apply runs up or down script of the migration and updates schema_migrations
+// in one transaction
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, migration Migration, up bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx) // does nothing if transaction is committed
+	}()
+
+	script := migration.Down
+	if up {
+		script = migration.Up
+	}
+	if _, err = tx.Exec(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if up {
+		_, err = tx.Exec(ctx, addMigrationQuery, migration.Version, migration.Name)
+	} else {
+		_, err = tx.Exec(ctx, deleteMigrationQuery, migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
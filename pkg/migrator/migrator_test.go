@@ -0,0 +1,81 @@
+package migrator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+type loadTest struct {
+	description string
+	files       fstest.MapFS
+	versions    []uint64
+	err         error
+}
+
+func TestLoad(t *testing.T) {
+	tests := []loadTest{
+		{
+			description: "migrations are sorted by version",
+			files: fstest.MapFS{
+				"0010_third.up.sql":    {Data: []byte("up 10")},
+				"0010_third.down.sql":  {Data: []byte("down 10")},
+				"0002_second.up.sql":   {Data: []byte("up 2")},
+				"0002_second.down.sql": {Data: []byte("down 2")},
+				"0001_first.up.sql":    {Data: []byte("up 1")},
+				"0001_first.down.sql":  {Data: []byte("down 1")},
+				"migrations.go":        {Data: []byte("package migrations")},
+			},
+			versions: []uint64{1, 2, 10},
+			err:      nil,
+		},
+		{
+			description: "migration without down script",
+			files: fstest.MapFS{
+				"0001_first.up.sql": {Data: []byte("up 1")},
+			},
+			versions: nil,
+			err:      ErrInvalidMigrations,
+		},
+		{
+			description: "two migrations with the same version",
+			files: fstest.MapFS{
+				"0001_first.up.sql":    {Data: []byte("up 1")},
+				"0001_first.down.sql":  {Data: []byte("down 1")},
+				"0001_second.up.sql":   {Data: []byte("up 2")},
+				"0001_second.down.sql": {Data: []byte("down 2")},
+			},
+			versions: nil,
+			err:      ErrInvalidMigrations,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			migrations, err := Load(test.files)
+			assert.ErrorIs(t, err, test.err)
+
+			var versions []uint64
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+			}
+			assert.Equal(t, test.versions, versions)
+		})
+	}
+}
+
+func TestLoadScripts(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"0001_init.up.sql":   {Data: []byte("CREATE TABLE t ();")},
+		"0001_init.down.sql": {Data: []byte("DROP TABLE t;")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{
+			Version: 1,
+			Name:    "init",
+			Up:      "CREATE TABLE t ();",
+			Down:    "DROP TABLE t;",
+		},
+	}, migrations)
+}